@@ -8,19 +8,67 @@ import (
 	"github.com/sbilibin2017/yp-metrics/internal/configs"
 )
 
-func parseFlags() *configs.AgentConfig {
+var configFileKeys = map[string]string{
+	"address":             "a",
+	"poll_interval":       "p",
+	"report_interval":     "r",
+	"rate_limit":          "l",
+	"log_level":           "log-level",
+	"protocol":            "protocol",
+	"grpc_address":        "grpc-address",
+	"key":                 "k",
+	"crypto_key":          "crypto-key",
+	"tls_ca":              "tls-ca",
+	"tls_cert":            "tls-cert",
+	"tls_key":             "tls-key",
+	"spool_dir":           "spool-dir",
+	"spool_max_size":      "spool-max-size",
+	"collectors":          "collectors",
+	"collectors_disabled": "collectors-disabled",
+	"collector_intervals": "collector-intervals",
+	"custom_collectors":   "custom-collectors",
+	"instance":            "instance",
+}
+
+func parseFlags() (*configs.AgentConfig, error) {
 	fs := flag.NewFlagSet("agent", flag.ExitOnError)
 
+	var configPath string
+	fs.StringVar(&configPath, "c", "", "path to JSON or YAML config file")
+
 	options := []configs.AgentOption{
 		withAddress(fs),
 		withPollInterval(fs),
 		withReportInterval(fs),
+		withRateLimit(fs),
 		withLogLevel(fs),
+		withProtocol(fs),
+		withGRPCAddress(fs),
+		withKey(fs),
+		withCryptoKey(fs),
+		withTLSCAFile(fs),
+		withTLSCertFile(fs),
+		withTLSKeyFile(fs),
+		withSpoolDir(fs),
+		withSpoolMaxSize(fs),
+		withCollectors(fs),
+		withDisabledCollectors(fs),
+		withCollectorIntervals(fs),
+		withCustomCollectorsPath(fs),
+		withInstance(fs),
 	}
 
 	fs.Parse(os.Args[1:])
 
-	return configs.NewAgentConfig(options...)
+	if env := os.Getenv("CONFIG"); env != "" {
+		configPath = env
+	}
+
+	if err := configs.ApplyConfigFile(fs, configPath, configFileKeys); err != nil {
+		return nil, err
+	}
+
+	return configs.NewAgentConfig(options...), nil
 }
 
 func withAddress(fs *flag.FlagSet) configs.AgentOption {
@@ -66,9 +114,24 @@ func withReportInterval(fs *flag.FlagSet) configs.AgentOption {
 	}
 }
 
+func withRateLimit(fs *flag.FlagSet) configs.AgentOption {
+	var limit int
+	fs.IntVar(&limit, "l", 1, "maximum number of concurrent outgoing report requests (log level moved to -log-level)")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("RATE_LIMIT"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.RateLimit = val
+				return
+			}
+		}
+		cfg.RateLimit = limit
+	}
+}
+
 func withLogLevel(fs *flag.FlagSet) configs.AgentOption {
 	var level string
-	fs.StringVar(&level, "l", "info", "log level")
+	fs.StringVar(&level, "log-level", "info", "log level")
 
 	return func(cfg *configs.AgentConfig) {
 		if env := os.Getenv("LOG_LEVEL"); env != "" {
@@ -78,3 +141,187 @@ func withLogLevel(fs *flag.FlagSet) configs.AgentOption {
 		}
 	}
 }
+
+func withProtocol(fs *flag.FlagSet) configs.AgentOption {
+	var protocol string
+	fs.StringVar(&protocol, "protocol", "http", "transport used to report metrics (http or grpc)")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("PROTOCOL"); env != "" {
+			cfg.Protocol = env
+		} else {
+			cfg.Protocol = protocol
+		}
+	}
+}
+
+func withGRPCAddress(fs *flag.FlagSet) configs.AgentOption {
+	var addr string
+	fs.StringVar(&addr, "grpc-address", "localhost:3200", "address of gRPC server")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("GRPC_ADDRESS"); env != "" {
+			cfg.GRPCAddress = env
+		} else {
+			cfg.GRPCAddress = addr
+		}
+	}
+}
+
+func withKey(fs *flag.FlagSet) configs.AgentOption {
+	var key string
+	fs.StringVar(&key, "k", "", "key for HMAC-SHA256 signing")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("KEY"); env != "" {
+			cfg.Key = env
+		} else {
+			cfg.Key = key
+		}
+	}
+}
+
+func withCryptoKey(fs *flag.FlagSet) configs.AgentOption {
+	var path string
+	fs.StringVar(&path, "crypto-key", "", "path to server RSA public key for encrypting payloads")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("CRYPTO_KEY"); env != "" {
+			cfg.CryptoKey = env
+		} else {
+			cfg.CryptoKey = path
+		}
+	}
+}
+
+func withTLSCAFile(fs *flag.FlagSet) configs.AgentOption {
+	var path string
+	fs.StringVar(&path, "tls-ca", "", "path to CA certificate for verifying the server (PEM)")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("TLS_CA"); env != "" {
+			cfg.TLSCAFile = env
+		} else {
+			cfg.TLSCAFile = path
+		}
+	}
+}
+
+func withTLSCertFile(fs *flag.FlagSet) configs.AgentOption {
+	var path string
+	fs.StringVar(&path, "tls-cert", "", "path to client TLS certificate (PEM)")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("TLS_CERT"); env != "" {
+			cfg.TLSCertFile = env
+		} else {
+			cfg.TLSCertFile = path
+		}
+	}
+}
+
+func withTLSKeyFile(fs *flag.FlagSet) configs.AgentOption {
+	var path string
+	fs.StringVar(&path, "tls-key", "", "path to client TLS private key (PEM)")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("TLS_KEY"); env != "" {
+			cfg.TLSKeyFile = env
+		} else {
+			cfg.TLSKeyFile = path
+		}
+	}
+}
+
+func withSpoolDir(fs *flag.FlagSet) configs.AgentOption {
+	var dir string
+	fs.StringVar(&dir, "spool-dir", "", "directory for undelivered batches (empty = disabled)")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("SPOOL_DIR"); env != "" {
+			cfg.SpoolDir = env
+		} else {
+			cfg.SpoolDir = dir
+		}
+	}
+}
+
+func withSpoolMaxSize(fs *flag.FlagSet) configs.AgentOption {
+	var size int
+	fs.IntVar(&size, "spool-max-size", 10<<20, "spool size limit in bytes, oldest batches are evicted first")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("SPOOL_MAX_SIZE"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.SpoolMaxSize = val
+				return
+			}
+		}
+		cfg.SpoolMaxSize = size
+	}
+}
+
+func withCollectors(fs *flag.FlagSet) configs.AgentOption {
+	var names string
+	fs.StringVar(&names, "collectors", "", "comma-separated list of enabled collectors (empty = all)")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("COLLECTORS"); env != "" {
+			cfg.Collectors = env
+		} else {
+			cfg.Collectors = names
+		}
+	}
+}
+
+func withDisabledCollectors(fs *flag.FlagSet) configs.AgentOption {
+	var names string
+	fs.StringVar(&names, "collectors-disabled", "", "comma-separated list of disabled collectors")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("COLLECTORS_DISABLED"); env != "" {
+			cfg.DisabledCollectors = env
+		} else {
+			cfg.DisabledCollectors = names
+		}
+	}
+}
+
+func withCollectorIntervals(fs *flag.FlagSet) configs.AgentOption {
+	var intervals string
+	fs.StringVar(&intervals, "collector-intervals", "", "per-collector poll intervals, e.g. system=10s,queue=1m")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("COLLECTOR_INTERVALS"); env != "" {
+			cfg.CollectorIntervals = env
+		} else {
+			cfg.CollectorIntervals = intervals
+		}
+	}
+}
+
+func withCustomCollectorsPath(fs *flag.FlagSet) configs.AgentOption {
+	var path string
+	fs.StringVar(&path, "custom-collectors", "", "path to JSON file with exec and file collectors")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("CUSTOM_COLLECTORS"); env != "" {
+			cfg.CustomCollectorsPath = env
+		} else {
+			cfg.CustomCollectorsPath = path
+		}
+	}
+}
+
+func withInstance(fs *flag.FlagSet) configs.AgentOption {
+	var instance string
+	fs.StringVar(&instance, "instance", "", "instance ID sent to the server (hostname by default)")
+
+	return func(cfg *configs.AgentConfig) {
+		if env := os.Getenv("INSTANCE"); env != "" {
+			cfg.Instance = env
+		} else {
+			cfg.Instance = instance
+		}
+	}
+}
@@ -3,17 +3,35 @@ package main
 import (
 	"flag"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sbilibin2017/yp-metrics/internal/configs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func resetAgentEnv() {
 	os.Unsetenv("ADDRESS")
 	os.Unsetenv("POLL_INTERVAL")
 	os.Unsetenv("REPORT_INTERVAL")
+	os.Unsetenv("RATE_LIMIT")
 	os.Unsetenv("LOG_LEVEL")
+	os.Unsetenv("PROTOCOL")
+	os.Unsetenv("GRPC_ADDRESS")
+	os.Unsetenv("KEY")
+	os.Unsetenv("CRYPTO_KEY")
+	os.Unsetenv("TLS_CA")
+	os.Unsetenv("TLS_CERT")
+	os.Unsetenv("TLS_KEY")
+	os.Unsetenv("SPOOL_DIR")
+	os.Unsetenv("SPOOL_MAX_SIZE")
+	os.Unsetenv("COLLECTORS")
+	os.Unsetenv("COLLECTORS_DISABLED")
+	os.Unsetenv("COLLECTOR_INTERVALS")
+	os.Unsetenv("CUSTOM_COLLECTORS")
+	os.Unsetenv("INSTANCE")
+	os.Unsetenv("CONFIG")
 }
 
 func TestAgentConfigOptions(t *testing.T) {
@@ -85,11 +103,31 @@ func TestAgentConfigOptions(t *testing.T) {
 				assert.Equal(t, 30, cfg.ReportInterval)
 			},
 		},
+		{
+			name:       "RateLimit from flag",
+			envKey:     "RATE_LIMIT",
+			envValue:   "",
+			flagArgs:   []string{"-l", "4"},
+			optionFunc: withRateLimit,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, 4, cfg.RateLimit)
+			},
+		},
+		{
+			name:       "RateLimit from env",
+			envKey:     "RATE_LIMIT",
+			envValue:   "8",
+			flagArgs:   []string{},
+			optionFunc: withRateLimit,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, 8, cfg.RateLimit)
+			},
+		},
 		{
 			name:       "LogLevel from flag",
 			envKey:     "LOG_LEVEL",
 			envValue:   "",
-			flagArgs:   []string{"-l", "debug"},
+			flagArgs:   []string{"-log-level", "debug"},
 			optionFunc: withLogLevel,
 			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
 				assert.Equal(t, "debug", cfg.LogLevel)
@@ -105,6 +143,286 @@ func TestAgentConfigOptions(t *testing.T) {
 				assert.Equal(t, "warn", cfg.LogLevel)
 			},
 		},
+		{
+			name:       "Protocol from flag",
+			envKey:     "PROTOCOL",
+			envValue:   "",
+			flagArgs:   []string{"-protocol", "grpc"},
+			optionFunc: withProtocol,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "grpc", cfg.Protocol)
+			},
+		},
+		{
+			name:       "Protocol from env",
+			envKey:     "PROTOCOL",
+			envValue:   "grpc",
+			flagArgs:   []string{},
+			optionFunc: withProtocol,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "grpc", cfg.Protocol)
+			},
+		},
+		{
+			name:       "GRPCAddress from flag",
+			envKey:     "GRPC_ADDRESS",
+			envValue:   "",
+			flagArgs:   []string{"-grpc-address", "flaghost:3200"},
+			optionFunc: withGRPCAddress,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "flaghost:3200", cfg.GRPCAddress)
+			},
+		},
+		{
+			name:       "GRPCAddress from env",
+			envKey:     "GRPC_ADDRESS",
+			envValue:   "envhost:3300",
+			flagArgs:   []string{},
+			optionFunc: withGRPCAddress,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "envhost:3300", cfg.GRPCAddress)
+			},
+		},
+		{
+			name:       "Key from flag",
+			envKey:     "KEY",
+			envValue:   "",
+			flagArgs:   []string{"-k", "flagkey"},
+			optionFunc: withKey,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "flagkey", cfg.Key)
+			},
+		},
+		{
+			name:       "Key from env",
+			envKey:     "KEY",
+			envValue:   "envkey",
+			flagArgs:   []string{},
+			optionFunc: withKey,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "envkey", cfg.Key)
+			},
+		},
+		{
+			name:       "CryptoKey from flag",
+			envKey:     "CRYPTO_KEY",
+			envValue:   "",
+			flagArgs:   []string{"-crypto-key", "/flag/key.pem"},
+			optionFunc: withCryptoKey,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/flag/key.pem", cfg.CryptoKey)
+			},
+		},
+		{
+			name:       "CryptoKey from env",
+			envKey:     "CRYPTO_KEY",
+			envValue:   "/env/key.pem",
+			flagArgs:   []string{},
+			optionFunc: withCryptoKey,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/env/key.pem", cfg.CryptoKey)
+			},
+		},
+		{
+			name:       "TLSCAFile from flag",
+			envKey:     "TLS_CA",
+			envValue:   "",
+			flagArgs:   []string{"-tls-ca", "/flag/tls-ca.pem"},
+			optionFunc: withTLSCAFile,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/flag/tls-ca.pem", cfg.TLSCAFile)
+			},
+		},
+		{
+			name:       "TLSCAFile from env",
+			envKey:     "TLS_CA",
+			envValue:   "/env/tls-ca.pem",
+			flagArgs:   []string{},
+			optionFunc: withTLSCAFile,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/env/tls-ca.pem", cfg.TLSCAFile)
+			},
+		},
+		{
+			name:       "TLSCertFile from flag",
+			envKey:     "TLS_CERT",
+			envValue:   "",
+			flagArgs:   []string{"-tls-cert", "/flag/tls-cert.pem"},
+			optionFunc: withTLSCertFile,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/flag/tls-cert.pem", cfg.TLSCertFile)
+			},
+		},
+		{
+			name:       "TLSCertFile from env",
+			envKey:     "TLS_CERT",
+			envValue:   "/env/tls-cert.pem",
+			flagArgs:   []string{},
+			optionFunc: withTLSCertFile,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/env/tls-cert.pem", cfg.TLSCertFile)
+			},
+		},
+		{
+			name:       "TLSKeyFile from flag",
+			envKey:     "TLS_KEY",
+			envValue:   "",
+			flagArgs:   []string{"-tls-key", "/flag/tls-key.pem"},
+			optionFunc: withTLSKeyFile,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/flag/tls-key.pem", cfg.TLSKeyFile)
+			},
+		},
+		{
+			name:       "TLSKeyFile from env",
+			envKey:     "TLS_KEY",
+			envValue:   "/env/tls-key.pem",
+			flagArgs:   []string{},
+			optionFunc: withTLSKeyFile,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/env/tls-key.pem", cfg.TLSKeyFile)
+			},
+		},
+		{
+			name:       "SpoolDir from flag",
+			envKey:     "SPOOL_DIR",
+			envValue:   "",
+			flagArgs:   []string{"-spool-dir", "/flag/spool"},
+			optionFunc: withSpoolDir,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/flag/spool", cfg.SpoolDir)
+			},
+		},
+		{
+			name:       "SpoolDir from env",
+			envKey:     "SPOOL_DIR",
+			envValue:   "/env/spool",
+			flagArgs:   []string{},
+			optionFunc: withSpoolDir,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/env/spool", cfg.SpoolDir)
+			},
+		},
+		{
+			name:       "SpoolMaxSize from flag",
+			envKey:     "SPOOL_MAX_SIZE",
+			envValue:   "",
+			flagArgs:   []string{"-spool-max-size", "2048"},
+			optionFunc: withSpoolMaxSize,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, 2048, cfg.SpoolMaxSize)
+			},
+		},
+		{
+			name:       "SpoolMaxSize from env",
+			envKey:     "SPOOL_MAX_SIZE",
+			envValue:   "4096",
+			flagArgs:   []string{},
+			optionFunc: withSpoolMaxSize,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, 4096, cfg.SpoolMaxSize)
+			},
+		},
+		{
+			name:       "Collectors from flag",
+			envKey:     "COLLECTORS",
+			envValue:   "",
+			flagArgs:   []string{"-collectors", "runtime"},
+			optionFunc: withCollectors,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "runtime", cfg.Collectors)
+			},
+		},
+		{
+			name:       "Collectors from env",
+			envKey:     "COLLECTORS",
+			envValue:   "system",
+			flagArgs:   []string{},
+			optionFunc: withCollectors,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "system", cfg.Collectors)
+			},
+		},
+		{
+			name:       "DisabledCollectors from flag",
+			envKey:     "COLLECTORS_DISABLED",
+			envValue:   "",
+			flagArgs:   []string{"-collectors-disabled", "system"},
+			optionFunc: withDisabledCollectors,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "system", cfg.DisabledCollectors)
+			},
+		},
+		{
+			name:       "DisabledCollectors from env",
+			envKey:     "COLLECTORS_DISABLED",
+			envValue:   "runtime",
+			flagArgs:   []string{},
+			optionFunc: withDisabledCollectors,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "runtime", cfg.DisabledCollectors)
+			},
+		},
+		{
+			name:       "CollectorIntervals from flag",
+			envKey:     "COLLECTOR_INTERVALS",
+			envValue:   "",
+			flagArgs:   []string{"-collector-intervals", "system=10s"},
+			optionFunc: withCollectorIntervals,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "system=10s", cfg.CollectorIntervals)
+			},
+		},
+		{
+			name:       "CollectorIntervals from env",
+			envKey:     "COLLECTOR_INTERVALS",
+			envValue:   "runtime=1m",
+			flagArgs:   []string{},
+			optionFunc: withCollectorIntervals,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "runtime=1m", cfg.CollectorIntervals)
+			},
+		},
+		{
+			name:       "CustomCollectorsPath from flag",
+			envKey:     "CUSTOM_COLLECTORS",
+			envValue:   "",
+			flagArgs:   []string{"-custom-collectors", "/flag/collectors.json"},
+			optionFunc: withCustomCollectorsPath,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/flag/collectors.json", cfg.CustomCollectorsPath)
+			},
+		},
+		{
+			name:       "CustomCollectorsPath from env",
+			envKey:     "CUSTOM_COLLECTORS",
+			envValue:   "/env/collectors.json",
+			flagArgs:   []string{},
+			optionFunc: withCustomCollectorsPath,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "/env/collectors.json", cfg.CustomCollectorsPath)
+			},
+		},
+		{
+			name:       "Instance from flag",
+			envKey:     "INSTANCE",
+			envValue:   "",
+			flagArgs:   []string{"-instance", "web01"},
+			optionFunc: withInstance,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "web01", cfg.Instance)
+			},
+		},
+		{
+			name:       "Instance from env",
+			envKey:     "INSTANCE",
+			envValue:   "web02",
+			flagArgs:   []string{},
+			optionFunc: withInstance,
+			assertFn: func(t *testing.T, cfg *configs.AgentConfig) {
+				assert.Equal(t, "web02", cfg.Instance)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -141,19 +459,25 @@ func TestParseFlagsEnvOverridesFlags(t *testing.T) {
 				"ADDRESS":         "http://env:8080",
 				"POLL_INTERVAL":   "11",
 				"REPORT_INTERVAL": "22",
+				"RATE_LIMIT":      "5",
 				"LOG_LEVEL":       "warn",
 			},
 			args: []string{
 				"-a", "http://flag:8080",
 				"-p", "1",
 				"-r", "2",
-				"-l", "info",
+				"-l", "3",
+				"-log-level", "info",
 			},
 			expected: &configs.AgentConfig{
 				Address:        "http://env:8080", // env wins
 				PollInterval:   11,                // env wins
 				ReportInterval: 22,                // env wins
+				RateLimit:      5,                 // env wins
 				LogLevel:       "warn",            // env wins
+				Protocol:       "http",
+				GRPCAddress:    "localhost:3200",
+				SpoolMaxSize:   10 << 20,
 			},
 		},
 		{
@@ -163,13 +487,18 @@ func TestParseFlagsEnvOverridesFlags(t *testing.T) {
 				"-a", "http://flag:8080",
 				"-p", "1",
 				"-r", "2",
-				"-l", "info",
+				"-l", "3",
+				"-log-level", "info",
 			},
 			expected: &configs.AgentConfig{
 				Address:        "http://flag:8080",
 				PollInterval:   1,
 				ReportInterval: 2,
+				RateLimit:      3,
 				LogLevel:       "info",
+				Protocol:       "http",
+				GRPCAddress:    "localhost:3200",
+				SpoolMaxSize:   10 << 20,
 			},
 		},
 		{
@@ -180,7 +509,11 @@ func TestParseFlagsEnvOverridesFlags(t *testing.T) {
 				Address:        "http://localhost:8080",
 				PollInterval:   2,
 				ReportInterval: 10,
+				RateLimit:      1,
 				LogLevel:       "info",
+				Protocol:       "http",
+				GRPCAddress:    "localhost:3200",
+				SpoolMaxSize:   10 << 20,
 			},
 		},
 	}
@@ -197,9 +530,65 @@ func TestParseFlagsEnvOverridesFlags(t *testing.T) {
 
 			os.Args = append([]string{"agent"}, tt.args...)
 
-			cfg := parseFlags()
+			cfg, err := parseFlags()
+			assert.NoError(t, err)
 
 			assert.Equal(t, tt.expected, cfg)
 		})
 	}
 }
+
+func TestParseFlagsConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "agent.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"address: http://file:8080\npoll_interval: 3s\nreport_interval: 1m\nprotocol: grpc\n",
+	), 0644))
+
+	unknownPath := filepath.Join(dir, "unknown.json")
+	require.NoError(t, os.WriteFile(unknownPath, []byte(`{"batch_size": 3}`), 0644))
+
+	t.Run("file < flags < env", func(t *testing.T) {
+		resetAgentEnv()
+		os.Setenv("REPORT_INTERVAL", "7")
+		defer resetAgentEnv()
+
+		origArgs := os.Args
+		defer func() { os.Args = origArgs }()
+		os.Args = []string{"agent", "-c", path, "-p", "4"}
+
+		cfg, err := parseFlags()
+		require.NoError(t, err)
+		assert.Equal(t, "http://file:8080", cfg.Address)
+		assert.Equal(t, 4, cfg.PollInterval)
+		assert.Equal(t, 7, cfg.ReportInterval)
+		assert.Equal(t, "grpc", cfg.Protocol)
+	})
+
+	t.Run("config path from env", func(t *testing.T) {
+		resetAgentEnv()
+		os.Setenv("CONFIG", path)
+		defer resetAgentEnv()
+
+		origArgs := os.Args
+		defer func() { os.Args = origArgs }()
+		os.Args = []string{"agent"}
+
+		cfg, err := parseFlags()
+		require.NoError(t, err)
+		assert.Equal(t, 3, cfg.PollInterval)
+		assert.Equal(t, 60, cfg.ReportInterval)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		resetAgentEnv()
+		origArgs := os.Args
+		defer func() { os.Args = origArgs }()
+		os.Args = []string{"agent", "-c", unknownPath}
+
+		cfg, err := parseFlags()
+		assert.ErrorIs(t, err, configs.ErrUnknownConfigKey)
+		assert.Nil(t, cfg)
+	})
+}
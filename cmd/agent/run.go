@@ -12,5 +12,6 @@ func run(ctx context.Context, config *configs.AgentConfig) error {
 	if err != nil {
 		return err
 	}
+	app.SetConfigLoader(parseFlags)
 	return app.Start(ctx)
 }
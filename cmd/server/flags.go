@@ -8,9 +8,39 @@ import (
 	"github.com/sbilibin2017/yp-metrics/internal/configs"
 )
 
-func parseFlags() *configs.ServerConfig {
+var configFileKeys = map[string]string{
+	"address":                  "a",
+	"store_interval":           "i",
+	"file_storage_path":        "f",
+	"restore":                  "r",
+	"database_dsn":             "d",
+	"log_level":                "l",
+	"alert_rules_path":         "alert-rules",
+	"alert_interval":           "alert-interval",
+	"history_file_path":        "history-file",
+	"history_size":             "history-size",
+	"history_retention":        "history-retention",
+	"grpc_address":             "grpc-address",
+	"key":                      "k",
+	"crypto_key":               "crypto-key",
+	"trusted_subnet":           "t",
+	"tls_cert":                 "tls-cert",
+	"tls_key":                  "tls-key",
+	"tls_client_ca":            "tls-client-ca",
+	"statsd_address":           "statsd-address",
+	"statsd_flush_interval":    "statsd-flush-interval",
+	"graphite_address":         "graphite-address",
+	"graphite_flush_interval":  "graphite-flush-interval",
+	"graphite_max_connections": "graphite-max-connections",
+	"set_window":               "set-window",
+}
+
+func parseFlags() (*configs.ServerConfig, error) {
 	fs := flag.NewFlagSet("server", flag.ExitOnError)
 
+	var configPath string
+	fs.StringVar(&configPath, "c", "", "path to JSON or YAML config file")
+
 	options := []configs.ServerOption{
 		withAddr(fs),
 		withStoreInterval(fs),
@@ -18,11 +48,37 @@ func parseFlags() *configs.ServerConfig {
 		withRestore(fs),
 		withDatabaseDSN(fs),
 		withLogLevel(fs),
+		withAlertRulesPath(fs),
+		withAlertInterval(fs),
+		withHistoryFilePath(fs),
+		withHistorySize(fs),
+		withHistoryRetention(fs),
+		withGRPCAddr(fs),
+		withKey(fs),
+		withCryptoKey(fs),
+		withTrustedSubnet(fs),
+		withTLSCertFile(fs),
+		withTLSKeyFile(fs),
+		withTLSClientCAFile(fs),
+		withStatsDAddr(fs),
+		withStatsDFlushInterval(fs),
+		withGraphiteAddr(fs),
+		withGraphiteFlushInterval(fs),
+		withGraphiteMaxConnections(fs),
+		withSetWindow(fs),
 	}
 
 	fs.Parse(os.Args[1:])
 
-	return configs.NewServerConfig(options...)
+	if env := os.Getenv("CONFIG"); env != "" {
+		configPath = env
+	}
+
+	if err := configs.ApplyConfigFile(fs, configPath, configFileKeys); err != nil {
+		return nil, err
+	}
+
+	return configs.NewServerConfig(options...), nil
 }
 
 func withAddr(fs *flag.FlagSet) configs.ServerOption {
@@ -106,3 +162,251 @@ func withLogLevel(fs *flag.FlagSet) configs.ServerOption {
 		}
 	}
 }
+
+func withAlertRulesPath(fs *flag.FlagSet) configs.ServerOption {
+	var path string
+	fs.StringVar(&path, "alert-rules", "", "path to JSON file with alert rules")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("ALERT_RULES_PATH"); env != "" {
+			cfg.AlertRulesPath = env
+		} else {
+			cfg.AlertRulesPath = path
+		}
+	}
+}
+
+func withAlertInterval(fs *flag.FlagSet) configs.ServerOption {
+	var interval int
+	fs.IntVar(&interval, "alert-interval", 10, "alert rules evaluation interval in seconds")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("ALERT_INTERVAL"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.AlertInterval = val
+				return
+			}
+		}
+		cfg.AlertInterval = interval
+	}
+}
+
+func withHistoryFilePath(fs *flag.FlagSet) configs.ServerOption {
+	var path string
+	fs.StringVar(&path, "history-file", "./data/history.json", "metric history file path")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("HISTORY_FILE_PATH"); env != "" {
+			cfg.HistoryFilePath = env
+		} else {
+			cfg.HistoryFilePath = path
+		}
+	}
+}
+
+func withHistorySize(fs *flag.FlagSet) configs.ServerOption {
+	var size int
+	fs.IntVar(&size, "history-size", 1000, "number of in-memory history samples kept per metric")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("HISTORY_SIZE"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.HistorySize = val
+				return
+			}
+		}
+		cfg.HistorySize = size
+	}
+}
+
+func withHistoryRetention(fs *flag.FlagSet) configs.ServerOption {
+	var retention int
+	fs.IntVar(&retention, "history-retention", 86400, "age in seconds after which file history samples are compacted away (0 = keep forever)")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("HISTORY_RETENTION"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.HistoryRetention = val
+				return
+			}
+		}
+		cfg.HistoryRetention = retention
+	}
+}
+
+func withGRPCAddr(fs *flag.FlagSet) configs.ServerOption {
+	var addr string
+	fs.StringVar(&addr, "grpc-address", "", "address and port to run gRPC server (empty = disabled)")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("GRPC_ADDRESS"); env != "" {
+			cfg.GRPCAddr = env
+		} else {
+			cfg.GRPCAddr = addr
+		}
+	}
+}
+
+func withKey(fs *flag.FlagSet) configs.ServerOption {
+	var key string
+	fs.StringVar(&key, "k", "", "key for HMAC-SHA256 signing")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("KEY"); env != "" {
+			cfg.Key = env
+		} else {
+			cfg.Key = key
+		}
+	}
+}
+
+func withCryptoKey(fs *flag.FlagSet) configs.ServerOption {
+	var path string
+	fs.StringVar(&path, "crypto-key", "", "path to RSA private key for decrypting agent payloads")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("CRYPTO_KEY"); env != "" {
+			cfg.CryptoKey = env
+		} else {
+			cfg.CryptoKey = path
+		}
+	}
+}
+
+func withTrustedSubnet(fs *flag.FlagSet) configs.ServerOption {
+	var subnet string
+	fs.StringVar(&subnet, "t", "", "trusted subnet in CIDR notation for metric updates")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("TRUSTED_SUBNET"); env != "" {
+			cfg.TrustedSubnet = env
+		} else {
+			cfg.TrustedSubnet = subnet
+		}
+	}
+}
+
+func withTLSCertFile(fs *flag.FlagSet) configs.ServerOption {
+	var path string
+	fs.StringVar(&path, "tls-cert", "", "path to server TLS certificate (PEM)")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("TLS_CERT"); env != "" {
+			cfg.TLSCertFile = env
+		} else {
+			cfg.TLSCertFile = path
+		}
+	}
+}
+
+func withTLSKeyFile(fs *flag.FlagSet) configs.ServerOption {
+	var path string
+	fs.StringVar(&path, "tls-key", "", "path to server TLS private key (PEM)")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("TLS_KEY"); env != "" {
+			cfg.TLSKeyFile = env
+		} else {
+			cfg.TLSKeyFile = path
+		}
+	}
+}
+
+func withTLSClientCAFile(fs *flag.FlagSet) configs.ServerOption {
+	var path string
+	fs.StringVar(&path, "tls-client-ca", "", "path to CA certificate for verifying client certificates (enables mTLS)")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("TLS_CLIENT_CA"); env != "" {
+			cfg.TLSClientCAFile = env
+		} else {
+			cfg.TLSClientCAFile = path
+		}
+	}
+}
+
+func withStatsDAddr(fs *flag.FlagSet) configs.ServerOption {
+	var addr string
+	fs.StringVar(&addr, "statsd-address", "", "UDP address for the StatsD listener (empty = disabled)")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("STATSD_ADDRESS"); env != "" {
+			cfg.StatsDAddr = env
+		} else {
+			cfg.StatsDAddr = addr
+		}
+	}
+}
+
+func withStatsDFlushInterval(fs *flag.FlagSet) configs.ServerOption {
+	var interval int
+	fs.IntVar(&interval, "statsd-flush-interval", 1, "StatsD batch flush interval in seconds")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("STATSD_FLUSH_INTERVAL"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.StatsDFlushInterval = val
+				return
+			}
+		}
+		cfg.StatsDFlushInterval = interval
+	}
+}
+
+func withGraphiteAddr(fs *flag.FlagSet) configs.ServerOption {
+	var addr string
+	fs.StringVar(&addr, "graphite-address", "", "TCP address for the Graphite plaintext listener (empty = disabled)")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("GRAPHITE_ADDRESS"); env != "" {
+			cfg.GraphiteAddr = env
+		} else {
+			cfg.GraphiteAddr = addr
+		}
+	}
+}
+
+func withGraphiteFlushInterval(fs *flag.FlagSet) configs.ServerOption {
+	var interval int
+	fs.IntVar(&interval, "graphite-flush-interval", 1, "Graphite batch flush interval in seconds")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("GRAPHITE_FLUSH_INTERVAL"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.GraphiteFlushInterval = val
+				return
+			}
+		}
+		cfg.GraphiteFlushInterval = interval
+	}
+}
+
+func withGraphiteMaxConnections(fs *flag.FlagSet) configs.ServerOption {
+	var maxConns int
+	fs.IntVar(&maxConns, "graphite-max-connections", 1000, "maximum number of concurrent Graphite connections")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("GRAPHITE_MAX_CONNECTIONS"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.GraphiteMaxConnections = val
+				return
+			}
+		}
+		cfg.GraphiteMaxConnections = maxConns
+	}
+}
+
+func withSetWindow(fs *flag.FlagSet) configs.ServerOption {
+	var window int
+	fs.IntVar(&window, "set-window", 0, "window in seconds after which set metrics are reset (0 = never)")
+
+	return func(cfg *configs.ServerConfig) {
+		if env := os.Getenv("SET_WINDOW"); env != "" {
+			if val, err := strconv.Atoi(env); err == nil {
+				cfg.SetWindow = val
+				return
+			}
+		}
+		cfg.SetWindow = window
+	}
+}
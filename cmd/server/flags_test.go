@@ -3,10 +3,12 @@ package main
 import (
 	"flag"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sbilibin2017/yp-metrics/internal/configs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func resetEnv() {
@@ -16,6 +18,25 @@ func resetEnv() {
 	os.Unsetenv("RESTORE")
 	os.Unsetenv("DATABASE_DSN")
 	os.Unsetenv("LOG_LEVEL")
+	os.Unsetenv("ALERT_RULES_PATH")
+	os.Unsetenv("ALERT_INTERVAL")
+	os.Unsetenv("HISTORY_FILE_PATH")
+	os.Unsetenv("HISTORY_SIZE")
+	os.Unsetenv("GRPC_ADDRESS")
+	os.Unsetenv("KEY")
+	os.Unsetenv("CRYPTO_KEY")
+	os.Unsetenv("TRUSTED_SUBNET")
+	os.Unsetenv("TLS_CERT")
+	os.Unsetenv("TLS_KEY")
+	os.Unsetenv("TLS_CLIENT_CA")
+	os.Unsetenv("STATSD_ADDRESS")
+	os.Unsetenv("STATSD_FLUSH_INTERVAL")
+	os.Unsetenv("GRAPHITE_ADDRESS")
+	os.Unsetenv("GRAPHITE_FLUSH_INTERVAL")
+	os.Unsetenv("GRAPHITE_MAX_CONNECTIONS")
+	os.Unsetenv("SET_WINDOW")
+	os.Unsetenv("HISTORY_RETENTION")
+	os.Unsetenv("CONFIG")
 }
 
 func TestServerConfigOptions(t *testing.T) {
@@ -147,6 +168,366 @@ func TestServerConfigOptions(t *testing.T) {
 				assert.Equal(t, "warn", cfg.LogLevel)
 			},
 		},
+		{
+			name:       "AlertRulesPath from flag",
+			envKey:     "ALERT_RULES_PATH",
+			envValue:   "",
+			flagArgs:   []string{"-alert-rules", "/flag/rules.json"},
+			optionFunc: withAlertRulesPath,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/flag/rules.json", cfg.AlertRulesPath)
+			},
+		},
+		{
+			name:       "AlertRulesPath from env",
+			envKey:     "ALERT_RULES_PATH",
+			envValue:   "/env/rules.json",
+			flagArgs:   []string{},
+			optionFunc: withAlertRulesPath,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/env/rules.json", cfg.AlertRulesPath)
+			},
+		},
+		{
+			name:       "AlertInterval from flag",
+			envKey:     "ALERT_INTERVAL",
+			envValue:   "",
+			flagArgs:   []string{"-alert-interval", "5"},
+			optionFunc: withAlertInterval,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 5, cfg.AlertInterval)
+			},
+		},
+		{
+			name:       "AlertInterval from env",
+			envKey:     "ALERT_INTERVAL",
+			envValue:   "15",
+			flagArgs:   []string{},
+			optionFunc: withAlertInterval,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 15, cfg.AlertInterval)
+			},
+		},
+		{
+			name:       "HistoryFilePath from flag",
+			envKey:     "HISTORY_FILE_PATH",
+			envValue:   "",
+			flagArgs:   []string{"-history-file", "/flag/history.json"},
+			optionFunc: withHistoryFilePath,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/flag/history.json", cfg.HistoryFilePath)
+			},
+		},
+		{
+			name:       "HistoryFilePath from env",
+			envKey:     "HISTORY_FILE_PATH",
+			envValue:   "/env/history.json",
+			flagArgs:   []string{},
+			optionFunc: withHistoryFilePath,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/env/history.json", cfg.HistoryFilePath)
+			},
+		},
+		{
+			name:       "HistorySize from flag",
+			envKey:     "HISTORY_SIZE",
+			envValue:   "",
+			flagArgs:   []string{"-history-size", "50"},
+			optionFunc: withHistorySize,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 50, cfg.HistorySize)
+			},
+		},
+		{
+			name:       "HistorySize from env",
+			envKey:     "HISTORY_SIZE",
+			envValue:   "500",
+			flagArgs:   []string{},
+			optionFunc: withHistorySize,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 500, cfg.HistorySize)
+			},
+		},
+		{
+			name:       "HistoryRetention from flag",
+			envKey:     "HISTORY_RETENTION",
+			envValue:   "",
+			flagArgs:   []string{"-history-retention", "3600"},
+			optionFunc: withHistoryRetention,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 3600, cfg.HistoryRetention)
+			},
+		},
+		{
+			name:       "HistoryRetention from env",
+			envKey:     "HISTORY_RETENTION",
+			envValue:   "60",
+			flagArgs:   []string{},
+			optionFunc: withHistoryRetention,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 60, cfg.HistoryRetention)
+			},
+		},
+		{
+			name:       "GRPCAddr from flag",
+			envKey:     "GRPC_ADDRESS",
+			envValue:   "",
+			flagArgs:   []string{"-grpc-address", ":3200"},
+			optionFunc: withGRPCAddr,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, ":3200", cfg.GRPCAddr)
+			},
+		},
+		{
+			name:       "GRPCAddr from env",
+			envKey:     "GRPC_ADDRESS",
+			envValue:   ":3300",
+			flagArgs:   []string{},
+			optionFunc: withGRPCAddr,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, ":3300", cfg.GRPCAddr)
+			},
+		},
+		{
+			name:       "Key from flag",
+			envKey:     "KEY",
+			envValue:   "",
+			flagArgs:   []string{"-k", "flagkey"},
+			optionFunc: withKey,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "flagkey", cfg.Key)
+			},
+		},
+		{
+			name:       "Key from env",
+			envKey:     "KEY",
+			envValue:   "envkey",
+			flagArgs:   []string{},
+			optionFunc: withKey,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "envkey", cfg.Key)
+			},
+		},
+		{
+			name:       "CryptoKey from flag",
+			envKey:     "CRYPTO_KEY",
+			envValue:   "",
+			flagArgs:   []string{"-crypto-key", "/flag/key.pem"},
+			optionFunc: withCryptoKey,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/flag/key.pem", cfg.CryptoKey)
+			},
+		},
+		{
+			name:       "CryptoKey from env",
+			envKey:     "CRYPTO_KEY",
+			envValue:   "/env/key.pem",
+			flagArgs:   []string{},
+			optionFunc: withCryptoKey,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/env/key.pem", cfg.CryptoKey)
+			},
+		},
+		{
+			name:       "TrustedSubnet from flag",
+			envKey:     "TRUSTED_SUBNET",
+			envValue:   "",
+			flagArgs:   []string{"-t", "10.0.0.0/8"},
+			optionFunc: withTrustedSubnet,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "10.0.0.0/8", cfg.TrustedSubnet)
+			},
+		},
+		{
+			name:       "TrustedSubnet from env",
+			envKey:     "TRUSTED_SUBNET",
+			envValue:   "192.168.0.0/16",
+			flagArgs:   []string{},
+			optionFunc: withTrustedSubnet,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "192.168.0.0/16", cfg.TrustedSubnet)
+			},
+		},
+		{
+			name:       "TLSCertFile from flag",
+			envKey:     "TLS_CERT",
+			envValue:   "",
+			flagArgs:   []string{"-tls-cert", "/flag/tls-cert.pem"},
+			optionFunc: withTLSCertFile,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/flag/tls-cert.pem", cfg.TLSCertFile)
+			},
+		},
+		{
+			name:       "TLSCertFile from env",
+			envKey:     "TLS_CERT",
+			envValue:   "/env/tls-cert.pem",
+			flagArgs:   []string{},
+			optionFunc: withTLSCertFile,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/env/tls-cert.pem", cfg.TLSCertFile)
+			},
+		},
+		{
+			name:       "TLSKeyFile from flag",
+			envKey:     "TLS_KEY",
+			envValue:   "",
+			flagArgs:   []string{"-tls-key", "/flag/tls-key.pem"},
+			optionFunc: withTLSKeyFile,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/flag/tls-key.pem", cfg.TLSKeyFile)
+			},
+		},
+		{
+			name:       "TLSKeyFile from env",
+			envKey:     "TLS_KEY",
+			envValue:   "/env/tls-key.pem",
+			flagArgs:   []string{},
+			optionFunc: withTLSKeyFile,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/env/tls-key.pem", cfg.TLSKeyFile)
+			},
+		},
+		{
+			name:       "TLSClientCAFile from flag",
+			envKey:     "TLS_CLIENT_CA",
+			envValue:   "",
+			flagArgs:   []string{"-tls-client-ca", "/flag/tls-client-ca.pem"},
+			optionFunc: withTLSClientCAFile,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/flag/tls-client-ca.pem", cfg.TLSClientCAFile)
+			},
+		},
+		{
+			name:       "TLSClientCAFile from env",
+			envKey:     "TLS_CLIENT_CA",
+			envValue:   "/env/tls-client-ca.pem",
+			flagArgs:   []string{},
+			optionFunc: withTLSClientCAFile,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, "/env/tls-client-ca.pem", cfg.TLSClientCAFile)
+			},
+		},
+		{
+			name:       "StatsDAddr from flag",
+			envKey:     "STATSD_ADDRESS",
+			envValue:   "",
+			flagArgs:   []string{"-statsd-address", ":8125"},
+			optionFunc: withStatsDAddr,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, ":8125", cfg.StatsDAddr)
+			},
+		},
+		{
+			name:       "StatsDAddr from env",
+			envKey:     "STATSD_ADDRESS",
+			envValue:   ":9125",
+			flagArgs:   []string{},
+			optionFunc: withStatsDAddr,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, ":9125", cfg.StatsDAddr)
+			},
+		},
+		{
+			name:       "StatsDFlushInterval from flag",
+			envKey:     "STATSD_FLUSH_INTERVAL",
+			envValue:   "",
+			flagArgs:   []string{"-statsd-flush-interval", "5"},
+			optionFunc: withStatsDFlushInterval,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 5, cfg.StatsDFlushInterval)
+			},
+		},
+		{
+			name:       "StatsDFlushInterval from env",
+			envKey:     "STATSD_FLUSH_INTERVAL",
+			envValue:   "7",
+			flagArgs:   []string{},
+			optionFunc: withStatsDFlushInterval,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 7, cfg.StatsDFlushInterval)
+			},
+		},
+		{
+			name:       "GraphiteAddr from flag",
+			envKey:     "GRAPHITE_ADDRESS",
+			envValue:   "",
+			flagArgs:   []string{"-graphite-address", ":2003"},
+			optionFunc: withGraphiteAddr,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, ":2003", cfg.GraphiteAddr)
+			},
+		},
+		{
+			name:       "GraphiteAddr from env",
+			envKey:     "GRAPHITE_ADDRESS",
+			envValue:   ":2004",
+			flagArgs:   []string{},
+			optionFunc: withGraphiteAddr,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, ":2004", cfg.GraphiteAddr)
+			},
+		},
+		{
+			name:       "GraphiteFlushInterval from flag",
+			envKey:     "GRAPHITE_FLUSH_INTERVAL",
+			envValue:   "",
+			flagArgs:   []string{"-graphite-flush-interval", "5"},
+			optionFunc: withGraphiteFlushInterval,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 5, cfg.GraphiteFlushInterval)
+			},
+		},
+		{
+			name:       "GraphiteFlushInterval from env",
+			envKey:     "GRAPHITE_FLUSH_INTERVAL",
+			envValue:   "7",
+			flagArgs:   []string{},
+			optionFunc: withGraphiteFlushInterval,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 7, cfg.GraphiteFlushInterval)
+			},
+		},
+		{
+			name:       "GraphiteMaxConnections from flag",
+			envKey:     "GRAPHITE_MAX_CONNECTIONS",
+			envValue:   "",
+			flagArgs:   []string{"-graphite-max-connections", "50"},
+			optionFunc: withGraphiteMaxConnections,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 50, cfg.GraphiteMaxConnections)
+			},
+		},
+		{
+			name:       "GraphiteMaxConnections from env",
+			envKey:     "GRAPHITE_MAX_CONNECTIONS",
+			envValue:   "70",
+			flagArgs:   []string{},
+			optionFunc: withGraphiteMaxConnections,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 70, cfg.GraphiteMaxConnections)
+			},
+		},
+		{
+			name:       "SetWindow from flag",
+			envKey:     "SET_WINDOW",
+			envValue:   "",
+			flagArgs:   []string{"-set-window", "3600"},
+			optionFunc: withSetWindow,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 3600, cfg.SetWindow)
+			},
+		},
+		{
+			name:       "SetWindow from env",
+			envKey:     "SET_WINDOW",
+			envValue:   "60",
+			flagArgs:   []string{},
+			optionFunc: withSetWindow,
+			assertFn: func(t *testing.T, cfg *configs.ServerConfig) {
+				assert.Equal(t, 60, cfg.SetWindow)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -196,12 +577,19 @@ func Test_parseFlags_envOverrideFlags(t *testing.T) {
 				"-l", "debug",
 			},
 			expected: &configs.ServerConfig{
-				Addr:            "env:127.0.0.1:9999", // env wins
-				StoreInterval:   111,                  // env wins
-				FileStoragePath: "/env/path.json",     // env wins
-				Restore:         true,                 // env wins
-				DatabaseDSN:     "env_dsn",            // env wins
-				LogLevel:        "warn",               // env wins
+				Addr:                   "env:127.0.0.1:9999", // env wins
+				StoreInterval:          111,                  // env wins
+				FileStoragePath:        "/env/path.json",     // env wins
+				Restore:                true,                 // env wins
+				DatabaseDSN:            "env_dsn",            // env wins
+				LogLevel:               "warn",               // env wins
+				AlertInterval:          10,
+				HistoryFilePath:        "./data/history.json",
+				HistorySize:            1000,
+				HistoryRetention:       86400,
+				StatsDFlushInterval:    1,
+				GraphiteFlushInterval:  1,
+				GraphiteMaxConnections: 1000,
 			},
 		},
 		{
@@ -216,12 +604,19 @@ func Test_parseFlags_envOverrideFlags(t *testing.T) {
 				"-l", "debug",
 			},
 			expected: &configs.ServerConfig{
-				Addr:            "flag:localhost:9000",
-				StoreInterval:   42,
-				FileStoragePath: "/flag/file.json",
-				Restore:         false,
-				DatabaseDSN:     "flag_dsn",
-				LogLevel:        "debug",
+				Addr:                   "flag:localhost:9000",
+				StoreInterval:          42,
+				FileStoragePath:        "/flag/file.json",
+				Restore:                false,
+				DatabaseDSN:            "flag_dsn",
+				LogLevel:               "debug",
+				AlertInterval:          10,
+				HistoryFilePath:        "./data/history.json",
+				HistorySize:            1000,
+				HistoryRetention:       86400,
+				StatsDFlushInterval:    1,
+				GraphiteFlushInterval:  1,
+				GraphiteMaxConnections: 1000,
 			},
 		},
 		{
@@ -229,12 +624,19 @@ func Test_parseFlags_envOverrideFlags(t *testing.T) {
 			env:  map[string]string{},
 			args: []string{},
 			expected: &configs.ServerConfig{
-				Addr:            ":8080",
-				StoreInterval:   300,
-				FileStoragePath: "./data/metrics.json",
-				Restore:         true,
-				DatabaseDSN:     "",
-				LogLevel:        "info",
+				Addr:                   ":8080",
+				StoreInterval:          300,
+				FileStoragePath:        "./data/metrics.json",
+				Restore:                true,
+				DatabaseDSN:            "",
+				LogLevel:               "info",
+				AlertInterval:          10,
+				HistoryFilePath:        "./data/history.json",
+				HistorySize:            1000,
+				HistoryRetention:       86400,
+				StatsDFlushInterval:    1,
+				GraphiteFlushInterval:  1,
+				GraphiteMaxConnections: 1000,
 			},
 		},
 	}
@@ -251,9 +653,71 @@ func Test_parseFlags_envOverrideFlags(t *testing.T) {
 
 			os.Args = append([]string{"server"}, tt.args...)
 
-			cfg := parseFlags()
+			cfg, err := parseFlags()
+			assert.NoError(t, err)
 
 			assert.Equal(t, tt.expected, cfg)
 		})
 	}
 }
+
+func Test_parseFlags_configFile(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`{
+		"address": "file:9000",
+		"store_interval": "1m",
+		"restore": false,
+		"log_level": "debug"
+	}`), 0644))
+
+	yamlPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte("address: yaml:9000\nstore_interval: 5s\n"), 0644))
+
+	unknownPath := filepath.Join(dir, "unknown.json")
+	require.NoError(t, os.WriteFile(unknownPath, []byte(`{"store_file": "/tmp/metrics.json"}`), 0644))
+
+	t.Run("file values", func(t *testing.T) {
+		resetEnv()
+		origArgs := os.Args
+		defer func() { os.Args = origArgs }()
+		os.Args = []string{"server", "-c", jsonPath}
+
+		cfg, err := parseFlags()
+		require.NoError(t, err)
+		assert.Equal(t, "file:9000", cfg.Addr)
+		assert.Equal(t, 60, cfg.StoreInterval)
+		assert.False(t, cfg.Restore)
+		assert.Equal(t, "debug", cfg.LogLevel)
+		assert.Equal(t, "./data/metrics.json", cfg.FileStoragePath)
+	})
+
+	t.Run("file < flags < env", func(t *testing.T) {
+		resetEnv()
+		os.Setenv("LOG_LEVEL", "warn")
+		os.Setenv("CONFIG", yamlPath)
+		defer resetEnv()
+
+		origArgs := os.Args
+		defer func() { os.Args = origArgs }()
+		os.Args = []string{"server", "-c", jsonPath, "-a", "flag:9000", "-l", "error"}
+
+		cfg, err := parseFlags()
+		require.NoError(t, err)
+		assert.Equal(t, "flag:9000", cfg.Addr) // флаг важнее файла
+		assert.Equal(t, 5, cfg.StoreInterval)  // CONFIG из env важнее -c
+		assert.Equal(t, "warn", cfg.LogLevel)  // env важнее флага
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		resetEnv()
+		origArgs := os.Args
+		defer func() { os.Args = origArgs }()
+		os.Args = []string{"server", "-c", unknownPath}
+
+		cfg, err := parseFlags()
+		assert.ErrorIs(t, err, configs.ErrUnknownConfigKey)
+		assert.Nil(t, cfg)
+	})
+}
@@ -7,8 +7,12 @@ import (
 )
 
 func main() {
-	config := parseFlags()
-	err := run(context.Background(), config)
+	config, err := parseFlags()
+	if err != nil {
+		panic(err)
+	}
+
+	err = run(context.Background(), config)
 	if err != nil {
 		panic(err)
 	}
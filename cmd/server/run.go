@@ -12,5 +12,6 @@ func run(ctx context.Context, config *configs.ServerConfig) error {
 	if err != nil {
 		return err
 	}
+	app.SetConfigLoader(parseFlags)
 	return app.Start(ctx)
 }
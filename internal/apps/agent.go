@@ -2,19 +2,49 @@ package apps
 
 import (
 	"context"
+	"crypto/rsa"
+	"errors"
+	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/sbilibin2017/yp-metrics/internal/configs"
 	"github.com/sbilibin2017/yp-metrics/internal/facades"
+	"github.com/sbilibin2017/yp-metrics/internal/interceptors"
 	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
 	"github.com/sbilibin2017/yp-metrics/internal/workers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+var (
+	ErrUnknownProtocol      = errors.New("unknown agent protocol")
+	ErrCryptoKeyRequiresTLS = errors.New("crypto key is not supported over gRPC without TLS")
+	ErrInvalidSpoolMaxSize  = errors.New("spool max size must be positive")
 )
 
 type AgentApp struct {
-	config  *configs.AgentConfig
-	workers []func(ctx context.Context)
+	config             *configs.AgentConfig
+	configLoader       func() (*configs.AgentConfig, error)
+	registry           *workers.CollectorRegistry
+	collectors         []workers.Collector
+	collectorIntervals map[string]time.Duration
+	workers            []func(ctx context.Context)
+	closers            []func()
+	pollIntervalCh     chan int
+	reportIntervalCh   chan int
 }
 
 func NewAgentApp(cfg *configs.AgentConfig) (*AgentApp, error) {
@@ -22,32 +52,265 @@ func NewAgentApp(cfg *configs.AgentConfig) (*AgentApp, error) {
 		return nil, err
 	}
 
-	client := resty.New()
-	metricFacade := facades.NewMetricUpdateFacade(client, cfg.Address)
+	collectorIntervals, err := parseCollectorIntervals(cfg.CollectorIntervals)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := workers.NewCollectorRegistry()
+	collectors := []workers.Collector{workers.NewRuntimeCollector(), workers.NewSystemCollector()}
+	if cfg.CustomCollectorsPath != "" {
+		custom, err := newCustomCollectors(cfg.CustomCollectorsPath)
+		if err != nil {
+			return nil, err
+		}
+		collectors = append(collectors, custom...)
+	}
+
+	for _, c := range collectors {
+		if err := registry.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	tlsConfig, err := newClientTLSConfig(cfg.TLSCAFile, cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		metricFacade workers.MetricsUpdater
+		closers      []func()
+	)
+
+	instance := cfg.Instance
+	if instance == "" {
+		if hostname, err := os.Hostname(); err == nil {
+			instance = hostname
+		}
+	}
+
+	switch cfg.Protocol {
+	case ProtocolHTTP, "":
+		var publicKey *rsa.PublicKey
+		if cfg.CryptoKey != "" {
+			key, err := newPublicKey(cfg.CryptoKey)
+			if err != nil {
+				return nil, err
+			}
+			publicKey = key
+		}
+
+		address := cfg.Address
+		client := resty.New()
+		if tlsConfig != nil {
+			if strings.HasPrefix(address, "http://") {
+				logger.Log.Errorw("TLS is configured but server address uses plain HTTP", "address", address)
+				return nil, ErrTLSPlainAddress
+			}
+			client.SetTLSClientConfig(tlsConfig)
+			if !strings.HasPrefix(address, "https://") {
+				address = "https://" + address
+			}
+		}
+
+		metricFacade = facades.NewMetricUpdateFacade(client, address, cfg.Key, publicKey, instance)
+		logger.Log.Infow("Using HTTP transport", "address", address, "instance", instance)
+
+	case ProtocolGRPC:
+		if cfg.CryptoKey != "" {
+			if tlsConfig == nil {
+				logger.Log.Errorw("Crypto key is set but gRPC channel is not encrypted, configure TLS", "address", cfg.GRPCAddress)
+				return nil, ErrCryptoKeyRequiresTLS
+			}
+			logger.Log.Infow("gRPC payloads are encrypted by TLS, crypto key is not used", "address", cfg.GRPCAddress)
+		}
+
+		creds := insecure.NewCredentials()
+		if tlsConfig != nil {
+			creds = credentials.NewTLS(tlsConfig)
+		}
+
+		conn, err := grpc.NewClient(
+			cfg.GRPCAddress,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithChainUnaryInterceptor(interceptors.HashUnaryClientInterceptor(cfg.Key)),
+			grpc.WithChainStreamInterceptor(interceptors.HashStreamClientInterceptor(cfg.Key)),
+		)
+		if err != nil {
+			logger.Log.Errorw("Failed to create gRPC client", "address", cfg.GRPCAddress, "error", err)
+			return nil, err
+		}
+		grpcFacade := facades.NewMetricUpdateGRPCFacade(pb.NewMetricServiceClient(conn), cfg.GRPCAddress, instance)
+		metricFacade = grpcFacade
+		closers = append(closers, grpcFacade.Close, func() { conn.Close() })
+		logger.Log.Infow("Using gRPC transport", "address", cfg.GRPCAddress, "instance", instance)
+
+	default:
+		return nil, ErrUnknownProtocol
+	}
+
+	if cfg.SpoolDir != "" {
+		if cfg.SpoolMaxSize <= 0 {
+			logger.Log.Errorw("Invalid spool max size", "maxSize", cfg.SpoolMaxSize)
+			return nil, ErrInvalidSpoolMaxSize
+		}
+		metricFacade = facades.NewMetricUpdateSpoolFacade(metricFacade, cfg.SpoolDir, int64(cfg.SpoolMaxSize))
+		logger.Log.Infow("Spooling undelivered batches", "dir", cfg.SpoolDir, "maxSize", cfg.SpoolMaxSize)
+	}
+
+	pollIntervalCh := make(chan int, 1)
+	reportIntervalCh := make(chan int, 1)
+
+	app := &AgentApp{
+		config:             cfg,
+		registry:           registry,
+		collectorIntervals: collectorIntervals,
+		closers:            closers,
+		pollIntervalCh:     pollIntervalCh,
+		reportIntervalCh:   reportIntervalCh,
+	}
 
-	workersList := []func(ctx context.Context){
+	app.workers = []func(ctx context.Context){
 		func(ctx context.Context) {
-			workers.StartMetricAgentWorker(ctx, metricFacade, cfg.PollInterval, cfg.ReportInterval)
+			workers.StartMetricAgentWorker(
+				ctx,
+				metricFacade,
+				app.collectors,
+				cfg.PollInterval,
+				cfg.ReportInterval,
+				cfg.RateLimit,
+				pollIntervalCh,
+				reportIntervalCh,
+			)
 		},
 	}
 
-	return &AgentApp{
-		config:  cfg,
-		workers: workersList,
-	}, nil
+	return app, nil
+}
+
+func (a *AgentApp) SetConfigLoader(loader func() (*configs.AgentConfig, error)) {
+	a.configLoader = loader
+}
+
+func (a *AgentApp) RegisterCollector(c workers.Collector) error {
+	return a.registry.Register(c)
 }
 
 func (a *AgentApp) Start(ctx context.Context) error {
+	collectors, err := a.registry.Select(
+		parseCollectorNames(a.config.Collectors),
+		parseCollectorNames(a.config.DisabledCollectors),
+		a.collectorIntervals,
+	)
+	if err != nil {
+		logger.Log.Errorw("Failed to select collectors", "error", err)
+		return err
+	}
+	a.collectors = collectors
+
 	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	defer stop()
 
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hupCh:
+				a.reload()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
 	for _, worker := range a.workers {
-		go worker(ctx)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker(ctx)
+		}()
 	}
 
 	<-ctx.Done()
 
 	logger.Log.Info("Shutdown signal received, stopping agent")
 
+	wg.Wait()
+
+	for _, closeFn := range a.closers {
+		closeFn()
+	}
+
 	return ctx.Err()
 }
+
+func (a *AgentApp) reload() {
+	logger.Log.Info("SIGHUP received, reloading configuration")
+
+	if a.configLoader == nil {
+		logger.Log.Warn("Configuration reload is not supported")
+		return
+	}
+
+	cfg, err := a.configLoader()
+	if err != nil {
+		logger.Log.Errorw("Failed to reload configuration", "error", err)
+		return
+	}
+
+	for _, field := range changedConfigFields(a.config, cfg, "LogLevel", "PollInterval", "ReportInterval") {
+		logger.Log.Warnw("Setting changed but requires restart to apply", "setting", field)
+	}
+
+	if cfg.LogLevel != a.config.LogLevel {
+		if err := logger.SetLevel(cfg.LogLevel); err != nil {
+			logger.Log.Errorw("Failed to apply log level", "level", cfg.LogLevel, "error", err)
+		} else {
+			a.config.LogLevel = cfg.LogLevel
+			logger.Log.Infow("Log level applied", "level", cfg.LogLevel)
+		}
+	}
+
+	if cfg.PollInterval != a.config.PollInterval {
+		if cfg.PollInterval <= 0 {
+			logger.Log.Errorw("Invalid poll interval", "pollInterval", cfg.PollInterval)
+		} else {
+			a.config.PollInterval = cfg.PollInterval
+			sendInterval(a.pollIntervalCh, cfg.PollInterval)
+			logger.Log.Infow("Poll interval applied", "pollInterval", cfg.PollInterval)
+		}
+	}
+
+	if cfg.ReportInterval != a.config.ReportInterval {
+		if cfg.ReportInterval <= 0 {
+			logger.Log.Errorw("Invalid report interval", "reportInterval", cfg.ReportInterval)
+		} else {
+			a.config.ReportInterval = cfg.ReportInterval
+			sendInterval(a.reportIntervalCh, cfg.ReportInterval)
+			logger.Log.Infow("Report interval applied", "reportInterval", cfg.ReportInterval)
+		}
+	}
+}
+
+func newPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Log.Errorw("Failed to read public key", "path", path, "error", err)
+		return nil, err
+	}
+
+	key, err := types.ParseRSAPublicKeyPEM(data)
+	if err != nil {
+		logger.Log.Errorw("Failed to parse public key", "path", path, "error", err)
+		return nil, err
+	}
+
+	logger.Log.Infow("Payload encryption enabled", "path", path)
+
+	return key, nil
+}
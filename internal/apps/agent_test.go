@@ -2,11 +2,19 @@ package apps
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/sbilibin2017/yp-metrics/internal/configs"
+	"github.com/sbilibin2017/yp-metrics/internal/workers"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewAgentApp_Success(t *testing.T) {
@@ -50,3 +58,178 @@ func TestAgentApp_Start_Shutdown(t *testing.T) {
 	err = <-done
 	assert.ErrorIs(t, err, context.Canceled)
 }
+
+func TestNewAgentApp_GRPC(t *testing.T) {
+	cfg := &configs.AgentConfig{
+		PollInterval:   1,
+		ReportInterval: 1,
+		LogLevel:       "info",
+		Protocol:       ProtocolGRPC,
+		GRPCAddress:    "localhost:3200",
+	}
+
+	app, err := NewAgentApp(cfg)
+	assert.NoError(t, err)
+	assert.NotNil(t, app)
+	assert.Len(t, app.workers, 1)
+	assert.Len(t, app.closers, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	err = <-done
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewAgentApp_GRPCCryptoKeyWithoutTLS(t *testing.T) {
+	cfg := &configs.AgentConfig{
+		PollInterval:   1,
+		ReportInterval: 1,
+		LogLevel:       "info",
+		Protocol:       ProtocolGRPC,
+		GRPCAddress:    "localhost:3200",
+		CryptoKey:      "public.pem",
+	}
+
+	// без TLS полезная нагрузка ушла бы в открытом виде
+	app, err := NewAgentApp(cfg)
+	assert.ErrorIs(t, err, ErrCryptoKeyRequiresTLS)
+	assert.Nil(t, app)
+}
+
+func TestNewAgentApp_InvalidSpoolMaxSize(t *testing.T) {
+	cfg := &configs.AgentConfig{
+		PollInterval:   1,
+		ReportInterval: 1,
+		LogLevel:       "info",
+		Protocol:       ProtocolHTTP,
+		Address:        "localhost:8080",
+		SpoolDir:       t.TempDir(),
+	}
+
+	// спул без ограничения размера рос бы, пока сервер недоступен
+	app, err := NewAgentApp(cfg)
+	assert.ErrorIs(t, err, ErrInvalidSpoolMaxSize)
+	assert.Nil(t, app)
+}
+
+func TestNewAgentApp_UnknownProtocol(t *testing.T) {
+	cfg := &configs.AgentConfig{
+		PollInterval:   1,
+		ReportInterval: 1,
+		LogLevel:       "info",
+		Protocol:       "udp",
+	}
+
+	app, err := NewAgentApp(cfg)
+	assert.ErrorIs(t, err, ErrUnknownProtocol)
+	assert.Nil(t, app)
+}
+
+func TestNewAgentApp_CryptoKey(t *testing.T) {
+	dir := t.TempDir()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes}), 0600))
+
+	invalidPath := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidPath, []byte("not a key"), 0600))
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "valid key", path: keyPath},
+		{name: "invalid key", path: invalidPath, wantErr: true},
+		{name: "missing key", path: filepath.Join(dir, "missing.pem"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, err := NewAgentApp(&configs.AgentConfig{
+				Address:        "http://localhost:8080",
+				PollInterval:   1,
+				ReportInterval: 1,
+				LogLevel:       "info",
+				CryptoKey:      tt.path,
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, app)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, app)
+		})
+	}
+}
+
+func TestAgentApp_Collectors(t *testing.T) {
+	newConfig := func() *configs.AgentConfig {
+		return &configs.AgentConfig{
+			Address:        "http://localhost:8080",
+			PollInterval:   1,
+			ReportInterval: 1,
+			LogLevel:       "info",
+		}
+	}
+
+	t.Run("custom collector registered and selected", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Collectors = "queue, runtime"
+		cfg.CollectorIntervals = "queue=5s"
+
+		app, err := NewAgentApp(cfg)
+		require.NoError(t, err)
+
+		require.NoError(t, app.RegisterCollector(workers.NewFuncCollector("queue", time.Second)))
+		assert.ErrorIs(t, app.RegisterCollector(workers.NewRuntimeCollector()), workers.ErrCollectorExists)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- app.Start(ctx)
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+
+		require.Len(t, app.collectors, 2)
+		assert.Equal(t, "runtime", app.collectors[0].Name())
+		assert.Equal(t, "queue", app.collectors[1].Name())
+		assert.Equal(t, 5*time.Second, app.collectors[1].Interval())
+	})
+
+	t.Run("unknown collector", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.DisabledCollectors = "missing"
+
+		app, err := NewAgentApp(cfg)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, app.Start(context.Background()), workers.ErrUnknownCollector)
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.CollectorIntervals = "system"
+
+		app, err := NewAgentApp(cfg)
+		assert.ErrorIs(t, err, ErrInvalidCollectorInterval)
+		assert.Nil(t, app)
+	})
+}
@@ -0,0 +1,76 @@
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/repositories"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+	"github.com/sbilibin2017/yp-metrics/internal/workers"
+)
+
+var (
+	ErrInvalidCollectorInterval = errors.New("invalid collector interval")
+)
+
+func parseCollectorNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func parseCollectorIntervals(s string) (map[string]time.Duration, error) {
+	intervals := make(map[string]time.Duration)
+
+	for _, item := range parseCollectorNames(s) {
+		name, value, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCollectorInterval, item)
+		}
+
+		interval, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCollectorInterval, item)
+		}
+
+		intervals[name] = interval
+	}
+
+	return intervals, nil
+}
+
+func newCustomCollectors(path string) ([]workers.Collector, error) {
+	definitions, err := repositories.NewCustomCollectorFileListRepository(path).List(context.Background())
+	if err != nil {
+		logger.Log.Errorw("Failed to load custom collectors", "path", path, "error", err)
+		return nil, err
+	}
+
+	if err := validators.ValidateCustomCollectors(definitions); err != nil {
+		logger.Log.Errorw("Invalid custom collectors", "path", path, "error", err)
+		return nil, err
+	}
+
+	collectors := make([]workers.Collector, 0, len(definitions))
+	for _, c := range definitions {
+		interval := time.Duration(c.Interval) * time.Second
+		if len(c.Exec) > 0 {
+			collectors = append(collectors, workers.NewExecCollector(c.Name, c.Exec, interval, time.Duration(c.Timeout)*time.Second))
+		} else {
+			collectors = append(collectors, workers.NewFileCollector(c.Name, c.File, interval))
+		}
+	}
+
+	logger.Log.Infow("Custom collectors loaded", "path", path, "count", len(collectors))
+
+	return collectors, nil
+}
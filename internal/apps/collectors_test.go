@@ -0,0 +1,103 @@
+package apps
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/configs"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+	"github.com/sbilibin2017/yp-metrics/internal/workers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCollectorNames(t *testing.T) {
+	assert.Nil(t, parseCollectorNames(""))
+	assert.Equal(t, []string{"runtime", "system"}, parseCollectorNames(" runtime, ,system "))
+}
+
+func TestParseCollectorIntervals(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]time.Duration
+		wantErr  bool
+	}{
+		{name: "empty", input: "", expected: map[string]time.Duration{}},
+		{name: "several", input: "system=10s, queue=1m", expected: map[string]time.Duration{"system": 10 * time.Second, "queue": time.Minute}},
+		{name: "missing value", input: "system", wantErr: true},
+		{name: "missing name", input: "=10s", wantErr: true},
+		{name: "bad duration", input: "system=soon", wantErr: true},
+		{name: "non-positive", input: "system=0s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals, err := parseCollectorIntervals(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCollectorInterval)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, intervals)
+		})
+	}
+}
+
+func TestNewCustomCollectors(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("exec and file", func(t *testing.T) {
+		path := filepath.Join(dir, "collectors.json")
+		content := `[
+			{"name": "queue", "exec": ["sh", "-c", "echo 'gauge QueueLength 7'"], "interval": 10, "timeout": 1},
+			{"name": "cache", "file": "/var/run/cache.metrics"}
+		]`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		collectors, err := newCustomCollectors(path)
+		require.NoError(t, err)
+		require.Len(t, collectors, 2)
+
+		assert.IsType(t, &workers.ExecCollector{}, collectors[0])
+		assert.Equal(t, 10*time.Second, collectors[0].Interval())
+		assert.IsType(t, &workers.FileCollector{}, collectors[1])
+		assert.Zero(t, collectors[1].Interval())
+
+		metrics, err := collectors[0].Collect(context.Background())
+		require.NoError(t, err)
+		require.Len(t, metrics, 1)
+		assert.Equal(t, "QueueLength", metrics[0].ID)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[{"name": "x"}]`), 0644))
+
+		_, err := newCustomCollectors(path)
+		assert.ErrorIs(t, err, validators.ErrCollectorSource)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := newCustomCollectors(filepath.Join(dir, "missing.json"))
+		assert.Error(t, err)
+	})
+
+	t.Run("registered in agent", func(t *testing.T) {
+		path := filepath.Join(dir, "agent.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[{"name": "runtime", "file": "/tmp/x"}]`), 0644))
+
+		app, err := NewAgentApp(&configs.AgentConfig{
+			Address:              "http://localhost:8080",
+			PollInterval:         1,
+			ReportInterval:       1,
+			LogLevel:             "info",
+			CustomCollectorsPath: path,
+		})
+		assert.ErrorIs(t, err, workers.ErrCollectorExists)
+		assert.Nil(t, app)
+	})
+}
@@ -0,0 +1,36 @@
+package apps
+
+import (
+	"reflect"
+)
+
+func changedConfigFields(oldCfg, newCfg any, reloadable ...string) []string {
+	skip := make(map[string]bool, len(reloadable))
+	for _, name := range reloadable {
+		skip[name] = true
+	}
+
+	oldVal := reflect.Indirect(reflect.ValueOf(oldCfg))
+	newVal := reflect.Indirect(reflect.ValueOf(newCfg))
+
+	var changed []string
+	for i := 0; i < oldVal.NumField(); i++ {
+		name := oldVal.Type().Field(i).Name
+		if skip[name] {
+			continue
+		}
+		if !reflect.DeepEqual(oldVal.Field(i).Interface(), newVal.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+
+	return changed
+}
+
+func sendInterval(ch chan int, interval int) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- interval
+}
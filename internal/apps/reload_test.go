@@ -0,0 +1,139 @@
+package apps
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedConfigFields(t *testing.T) {
+	oldCfg := &configs.AgentConfig{Address: "a", PollInterval: 1, LogLevel: "info"}
+	newCfg := &configs.AgentConfig{Address: "b", PollInterval: 2, LogLevel: "debug", Key: "k"}
+
+	changed := changedConfigFields(oldCfg, newCfg, "LogLevel", "PollInterval")
+	assert.Equal(t, []string{"Address", "Key"}, changed)
+
+	assert.Empty(t, changedConfigFields(oldCfg, oldCfg))
+}
+
+func TestSendInterval(t *testing.T) {
+	ch := make(chan int, 1)
+
+	sendInterval(ch, 1)
+	sendInterval(ch, 2)
+
+	assert.Equal(t, 2, <-ch)
+	assert.Empty(t, ch)
+}
+
+func TestServerApp_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	cfg := &configs.ServerConfig{Addr: ":0", LogLevel: "info", StoreInterval: 300, FileStoragePath: path}
+
+	app, err := NewServerApp(cfg)
+	require.NoError(t, err)
+
+	// Без загрузчика перезагрузка ничего не меняет
+	app.reload()
+	assert.Equal(t, 300, app.config.StoreInterval)
+
+	app.SetConfigLoader(func() (*configs.ServerConfig, error) {
+		return &configs.ServerConfig{Addr: ":9090", LogLevel: "debug", StoreInterval: 5, FileStoragePath: path}, nil
+	})
+	app.reload()
+
+	assert.Equal(t, "debug", app.config.LogLevel)
+	assert.Equal(t, 5, app.config.StoreInterval)
+	assert.Equal(t, ":0", app.config.Addr)
+	assert.Equal(t, 5, <-app.storeIntervalCh)
+
+	app.SetConfigLoader(func() (*configs.ServerConfig, error) {
+		return &configs.ServerConfig{Addr: ":0", LogLevel: "notalevel", StoreInterval: -1, FileStoragePath: path}, nil
+	})
+	app.reload()
+
+	assert.Equal(t, "debug", app.config.LogLevel)
+	assert.Equal(t, 5, app.config.StoreInterval)
+	assert.Empty(t, app.storeIntervalCh)
+
+	app.SetConfigLoader(func() (*configs.ServerConfig, error) {
+		return nil, errors.New("bad config")
+	})
+	app.reload()
+	assert.Equal(t, "debug", app.config.LogLevel)
+}
+
+func TestServerApp_Reload_StoreIntervalWithoutFile(t *testing.T) {
+	cfg := &configs.ServerConfig{Addr: ":0", LogLevel: "info", StoreInterval: 300}
+
+	app, err := NewServerApp(cfg)
+	require.NoError(t, err)
+
+	// Без файлового хранилища интервал сохранения не применяется
+	app.SetConfigLoader(func() (*configs.ServerConfig, error) {
+		return &configs.ServerConfig{Addr: ":0", LogLevel: "info", StoreInterval: 5}, nil
+	})
+	app.reload()
+
+	assert.Equal(t, 300, app.config.StoreInterval)
+	assert.Empty(t, app.storeIntervalCh)
+}
+
+func TestAgentApp_Reload(t *testing.T) {
+	cfg := &configs.AgentConfig{Address: "http://localhost:8080", PollInterval: 2, ReportInterval: 10, LogLevel: "info"}
+
+	app, err := NewAgentApp(cfg)
+	require.NoError(t, err)
+
+	app.SetConfigLoader(func() (*configs.AgentConfig, error) {
+		return &configs.AgentConfig{Address: "http://other:8080", PollInterval: 1, ReportInterval: 0, LogLevel: "warn"}, nil
+	})
+	app.reload()
+
+	assert.Equal(t, "warn", app.config.LogLevel)
+	assert.Equal(t, 1, app.config.PollInterval)
+	assert.Equal(t, 10, app.config.ReportInterval)
+	assert.Equal(t, "http://localhost:8080", app.config.Address)
+	assert.Equal(t, 1, <-app.pollIntervalCh)
+	assert.Empty(t, app.reportIntervalCh)
+}
+
+func TestAgentApp_SIGHUP(t *testing.T) {
+	cfg := &configs.AgentConfig{Address: "http://localhost:8080", PollInterval: 2, ReportInterval: 10, LogLevel: "info"}
+
+	app, err := NewAgentApp(cfg)
+	require.NoError(t, err)
+
+	reloaded := make(chan struct{}, 1)
+	app.SetConfigLoader(func() (*configs.AgentConfig, error) {
+		reloaded <- struct{}{}
+		return &configs.AgentConfig{Address: cfg.Address, PollInterval: 2, ReportInterval: 10, LogLevel: "info"}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+
+	select {
+	case <-reloaded:
+	case <-time.After(time.Second):
+		t.Fatal("configuration was not reloaded on SIGHUP")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
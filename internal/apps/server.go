@@ -2,11 +2,15 @@ package apps
 
 import (
 	"context"
+	"crypto/rsa"
 	"errors"
+	"net"
 	"net/http"
+	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,21 +19,51 @@ import (
 	"github.com/pressly/goose"
 	"github.com/sbilibin2017/yp-metrics/internal/configs"
 	"github.com/sbilibin2017/yp-metrics/internal/contexts"
+	"github.com/sbilibin2017/yp-metrics/internal/grpcservers"
 	"github.com/sbilibin2017/yp-metrics/internal/handlers"
+	"github.com/sbilibin2017/yp-metrics/internal/interceptors"
+	"github.com/sbilibin2017/yp-metrics/internal/listeners"
 	"github.com/sbilibin2017/yp-metrics/internal/logger"
 	"github.com/sbilibin2017/yp-metrics/internal/middlewares"
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
 	"github.com/sbilibin2017/yp-metrics/internal/repositories"
 	"github.com/sbilibin2017/yp-metrics/internal/services"
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 	"github.com/sbilibin2017/yp-metrics/internal/validators"
 	"github.com/sbilibin2017/yp-metrics/internal/workers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
+var (
+	ErrInvalidAlertInterval         = errors.New("alert interval must be positive")
+	ErrInvalidStatsDFlushInterval   = errors.New("statsd flush interval must be positive")
+	ErrInvalidGraphiteFlushInterval = errors.New("graphite flush interval must be positive")
+	ErrInvalidGraphiteConnections   = errors.New("graphite max connections must be positive")
+	ErrInvalidSetWindow             = errors.New("set window must not be negative")
+)
+
+type ingestServer interface {
+	Listen() error
+	Serve(ctx context.Context) error
+}
+
+type ingestListener struct {
+	name   string
+	addr   string
+	server ingestServer
+}
+
 type ServerApp struct {
-	config  *configs.ServerConfig
-	db      *sqlx.DB
-	server  *http.Server
-	workers []func(ctx context.Context)
+	config          *configs.ServerConfig
+	configLoader    func() (*configs.ServerConfig, error)
+	db              *sqlx.DB
+	server          *http.Server
+	grpcServer      *grpc.Server
+	listeners       []ingestListener
+	workers         []func(ctx context.Context)
+	fileStorage     bool
+	storeIntervalCh chan int
 }
 
 func NewServerApp(config *configs.ServerConfig) (*ServerApp, error) {
@@ -63,69 +97,262 @@ func NewServerApp(config *configs.ServerConfig) (*ServerApp, error) {
 	metricFileListRepository := repositories.NewMetricFileListRepository(config.FileStoragePath)
 	metricDBListRepository := repositories.NewMetricDBListRepository(db, contexts.GetTxFromContext)
 
+	historyBuffer := repositories.NewMetricHistoryBuffer(config.HistorySize)
+
+	metricMemoryHistorySaveRepository := repositories.NewMetricMemoryHistorySaveRepository(historyBuffer)
+	metricFileHistorySaveRepository := repositories.NewMetricFileHistorySaveRepository(
+		config.HistoryFilePath,
+		time.Duration(config.HistoryRetention)*time.Second,
+	)
+	metricDBHistorySaveRepository := repositories.NewMetricDBHistorySaveRepository(db, contexts.GetTxFromContext)
+
+	metricMemoryHistoryListRepository := repositories.NewMetricMemoryHistoryListRepository(historyBuffer)
+	metricFileHistoryListRepository := repositories.NewMetricFileHistoryListRepository(config.HistoryFilePath)
+	metricDBHistoryListRepository := repositories.NewMetricDBHistoryListRepository(db, contexts.GetTxFromContext)
+
+	instanceRegistry := repositories.NewInstanceRegistry()
+	instanceMemorySaveRepository := repositories.NewInstanceMemorySaveRepository(instanceRegistry)
+	instanceMemoryListRepository := repositories.NewInstanceMemoryListRepository(instanceRegistry)
+
+	metricUpdateTimeRegistry := repositories.NewMetricUpdateTimeRegistry()
+	metricUpdateTimeMemorySaveRepository := repositories.NewMetricUpdateTimeMemorySaveRepository(metricUpdateTimeRegistry)
+	metricUpdateTimeMemoryGetRepository := repositories.NewMetricUpdateTimeMemoryGetRepository(metricUpdateTimeRegistry)
+
 	metricSaverContext := repositories.NewMetricSaverContext()
 	metricGetterContext := repositories.NewMetricGetterContext()
 	metricListerContext := repositories.NewMetricListerContext()
+	metricHistorySaverContext := repositories.NewMetricHistorySaverContext()
+	metricHistoryListerContext := repositories.NewMetricHistoryListerContext()
 
 	if config.DatabaseDSN != "" {
 		metricSaverContext.SetContext(metricDBSaveRepository)
 		metricGetterContext.SetContext(metricDBGetRepository)
 		metricListerContext.SetContext(metricDBListRepository)
+		metricHistorySaverContext.SetContext(metricDBHistorySaveRepository)
+		metricHistoryListerContext.SetContext(metricDBHistoryListRepository)
 		logger.Log.Info("Using database repositories for saver, getter, and lister")
 	} else if config.FileStoragePath != "" {
 		metricSaverContext.SetContext(metricFileSaveRepository)
 		metricGetterContext.SetContext(metricFileGetRepository)
 		metricListerContext.SetContext(metricFileListRepository)
+		metricHistorySaverContext.SetContext(metricFileHistorySaveRepository)
+		metricHistoryListerContext.SetContext(metricFileHistoryListRepository)
 		logger.Log.Infow("Using file repositories for saver, getter, and lister", "fileStoragePath", config.FileStoragePath)
 	} else {
 		metricSaverContext.SetContext(metricMemorySaveRepository)
 		metricGetterContext.SetContext(metricMemoryGetRepository)
 		metricListerContext.SetContext(metricMemoryListRepository)
+		metricHistorySaverContext.SetContext(metricMemoryHistorySaveRepository)
+		metricHistoryListerContext.SetContext(metricMemoryHistoryListRepository)
 		logger.Log.Info("Using in-memory repositories for saver, getter, and lister")
 	}
 
-	metricUpdateService := services.NewMetricUpdateService(metricSaverContext, metricGetterContext)
-	metricGetService := services.NewMetricGetService(metricGetterContext)
-	metricListService := services.NewMetricListService(metricListerContext)
+	if config.SetWindow < 0 {
+		return nil, ErrInvalidSetWindow
+	}
+	setWindow := time.Duration(config.SetWindow) * time.Second
+
+	metricUpdateService := services.NewMetricUpdateService(
+		metricSaverContext,
+		metricGetterContext,
+		metricHistorySaverContext,
+		instanceMemorySaveRepository,
+		metricUpdateTimeMemorySaveRepository,
+		contexts.GetInstanceFromContext,
+		setWindow,
+	)
+	metricAggregateService := services.NewMetricAggregateService(metricGetterContext, metricListerContext, setWindow)
+	metricGetService := services.NewMetricGetService(metricAggregateService, setWindow)
+	metricListService := services.NewMetricListService(metricListerContext, setWindow)
+	metricHistoryService := services.NewMetricHistoryService(metricHistoryListerContext, metricListerContext)
+	instanceListService := services.NewInstanceListService(instanceMemoryListRepository)
+
+	var alertRules []types.AlertRule
+	if config.AlertRulesPath != "" {
+		alertRules, err = newAlertRules(config.AlertRulesPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(alertRules) > 0 && config.AlertInterval <= 0 {
+			return nil, ErrInvalidAlertInterval
+		}
+	}
+
+	alertEvaluateService := services.NewAlertEvaluateService(metricAggregateService, metricUpdateTimeMemoryGetRepository, alertRules)
 
 	logger.Log.Info("Services initialized")
 
+	var privateKey *rsa.PrivateKey
+	if config.CryptoKey != "" {
+		privateKey, err = newPrivateKey(config.CryptoKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	metricUpdatePathHandler := handlers.MetricUpdatePathHandler(validators.ValidateMetricPath, metricUpdateService)
 	metricUpdateBodyHandler := handlers.MetricUpdateBodyHandler(validators.ValidateMetricBody, metricUpdateService)
 	metricUpdatesBodyHandler := handlers.MetricUpdatesBodyHandler(validators.ValidateMetricBody, metricUpdateService)
+	metricWriteInfluxHandler := handlers.MetricWriteInfluxHandler(validators.ValidateMetricBody, metricUpdateService)
+	metricWriteOTLPHandler := handlers.MetricWriteOTLPHandler(validators.ValidateMetricBody, metricUpdateService)
 	metricGetPathHandler := handlers.MetricGetPathHandler(validators.ValidateMetricIDPath, metricGetService)
-	metricGetBodyHandler := handlers.MetricGetBodyHandler(validators.ValidateMetricIDPath, metricGetService)
+	metricGetBodyHandler := handlers.MetricGetBodyHandler(validators.ValidateMetricIDBody, metricGetService)
 	metricListHTMLHandler := handlers.MetricListHTMLHandler(metricListService)
+	metricListPrometheusHandler := handlers.MetricListPrometheusHandler(metricListService)
+	metricHistoryPathHandler := handlers.MetricHistoryPathHandler(validators.ValidateMetricIDPath, metricHistoryService)
 	pingDBHandler := handlers.PingDBHandler(db)
+	alertListHandler := handlers.AlertListHandler(alertEvaluateService)
+	instanceListHandler := handlers.InstanceListHandler(instanceListService)
 
-	middlewares := []func(http.Handler) http.Handler{
-		middlewares.LoggingMiddleware,
+	var trustedSubnet *net.IPNet
+	if config.TrustedSubnet != "" {
+		_, trustedSubnet, err = net.ParseCIDR(config.TrustedSubnet)
+		if err != nil {
+			logger.Log.Errorw("Invalid trusted subnet", "subnet", config.TrustedSubnet, "error", err)
+			return nil, err
+		}
+	}
+
+	trustedSubnetMiddleware := middlewares.TrustedSubnetMiddleware(trustedSubnet)
+
+	commonMiddlewares := []func(http.Handler) http.Handler{
 		middlewares.GzipMiddleware,
+		middlewares.InstanceMiddleware(contexts.SetInstanceToContext),
 		middlewares.TxMiddleware(db, contexts.SetTxToContext),
 		middlewares.RetryMiddleware,
 	}
 
 	router := chi.NewRouter()
-	router.Use(middlewares...)
+	router.Use(middlewares.LoggingMiddleware)
+
+	router.Group(func(r chi.Router) {
+		r.Use(
+			trustedSubnetMiddleware,
+			middlewares.HashMiddleware(config.Key),
+			middlewares.CryptoMiddleware(privateKey),
+		)
+		r.Use(commonMiddlewares...)
+
+		r.Post("/update/{type}/{name}/{value}", metricUpdatePathHandler)
+		r.Post("/update/{type}/{name}", metricUpdatePathHandler)
+		r.Post("/update/", metricUpdateBodyHandler)
+		r.Post("/updates/", metricUpdatesBodyHandler)
+	})
+
+	router.Group(func(r chi.Router) {
+		r.Use(trustedSubnetMiddleware)
+		r.Use(commonMiddlewares...)
 
-	router.Post("/update/{type}/{name}/{value}", metricUpdatePathHandler)
-	router.Post("/update/{type}/{name}", metricUpdatePathHandler)
-	router.Post("/update/", metricUpdateBodyHandler)
-	router.Post("/updates/", metricUpdatesBodyHandler)
+		r.Post("/write", metricWriteInfluxHandler)
+		r.Post("/v1/metrics", metricWriteOTLPHandler)
+	})
 
-	router.Get("/value/{type}/{name}", metricGetPathHandler)
-	router.Get("/value/{type}", metricGetPathHandler)
-	router.Post("/value/", metricGetBodyHandler)
+	router.Group(func(r chi.Router) {
+		r.Use(commonMiddlewares...)
 
-	router.Get("/", metricListHTMLHandler)
+		r.Get("/value/{type}/{name}", metricGetPathHandler)
+		r.Get("/value/{type}", metricGetPathHandler)
+		r.Post("/value/", metricGetBodyHandler)
 
-	router.Get("/ping", pingDBHandler)
+		r.Get("/history/{type}/{name}", metricHistoryPathHandler)
+
+		r.Get("/", metricListHTMLHandler)
+		r.Get("/metrics", metricListPrometheusHandler)
+
+		r.Get("/ping", pingDBHandler)
+
+		r.Get("/alerts", alertListHandler)
+
+		r.Get("/instances", instanceListHandler)
+	})
+
+	tlsConfig, err := newServerTLSConfig(config.TLSCertFile, config.TLSKeyFile, config.TLSClientCAFile)
+	if err != nil {
+		return nil, err
+	}
 
 	srv := &http.Server{
-		Addr:    config.Addr,
-		Handler: router,
+		Addr:      config.Addr,
+		Handler:   router,
+		TLSConfig: tlsConfig,
+	}
+
+	var grpcServer *grpc.Server
+	if config.GRPCAddr != "" {
+		opts := []grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(
+				interceptors.LoggingUnaryInterceptor,
+				interceptors.TrustedSubnetUnaryInterceptor(trustedSubnet),
+				interceptors.HashUnaryInterceptor(config.Key),
+				interceptors.InstanceUnaryInterceptor(contexts.SetInstanceToContext),
+				interceptors.TxUnaryInterceptor(db, contexts.SetTxToContext),
+			),
+			grpc.ChainStreamInterceptor(
+				interceptors.LoggingStreamInterceptor,
+				interceptors.TrustedSubnetStreamInterceptor(trustedSubnet),
+				interceptors.HashStreamInterceptor(config.Key),
+				interceptors.InstanceStreamInterceptor(contexts.SetInstanceToContext),
+			),
+		}
+		if tlsConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		}
+
+		grpcServer = grpc.NewServer(opts...)
+		pb.RegisterMetricServiceServer(grpcServer, grpcservers.NewMetricServer(
+			validators.ValidateMetricBody,
+			validators.ValidateMetricIDPath,
+			metricUpdateService,
+			metricGetService,
+			metricListService,
+			db,
+			contexts.SetTxToContext,
+		))
+	}
+
+	var ingestListeners []ingestListener
+	if config.StatsDAddr != "" {
+		if config.StatsDFlushInterval <= 0 {
+			return nil, ErrInvalidStatsDFlushInterval
+		}
+		ingestListeners = append(ingestListeners, ingestListener{
+			name: "StatsD",
+			addr: config.StatsDAddr,
+			server: listeners.NewStatsDServer(
+				config.StatsDAddr,
+				time.Duration(config.StatsDFlushInterval)*time.Second,
+				trustedSubnet,
+				metricUpdateService,
+				db,
+				contexts.SetTxToContext,
+			),
+		})
 	}
 
+	if config.GraphiteAddr != "" {
+		if config.GraphiteFlushInterval <= 0 {
+			return nil, ErrInvalidGraphiteFlushInterval
+		}
+		if config.GraphiteMaxConnections <= 0 {
+			return nil, ErrInvalidGraphiteConnections
+		}
+		ingestListeners = append(ingestListeners, ingestListener{
+			name: "Graphite",
+			addr: config.GraphiteAddr,
+			server: listeners.NewGraphiteServer(
+				config.GraphiteAddr,
+				time.Duration(config.GraphiteFlushInterval)*time.Second,
+				config.GraphiteMaxConnections,
+				trustedSubnet,
+				metricUpdateService,
+				db,
+				contexts.SetTxToContext,
+			),
+		})
+	}
+
+	storeIntervalCh := make(chan int, 1)
+
 	ws := make([]func(ctx context.Context), 0)
 	if config.FileStoragePath != "" {
 		ws = append(ws, func(ctx context.Context) {
@@ -137,20 +364,39 @@ func NewServerApp(config *configs.ServerConfig) (*ServerApp, error) {
 				metricFileListRepository,
 				config.StoreInterval,
 				config.Restore,
+				storeIntervalCh,
+			)
+		})
+	}
+
+	if len(alertRules) > 0 {
+		ws = append(ws, func(ctx context.Context) {
+			workers.StartAlertServerWorker(
+				ctx,
+				alertEvaluateService,
+				config.AlertInterval,
 			)
 		})
 	}
 
 	app := &ServerApp{
-		config:  config,
-		db:      db,
-		server:  srv,
-		workers: ws,
+		config:          config,
+		db:              db,
+		server:          srv,
+		grpcServer:      grpcServer,
+		listeners:       ingestListeners,
+		workers:         ws,
+		fileStorage:     config.DatabaseDSN == "" && config.FileStoragePath != "",
+		storeIntervalCh: storeIntervalCh,
 	}
 
 	return app, nil
 }
 
+func (a *ServerApp) SetConfigLoader(loader func() (*configs.ServerConfig, error)) {
+	a.configLoader = loader
+}
+
 func (a *ServerApp) Start(ctx context.Context) error {
 	ctx, stop := signal.NotifyContext(
 		ctx,
@@ -160,12 +406,34 @@ func (a *ServerApp) Start(ctx context.Context) error {
 	)
 	defer stop()
 
-	logger.Log.Infow("Starting HTTP server", "address", a.config.Addr)
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hupCh:
+				a.reload()
+			}
+		}
+	}()
+
+	logger.Log.Infow("Starting HTTP server", "address", a.config.Addr, "tls", a.server.TLSConfig != nil)
 
 	errChan := make(chan error, 1)
 
 	go func() {
-		if err := a.server.ListenAndServe(); err != nil &&
+		var err error
+		if a.server.TLSConfig != nil {
+			err = a.server.ListenAndServeTLS("", "")
+		} else {
+			err = a.server.ListenAndServe()
+		}
+
+		if err != nil &&
 			!errors.Is(err, context.Canceled) &&
 			err != http.ErrServerClosed {
 
@@ -177,6 +445,53 @@ func (a *ServerApp) Start(ctx context.Context) error {
 		close(errChan)
 	}()
 
+	grpcErrChan := make(chan error, 1)
+
+	if a.grpcServer != nil {
+		lis, err := net.Listen("tcp", a.config.GRPCAddr)
+		if err != nil {
+			logger.Log.Errorw("Failed to listen gRPC address", "address", a.config.GRPCAddr, "error", err)
+			a.server.Close()
+			return err
+		}
+
+		logger.Log.Infow("Starting gRPC server", "address", a.config.GRPCAddr)
+
+		go func() {
+			if err := a.grpcServer.Serve(lis); err != nil {
+				logger.Log.Errorw("gRPC server failed", "error", err)
+				grpcErrChan <- err
+			} else {
+				logger.Log.Info("gRPC server exited gracefully")
+			}
+			close(grpcErrChan)
+		}()
+	}
+
+	for _, l := range a.listeners {
+		if err := l.server.Listen(); err != nil {
+			logger.Log.Errorw("Failed to listen "+l.name+" address", "address", l.addr, "error", err)
+			a.server.Close()
+			if a.grpcServer != nil {
+				a.grpcServer.Stop()
+			}
+			return err
+		}
+	}
+
+	var listenersWg sync.WaitGroup
+	for _, l := range a.listeners {
+		logger.Log.Infow("Starting "+l.name+" listener", "address", l.addr)
+
+		listenersWg.Add(1)
+		go func() {
+			defer listenersWg.Done()
+			if err := l.server.Serve(ctx); err != nil {
+				logger.Log.Errorw(l.name+" listener failed", "error", err)
+			}
+		}()
+	}
+
 	for _, worker := range a.workers {
 		go worker(ctx)
 	}
@@ -187,15 +502,37 @@ func (a *ServerApp) Start(ctx context.Context) error {
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		if err := a.server.Shutdown(shutdownCtx); err != nil {
-			logger.Log.Errorw("Error during server shutdown", "error", err)
-			return err
+		grpcStopped := make(chan struct{})
+		if a.grpcServer != nil {
+			go func() {
+				a.grpcServer.GracefulStop()
+				close(grpcStopped)
+			}()
+		} else {
+			close(grpcStopped)
 		}
 
+		shutdownErr := a.server.Shutdown(shutdownCtx)
+
+		select {
+		case <-grpcStopped:
+		case <-shutdownCtx.Done():
+			logger.Log.Warn("gRPC graceful stop timed out, closing open streams")
+			a.grpcServer.Stop()
+			<-grpcStopped
+		}
+
+		listenersWg.Wait()
+
 		if a.db != nil {
 			a.db.Close()
 		}
 
+		if shutdownErr != nil {
+			logger.Log.Errorw("Error during server shutdown", "error", shutdownErr)
+			return shutdownErr
+		}
+
 		logger.Log.Info("Server shutdown completed gracefully")
 		return ctx.Err()
 
@@ -203,10 +540,61 @@ func (a *ServerApp) Start(ctx context.Context) error {
 		if err != nil {
 			logger.Log.Errorw("Server exited with error", "error", err)
 		}
+		if a.grpcServer != nil {
+			a.grpcServer.Stop()
+		}
+		return err
+
+	case err := <-grpcErrChan:
+		if err != nil {
+			logger.Log.Errorw("gRPC server exited with error", "error", err)
+		}
+		a.server.Close()
 		return err
 	}
 }
 
+func (a *ServerApp) reload() {
+	logger.Log.Info("SIGHUP received, reloading configuration")
+
+	if a.configLoader == nil {
+		logger.Log.Warn("Configuration reload is not supported")
+		return
+	}
+
+	cfg, err := a.configLoader()
+	if err != nil {
+		logger.Log.Errorw("Failed to reload configuration", "error", err)
+		return
+	}
+
+	for _, field := range changedConfigFields(a.config, cfg, "LogLevel", "StoreInterval") {
+		logger.Log.Warnw("Setting changed but requires restart to apply", "setting", field)
+	}
+
+	if cfg.LogLevel != a.config.LogLevel {
+		if err := logger.SetLevel(cfg.LogLevel); err != nil {
+			logger.Log.Errorw("Failed to apply log level", "level", cfg.LogLevel, "error", err)
+		} else {
+			a.config.LogLevel = cfg.LogLevel
+			logger.Log.Infow("Log level applied", "level", cfg.LogLevel)
+		}
+	}
+
+	if cfg.StoreInterval != a.config.StoreInterval {
+		switch {
+		case cfg.StoreInterval < 0:
+			logger.Log.Errorw("Invalid store interval", "storeInterval", cfg.StoreInterval)
+		case !a.fileStorage:
+			logger.Log.Infow("Store interval ignored, metrics are not stored in a file", "storeInterval", cfg.StoreInterval)
+		default:
+			a.config.StoreInterval = cfg.StoreInterval
+			sendInterval(a.storeIntervalCh, cfg.StoreInterval)
+			logger.Log.Infow("Store interval applied", "storeInterval", cfg.StoreInterval)
+		}
+	}
+}
+
 func newDB(dsn string) (*sqlx.DB, error) {
 	db, err := sqlx.Connect("pgx", dsn)
 	if err != nil {
@@ -244,3 +632,38 @@ func newDB(dsn string) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+func newAlertRules(path string) ([]types.AlertRule, error) {
+	rules, err := repositories.NewAlertRuleFileListRepository(path).List(context.Background())
+	if err != nil {
+		logger.Log.Errorw("Failed to load alert rules", "path", path, "error", err)
+		return nil, err
+	}
+
+	if err := validators.ValidateAlertRules(rules); err != nil {
+		logger.Log.Errorw("Invalid alert rules", "path", path, "error", err)
+		return nil, err
+	}
+
+	logger.Log.Infow("Alert rules loaded", "path", path, "count", len(rules))
+
+	return rules, nil
+}
+
+func newPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Log.Errorw("Failed to read private key", "path", path, "error", err)
+		return nil, err
+	}
+
+	key, err := types.ParseRSAPrivateKeyPEM(data)
+	if err != nil {
+		logger.Log.Errorw("Failed to parse private key", "path", path, "error", err)
+		return nil, err
+	}
+
+	logger.Log.Infow("Payload decryption enabled", "path", path)
+
+	return key, nil
+}
@@ -2,14 +2,33 @@ package apps_test
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	"github.com/sbilibin2017/yp-metrics/internal/apps"
 	"github.com/sbilibin2017/yp-metrics/internal/configs"
+	"github.com/sbilibin2017/yp-metrics/internal/facades"
+	"github.com/sbilibin2017/yp-metrics/internal/interceptors"
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
 )
 
 func TestNewServerApp_Success(t *testing.T) {
@@ -92,3 +111,1086 @@ func TestStart_ServerError(t *testing.T) {
 		t.Fatal("Timeout waiting for server error")
 	}
 }
+
+func TestNewServerApp_AlertRules(t *testing.T) {
+	dir := t.TempDir()
+
+	validPath := filepath.Join(dir, "rules.json")
+	require.NoError(t, os.WriteFile(validPath, []byte(`[
+		{"name": "high_heap", "metric": {"id": "HeapAlloc", "type": "gauge"}, "kind": "threshold", "operator": ">", "threshold": 100}
+	]`), 0644))
+
+	invalidPath := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidPath, []byte(`[
+		{"name": "bad", "metric": {"id": "HeapAlloc", "type": "gauge"}, "kind": "unknown"}
+	]`), 0644))
+
+	t.Run("valid rules", func(t *testing.T) {
+		app, err := apps.NewServerApp(&configs.ServerConfig{
+			Addr:           ":0",
+			LogLevel:       "info",
+			AlertRulesPath: validPath,
+			AlertInterval:  1,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, app)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		app, err := apps.NewServerApp(&configs.ServerConfig{
+			Addr:           ":0",
+			LogLevel:       "info",
+			AlertRulesPath: invalidPath,
+			AlertInterval:  1,
+		})
+		assert.Error(t, err)
+		assert.Nil(t, app)
+	})
+
+	t.Run("missing rules file", func(t *testing.T) {
+		app, err := apps.NewServerApp(&configs.ServerConfig{
+			Addr:           ":0",
+			LogLevel:       "info",
+			AlertRulesPath: filepath.Join(dir, "missing.json"),
+			AlertInterval:  1,
+		})
+		assert.Error(t, err)
+		assert.Nil(t, app)
+	})
+
+	t.Run("zero interval", func(t *testing.T) {
+		app, err := apps.NewServerApp(&configs.ServerConfig{
+			Addr:           ":0",
+			LogLevel:       "info",
+			AlertRulesPath: validPath,
+		})
+		assert.ErrorIs(t, err, apps.ErrInvalidAlertInterval)
+		assert.Nil(t, app)
+	})
+}
+
+func TestStart_GRPCServer(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     ":0",
+		GRPCAddr: "127.0.0.1:37201",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.NewClient(cfg.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := pb.NewMetricServiceClient(conn)
+
+	val := 3.14
+	_, err = client.Update(ctx, &pb.UpdateRequest{
+		Metric: pb.NewMetric(types.Metrics{ID: "Alloc", MType: types.Gauge, Value: &val}),
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Get(ctx, &pb.GetRequest{Id: &pb.MetricID{Id: "Alloc", Type: types.Gauge}})
+	require.NoError(t, err)
+	assert.Equal(t, val, resp.GetMetric().GetValue())
+
+	// метки, summary и set передаются через gRPC без потерь
+	observation := 0.25
+	stream, err := client.Updates(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.UpdatesRequest{Metrics: pb.NewMetrics([]types.Metrics{
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"host": "web01"}, Value: &observation},
+		{ID: "rt", MType: types.Summary, Value: &observation},
+		{ID: "users", MType: types.Set, Members: []string{"alice", "bob"}},
+	})}))
+	updates, err := stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, updates.GetError())
+	assert.Equal(t, int32(3), updates.GetUpdated())
+	require.NoError(t, stream.CloseSend())
+
+	resp, err = client.Get(ctx, &pb.GetRequest{Id: &pb.MetricID{Id: "Alloc", Type: types.Gauge, Labels: map[string]string{"host": "web01"}}})
+	require.NoError(t, err)
+	assert.Equal(t, observation, resp.GetMetric().GetValue())
+	assert.Equal(t, map[string]string{"host": "web01"}, resp.GetMetric().GetLabels())
+
+	list, err := client.List(ctx, &pb.ListRequest{})
+	require.NoError(t, err)
+	byID := make(map[string]types.Metrics)
+	for _, m := range list.GetMetrics() {
+		byID[m.GetId()] = m.ToMetrics()
+	}
+	require.NotNil(t, byID["rt"].Summary)
+	assert.Equal(t, int64(1), byID["rt"].Summary.Count)
+	require.NotNil(t, byID["users"].Set)
+	assert.Equal(t, uint64(2), byID["users"].Set.Cardinality())
+
+	cancel()
+
+	err = <-done
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStart_GRPCOpenStreamShutdown(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     ":0",
+		GRPCAddr: "127.0.0.1:37216",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.NewClient(cfg.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// поток остаётся открытым и не мешает завершению сервера
+	stream, err := pb.NewMetricServiceClient(conn).Updates(context.Background())
+	require.NoError(t, err)
+	val := 1.0
+	require.NoError(t, stream.Send(&pb.UpdatesRequest{
+		Metrics: pb.NewMetrics([]types.Metrics{{ID: "Alloc", MType: types.Gauge, Value: &val}}),
+	}))
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	cancel()
+
+	select {
+	case err = <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(10 * time.Second):
+		t.Fatal("server did not shut down with an open stream")
+	}
+}
+
+func TestStart_GRPCListenError(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     ":0",
+		GRPCAddr: ":99999",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- app.Start(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for gRPC listen error")
+	}
+}
+
+func writeRSAKeys(t *testing.T, dir string) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "private.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(path, data, 0600))
+
+	return key, path
+}
+
+func TestNewServerApp_CryptoKey(t *testing.T) {
+	dir := t.TempDir()
+	_, keyPath := writeRSAKeys(t, dir)
+
+	invalidPath := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidPath, []byte("not a key"), 0600))
+
+	app, err := apps.NewServerApp(&configs.ServerConfig{Addr: ":0", LogLevel: "info", CryptoKey: keyPath})
+	require.NoError(t, err)
+	require.NotNil(t, app)
+
+	app, err = apps.NewServerApp(&configs.ServerConfig{Addr: ":0", LogLevel: "info", CryptoKey: invalidPath})
+	assert.Error(t, err)
+	assert.Nil(t, app)
+
+	app, err = apps.NewServerApp(&configs.ServerConfig{Addr: ":0", LogLevel: "info", CryptoKey: filepath.Join(dir, "missing.pem")})
+	assert.Error(t, err)
+	assert.Nil(t, app)
+}
+
+func TestStart_SignedEncryptedUpdates(t *testing.T) {
+	key, keyPath := writeRSAKeys(t, t.TempDir())
+
+	cfg := &configs.ServerConfig{
+		Addr:      "127.0.0.1:37202",
+		LogLevel:  "info",
+		Key:       "secret",
+		CryptoKey: keyPath,
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	val := 3.14
+	metrics := []types.Metrics{{ID: "Alloc", MType: types.Gauge, Value: &val}}
+
+	facade := facades.NewMetricUpdateFacade(resty.New(), cfg.Addr, cfg.Key, &key.PublicKey, "")
+	require.NoError(t, facade.Updates(ctx, metrics))
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	resp, err := client.R().Get("/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, "3.14", resp.String())
+
+	// эндпоинты агента требуют подпись и шифрование
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`[{"id":"Alloc","type":"gauge","value":1}]`).
+		Post("/updates/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	// эндпоинты приёма из внешних систем защищены только доверенной подсетью
+	resp, err = client.R().SetBody("cpu usage_idle=92.5").Post("/write")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"cpu.utilization","gauge":{"dataPoints":[{"asDouble":0.25}]}}]}]}]}`).
+		Post("/v1/metrics")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	cancel()
+
+	err = <-done
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewServerApp_InvalidTrustedSubnet(t *testing.T) {
+	app, err := apps.NewServerApp(&configs.ServerConfig{Addr: ":0", LogLevel: "info", TrustedSubnet: "not-a-cidr"})
+	assert.Error(t, err)
+	assert.Nil(t, app)
+}
+
+func TestStart_TrustedSubnet(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:          "127.0.0.1:37203",
+		LogLevel:      "info",
+		TrustedSubnet: "10.0.0.0/8",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	client := resty.New()
+	base := "http://" + cfg.Addr
+
+	resp, err := client.R().SetHeader("X-Real-IP", "10.1.2.3").Post(base + "/update/gauge/Alloc/1.5")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().SetHeader("X-Real-IP", "192.168.1.1").Post(base + "/update/gauge/Alloc/2.5")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode())
+
+	resp, err = client.R().Post(base + "/updates/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode())
+
+	// Эндпоинты чтения доступны из любой сети
+	resp, err = client.R().Get(base + "/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, "1.5", resp.String())
+
+	cancel()
+
+	err = <-done
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewServerApp_InvalidStatsDFlushInterval(t *testing.T) {
+	app, err := apps.NewServerApp(&configs.ServerConfig{
+		Addr:       ":0",
+		LogLevel:   "info",
+		StatsDAddr: "127.0.0.1:0",
+	})
+	assert.ErrorIs(t, err, apps.ErrInvalidStatsDFlushInterval)
+	assert.Nil(t, app)
+}
+
+func TestStart_StatsDListener(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:                "127.0.0.1:37205",
+		LogLevel:            "info",
+		StatsDAddr:          "127.0.0.1:37206",
+		StatsDFlushInterval: 1,
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := net.Dial("udp", cfg.StatsDAddr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("requests:1|c\nrequests:2|c\nlatency:42|ms\nlatency:58|ms"))
+	require.NoError(t, err)
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	require.Eventually(t, func() bool {
+		resp, err := client.R().Get("/value/counter/requests")
+		return err == nil && resp.StatusCode() == http.StatusOK && resp.String() == "3"
+	}, 3*time.Second, 100*time.Millisecond)
+
+	// таймеры попадают в summary со всеми наблюдениями за период
+	resp, err := client.R().Get("/value/summary/latency")
+	require.NoError(t, err)
+	assert.Equal(t, "count=2 sum=100", resp.String())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStart_StatsDListenError(t *testing.T) {
+	app, err := apps.NewServerApp(&configs.ServerConfig{
+		Addr:                ":0",
+		LogLevel:            "info",
+		StatsDAddr:          ":99999",
+		StatsDFlushInterval: 1,
+	})
+	require.NoError(t, err)
+
+	select {
+	case err := <-startAsync(app):
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for StatsD listen error")
+	}
+}
+
+func startAsync(app *apps.ServerApp) <-chan error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- app.Start(context.Background())
+	}()
+	return errCh
+}
+
+func TestStart_InfluxWrite(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     "127.0.0.1:37207",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	resp, err := client.R().
+		SetBody("cpu,host=web01 usage_idle=92.5,processes=12i\ncpu processes=3i").
+		Post("/write")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+	resp, err = client.R().SetQueryParam("labels", "host=web01").Get("/value/gauge/cpu.usage_idle")
+	require.NoError(t, err)
+	assert.Equal(t, "92.5", resp.String())
+
+	// теги становятся метками, поэтому строки с разными тегами — разные ряды
+	resp, err = client.R().SetQueryParam("labels", "host=web01").Get("/value/gauge/cpu.processes")
+	require.NoError(t, err)
+	assert.Equal(t, "12", resp.String())
+
+	resp, err = client.R().Get("/value/gauge/cpu.processes")
+	require.NoError(t, err)
+	assert.Equal(t, "3", resp.String())
+
+	// целые поля — абсолютные значения, повторная запись их не накапливает
+	resp, err = client.R().SetBody("cpu processes=3i").Post("/write")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+	resp, err = client.R().Get("/value/gauge/cpu.processes")
+	require.NoError(t, err)
+	assert.Equal(t, "3", resp.String())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestNewServerApp_InvalidGraphiteConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *configs.ServerConfig
+		wantErr error
+	}{
+		{
+			name: "flush interval",
+			cfg: &configs.ServerConfig{
+				Addr:                   ":0",
+				LogLevel:               "info",
+				GraphiteAddr:           "127.0.0.1:0",
+				GraphiteMaxConnections: 10,
+			},
+			wantErr: apps.ErrInvalidGraphiteFlushInterval,
+		},
+		{
+			name: "max connections",
+			cfg: &configs.ServerConfig{
+				Addr:                  ":0",
+				LogLevel:              "info",
+				GraphiteAddr:          "127.0.0.1:0",
+				GraphiteFlushInterval: 1,
+			},
+			wantErr: apps.ErrInvalidGraphiteConnections,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, err := apps.NewServerApp(tt.cfg)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, app)
+		})
+	}
+}
+
+func TestStart_GraphiteListener(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:                   "127.0.0.1:37208",
+		LogLevel:               "info",
+		GraphiteAddr:           "127.0.0.1:37209",
+		GraphiteFlushInterval:  1,
+		GraphiteMaxConnections: 10,
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := net.Dial("tcp", cfg.GraphiteAddr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("servers.web01.load 1.5 1700000000\n"))
+	require.NoError(t, err)
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	require.Eventually(t, func() bool {
+		resp, err := client.R().Get("/value/gauge/servers.web01.load")
+		return err == nil && resp.StatusCode() == http.StatusOK && resp.String() == "1.5"
+	}, 3*time.Second, 100*time.Millisecond)
+
+	// открытое соединение не должно мешать корректной остановке
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timeout waiting for graceful shutdown")
+	}
+}
+
+func TestStart_GraphiteListenError(t *testing.T) {
+	app, err := apps.NewServerApp(&configs.ServerConfig{
+		Addr:                   ":0",
+		LogLevel:               "info",
+		GraphiteAddr:           ":99999",
+		GraphiteFlushInterval:  1,
+		GraphiteMaxConnections: 10,
+	})
+	require.NoError(t, err)
+
+	select {
+	case err := <-startAsync(app):
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for Graphite listen error")
+	}
+}
+
+func TestStart_OTLPWrite(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     "127.0.0.1:37210",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	body := `{"resourceMetrics":[{"resource":{"attributes":[{"key":"host.name","value":{"stringValue":"web01"}}]},
+		"scopeMetrics":[{"metrics":[
+			{"name":"cpu.utilization","gauge":{"dataPoints":[{"asDouble":0.25}]}},
+			{"name":"http.requests","sum":{"isMonotonic":true,"aggregationTemporality":1,"dataPoints":[{"asInt":"7"}]}}
+		]}]}]}`
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(body).
+			Post("/v1/metrics")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+	}
+
+	resp, err := client.R().SetQueryParam("labels", "host.name=web01").Get("/value/gauge/cpu.utilization")
+	require.NoError(t, err)
+	assert.Equal(t, "0.25", resp.String())
+
+	resp, err = client.R().SetQueryParam("labels", "host.name=web01").Get("/value/counter/http.requests")
+	require.NoError(t, err)
+	assert.Equal(t, "14", resp.String())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStart_LabeledMetrics(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     "127.0.0.1:37211",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	// два агента с одинаковыми именами метрик не перезаписывают друг друга
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`[
+			{"id":"Alloc","type":"gauge","labels":{"host":"web01"},"value":1},
+			{"id":"Alloc","type":"gauge","labels":{"host":"web02"},"value":2},
+			{"id":"PollCount","type":"counter","labels":{"host":"web01"},"delta":3},
+			{"id":"PollCount","type":"counter","labels":{"host":"web01"},"delta":4}
+		]`).
+		Post("/updates/")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().SetQueryParam("labels", "host=web02").Get("/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, "2", resp.String())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"id":"PollCount","type":"counter","labels":{"host":"web01"}}`).
+		Post("/value/")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"PollCount","type":"counter","labels":{"host":"web01"},"delta":7}`, resp.String())
+
+	resp, err = client.R().Get("/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`[{"id":"Alloc","type":"gauge","labels":{"host":""},"value":1}]`).
+		Post("/updates/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStart_InstanceMetrics(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:        "127.0.0.1:37212",
+		LogLevel:    "info",
+		HistorySize: 10,
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	facade := facades.NewMetricUpdateFacade(resty.New(), cfg.Addr, "", nil, "web01")
+	value := 1.5
+	require.NoError(t, facade.Updates(ctx, []types.Metrics{{ID: "Alloc", MType: types.Gauge, Value: &value}}))
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	resp, err := client.R().SetQueryParam("labels", "instance=web01").Get("/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", resp.String())
+
+	// запрос без меток агрегирует значения всех инстансов: для gauge — максимум
+	other := facades.NewMetricUpdateFacade(resty.New(), cfg.Addr, "", nil, "web02")
+	otherValue := 2.0
+	require.NoError(t, other.Updates(ctx, []types.Metrics{{ID: "Alloc", MType: types.Gauge, Value: &otherValue}}))
+
+	resp, err = client.R().Get("/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, "2", resp.String())
+
+	var metric types.Metrics
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"id":"Alloc","type":"gauge"}`).
+		SetResult(&metric).
+		Post("/value/")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, 2.0, *metric.Value)
+
+	// пустое значение метки в теле отклоняется с 400
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"id":"Alloc","type":"gauge","labels":{"instance":""}}`).
+		Post("/value/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	// история без меток объединяет отсчёты всех инстансов
+	var history types.MetricHistory
+	resp, err = client.R().SetResult(&history).Get("/history/gauge/Alloc")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Len(t, history.Samples, 2)
+	assert.Equal(t, 1.5, *history.Samples[0].Value)
+	assert.Equal(t, 2.0, *history.Samples[1].Value)
+
+	resp, err = client.R().Get("/")
+	require.NoError(t, err)
+	assert.Contains(t, resp.String(), "<h2>web01</h2>\n<ul>\n<li>Alloc (gauge): 1.5</li>")
+
+	var instances []types.Instance
+	resp, err = client.R().SetResult(&instances).Get("/instances")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Len(t, instances, 2)
+	assert.Equal(t, "web01", instances[0].ID)
+	assert.WithinDuration(t, time.Now(), instances[0].LastSeen, 5*time.Second)
+
+	// некорректный идентификатор инстанса отклоняется
+	resp, err = client.R().
+		SetHeader(types.InstanceIDHeader, strings.Repeat("a", 256)).
+		SetHeader("Content-Type", "application/json").
+		SetBody(`[{"id":"Alloc","type":"gauge","value":1}]`).
+		Post("/updates/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStart_GRPCInstanceMetrics(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     "127.0.0.1:37217",
+		GRPCAddr: "127.0.0.1:37218",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := grpc.NewClient(cfg.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	facade := facades.NewMetricUpdateGRPCFacade(pb.NewMetricServiceClient(conn), cfg.GRPCAddr, "web02")
+
+	value := 2.5
+	require.NoError(t, facade.Updates(ctx, []types.Metrics{{ID: "Alloc", MType: types.Gauge, Value: &value}}))
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	resp, err := client.R().SetQueryParam("labels", "instance=web02").Get("/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, "2.5", resp.String())
+
+	var instances []types.Instance
+	_, err = client.R().SetResult(&instances).Get("/instances")
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "web02", instances[0].ID)
+
+	facade.Close()
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStart_HistogramMetrics(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     "127.0.0.1:37213",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	// две пачки с одинаковыми границами складываются по бакетам
+	for _, body := range []string{
+		`{"id":"latency","type":"histogram","histogram":{"bounds":[0.1,0.5,1],"counts":[30,10,0,0],"sum":4}}`,
+		`{"id":"latency","type":"histogram","histogram":{"bounds":[0.1,0.5,1],"counts":[20,30,10,0],"sum":16}}`,
+	} {
+		resp, err := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(body).
+			Post("/update/")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+	}
+
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"id":"latency","type":"histogram"}`).
+		Post("/value/")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"latency","type":"histogram","histogram":{"bounds":[0.1,0.5,1],"counts":[50,40,10,0],"sum":20}}`, resp.String())
+
+	resp, err = client.R().SetQueryParam("q", "0.95").Get("/value/histogram/latency")
+	require.NoError(t, err)
+	assert.Equal(t, "0.75", resp.String())
+
+	resp, err = client.R().Get("/value/histogram/latency")
+	require.NoError(t, err)
+	assert.Equal(t, "count=100 sum=20", resp.String())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"id":"latency","type":"histogram","histogram":{"bounds":[1],"counts":[1,0],"sum":1}}`).
+		Post("/update/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`[{"id":"latency","type":"histogram","histogram":{"bounds":[1,0.5],"counts":[1,0,0],"sum":1}}]`).
+		Post("/updates/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStart_SummaryMetrics(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     "127.0.0.1:37214",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	// каждое наблюдение дополняет скетч на сервере
+	for i := 1; i <= 100; i++ {
+		resp, err := client.R().Post("/update/summary/rt/" + strconv.Itoa(i))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode())
+	}
+
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"id":"rt","type":"summary","value":1000}`).
+		Post("/update/")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().Get("/value/summary/rt")
+	require.NoError(t, err)
+	assert.Equal(t, "count=101 sum=6050", resp.String())
+
+	resp, err = client.R().SetQueryParam("q", "0.5").Get("/value/summary/rt")
+	require.NoError(t, err)
+	median, err := strconv.ParseFloat(resp.String(), 64)
+	require.NoError(t, err)
+	assert.InEpsilon(t, 51, median, 0.02)
+
+	resp, err = client.R().SetQueryParam("q", "1").Get("/value/summary/rt")
+	require.NoError(t, err)
+	assert.Equal(t, "1000", resp.String())
+
+	resp, err = client.R().Post("/update/summary/rt/slow")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStart_SetMetrics(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:      "127.0.0.1:37215",
+		LogLevel:  "info",
+		SetWindow: 1,
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	client := resty.New().SetBaseURL("http://" + cfg.Addr)
+
+	// ждём начала нового секундного окна, чтобы обновления попали в одно окно
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	resp, err := client.R().Post("/update/set/users/alice")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"id":"users","type":"set","members":["alice","bob","carol"]}`).
+		Post("/update/")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().Get("/value/set/users")
+	require.NoError(t, err)
+	assert.Equal(t, "3", resp.String())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"id":"users","type":"set","members":[""]}`).
+		Post("/update/")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+
+	// после смены окна множество начинается заново
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	resp, err = client.R().Get("/value/set/users")
+	require.NoError(t, err)
+	assert.Equal(t, "0", resp.String())
+
+	resp, err = client.R().Post("/update/set/users/dave")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().Get("/value/set/users")
+	require.NoError(t, err)
+	assert.Equal(t, "1", resp.String())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestNewServerApp_InvalidSetWindow(t *testing.T) {
+	app, err := apps.NewServerApp(&configs.ServerConfig{Addr: ":0", LogLevel: "info", SetWindow: -1})
+	assert.ErrorIs(t, err, apps.ErrInvalidSetWindow)
+	assert.Nil(t, app)
+}
+
+func TestStart_GRPCSignedUpdates(t *testing.T) {
+	cfg := &configs.ServerConfig{
+		Addr:     "127.0.0.1:37219",
+		GRPCAddr: "127.0.0.1:37220",
+		Key:      "secret",
+		LogLevel: "info",
+	}
+
+	app, err := apps.NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	value := 1.5
+	metric := types.Metrics{ID: "Alloc", MType: types.Gauge, Value: &value}
+
+	// без подписи запись отклоняется
+	plain, err := grpc.NewClient(cfg.GRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer plain.Close()
+
+	_, err = pb.NewMetricServiceClient(plain).Update(ctx, &pb.UpdateRequest{Metric: pb.NewMetric(metric)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	unsigned := facades.NewMetricUpdateGRPCFacade(pb.NewMetricServiceClient(plain), cfg.GRPCAddr, "")
+	assert.Error(t, unsigned.Updates(ctx, []types.Metrics{metric}))
+	unsigned.Close()
+
+	signed, err := grpc.NewClient(
+		cfg.GRPCAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(interceptors.HashUnaryClientInterceptor(cfg.Key)),
+		grpc.WithChainStreamInterceptor(interceptors.HashStreamClientInterceptor(cfg.Key)),
+	)
+	require.NoError(t, err)
+	defer signed.Close()
+
+	_, err = pb.NewMetricServiceClient(signed).Update(ctx, &pb.UpdateRequest{Metric: pb.NewMetric(metric)})
+	require.NoError(t, err)
+
+	facade := facades.NewMetricUpdateGRPCFacade(pb.NewMetricServiceClient(signed), cfg.GRPCAddr, "")
+	value = 2.5
+	require.NoError(t, facade.Updates(ctx, []types.Metrics{metric}))
+
+	resp, err := resty.New().R().Get("http://" + cfg.Addr + "/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, "2.5", resp.String())
+
+	facade.Close()
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
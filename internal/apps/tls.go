@@ -0,0 +1,106 @@
+package apps
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+)
+
+var (
+	ErrTLSCertKeyPair   = errors.New("TLS certificate and key must be set together")
+	ErrTLSClientCANoTLS = errors.New("TLS client CA requires server certificate and key")
+	ErrInvalidCAFile    = errors.New("no certificates found in CA file")
+	ErrTLSPlainAddress  = errors.New("TLS is configured but server address uses http://")
+)
+
+func newServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return nil, ErrTLSClientCANoTLS
+		}
+		return nil, nil
+	}
+
+	if certFile == "" || keyFile == "" {
+		return nil, ErrTLSCertKeyPair
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		logger.Log.Errorw("Failed to load server certificate", "cert", certFile, "key", keyFile, "error", err)
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		pool, err := newCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		logger.Log.Infow("Client certificate verification enabled", "ca", clientCAFile)
+	}
+
+	logger.Log.Infow("TLS enabled", "cert", certFile)
+
+	return cfg, nil
+}
+
+func newClientTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, ErrTLSCertKeyPair
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		pool, err := newCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			logger.Log.Errorw("Failed to load client certificate", "cert", certFile, "key", keyFile, "error", err)
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	logger.Log.Infow("TLS enabled", "ca", caFile, "cert", certFile)
+
+	return cfg, nil
+}
+
+func newCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Log.Errorw("Failed to read CA file", "path", path, "error", err)
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		logger.Log.Errorw("Failed to parse CA file", "path", path)
+		return nil, ErrInvalidCAFile
+	}
+
+	return pool, nil
+}
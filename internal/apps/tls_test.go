@@ -0,0 +1,270 @@
+package apps
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/sbilibin2017/yp-metrics/internal/configs"
+	"github.com/sbilibin2017/yp-metrics/internal/facades"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCerts struct {
+	caFile         string
+	serverCertFile string
+	serverKeyFile  string
+	clientCertFile string
+	clientKeyFile  string
+}
+
+func writeTestCerts(t *testing.T, dir string) testCerts {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "yp-metrics test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	serverCert, serverKey := issue(2, "server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := issue(3, "agent", x509.ExtKeyUsageClientAuth)
+
+	certs := testCerts{
+		caFile:         filepath.Join(dir, "ca.pem"),
+		serverCertFile: filepath.Join(dir, "server.pem"),
+		serverKeyFile:  filepath.Join(dir, "server-key.pem"),
+		clientCertFile: filepath.Join(dir, "client.pem"),
+		clientKeyFile:  filepath.Join(dir, "client-key.pem"),
+	}
+
+	require.NoError(t, os.WriteFile(certs.caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0600))
+	require.NoError(t, os.WriteFile(certs.serverCertFile, serverCert, 0600))
+	require.NoError(t, os.WriteFile(certs.serverKeyFile, serverKey, 0600))
+	require.NoError(t, os.WriteFile(certs.clientCertFile, clientCert, 0600))
+	require.NoError(t, os.WriteFile(certs.clientKeyFile, clientKey, 0600))
+
+	return certs
+}
+
+func TestNewServerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certs := writeTestCerts(t, dir)
+
+	notPEM := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0600))
+
+	tests := []struct {
+		name       string
+		cert       string
+		key        string
+		clientCA   string
+		wantNil    bool
+		wantErr    error
+		anyErr     bool
+		clientAuth tls.ClientAuthType
+	}{
+		{name: "disabled", wantNil: true},
+		{name: "tls", cert: certs.serverCertFile, key: certs.serverKeyFile, clientAuth: tls.NoClientCert},
+		{name: "mtls", cert: certs.serverCertFile, key: certs.serverKeyFile, clientCA: certs.caFile, clientAuth: tls.RequireAndVerifyClientCert},
+		{name: "cert without key", cert: certs.serverCertFile, wantErr: ErrTLSCertKeyPair},
+		{name: "client CA without cert", clientCA: certs.caFile, wantErr: ErrTLSClientCANoTLS},
+		{name: "invalid client CA", cert: certs.serverCertFile, key: certs.serverKeyFile, clientCA: notPEM, wantErr: ErrInvalidCAFile},
+		{name: "mismatched key", cert: certs.serverCertFile, key: certs.clientKeyFile, anyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newServerTLSConfig(tt.cert, tt.key, tt.clientCA)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.anyErr:
+				assert.Error(t, err)
+			case tt.wantNil:
+				assert.NoError(t, err)
+				assert.Nil(t, cfg)
+			default:
+				require.NoError(t, err)
+				require.NotNil(t, cfg)
+				assert.Len(t, cfg.Certificates, 1)
+				assert.Equal(t, tt.clientAuth, cfg.ClientAuth)
+			}
+		})
+	}
+}
+
+func TestNewClientTLSConfig(t *testing.T) {
+	certs := writeTestCerts(t, t.TempDir())
+
+	cfg, err := newClientTLSConfig("", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	cfg, err = newClientTLSConfig(certs.caFile, "", "")
+	require.NoError(t, err)
+	assert.NotNil(t, cfg.RootCAs)
+	assert.Empty(t, cfg.Certificates)
+
+	cfg, err = newClientTLSConfig(certs.caFile, certs.clientCertFile, certs.clientKeyFile)
+	require.NoError(t, err)
+	assert.Len(t, cfg.Certificates, 1)
+
+	_, err = newClientTLSConfig(certs.caFile, certs.clientCertFile, "")
+	assert.ErrorIs(t, err, ErrTLSCertKeyPair)
+
+	_, err = newClientTLSConfig(filepath.Join(t.TempDir(), "missing.pem"), "", "")
+	assert.Error(t, err)
+}
+
+func TestServerApp_MutualTLS(t *testing.T) {
+	certs := writeTestCerts(t, t.TempDir())
+
+	cfg := &configs.ServerConfig{
+		Addr:            "127.0.0.1:37204",
+		LogLevel:        "info",
+		TLSCertFile:     certs.serverCertFile,
+		TLSKeyFile:      certs.serverKeyFile,
+		TLSClientCAFile: certs.caFile,
+	}
+
+	app, err := NewServerApp(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- app.Start(ctx)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	clientTLS, err := newClientTLSConfig(certs.caFile, certs.clientCertFile, certs.clientKeyFile)
+	require.NoError(t, err)
+
+	val := 1.5
+	facade := facades.NewMetricUpdateFacade(resty.New().SetTLSClientConfig(clientTLS), "https://"+cfg.Addr, "", nil, "")
+	require.NoError(t, facade.Updates(ctx, []types.Metrics{{ID: "Alloc", MType: types.Gauge, Value: &val}}))
+
+	resp, err := resty.New().SetTLSClientConfig(clientTLS).R().Get("https://" + cfg.Addr + "/value/gauge/Alloc")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Equal(t, "1.5", resp.String())
+
+	// Без клиентского сертификата рукопожатие не проходит
+	noCertTLS, err := newClientTLSConfig(certs.caFile, "", "")
+	require.NoError(t, err)
+	_, err = resty.New().SetTLSClientConfig(noCertTLS).R().Get("https://" + cfg.Addr + "/value/gauge/Alloc")
+	assert.Error(t, err)
+
+	// Без CA сертификат сервера не проходит проверку
+	_, err = resty.New().R().Get("https://" + cfg.Addr + "/value/gauge/Alloc")
+	assert.Error(t, err)
+
+	cancel()
+
+	err = <-done
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestNewAgentApp_TLS(t *testing.T) {
+	certs := writeTestCerts(t, t.TempDir())
+
+	app, err := NewAgentApp(&configs.AgentConfig{
+		Address:        "localhost:8080",
+		PollInterval:   1,
+		ReportInterval: 1,
+		LogLevel:       "info",
+		TLSCAFile:      certs.caFile,
+		TLSCertFile:    certs.clientCertFile,
+		TLSKeyFile:     certs.clientKeyFile,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, app)
+
+	app, err = NewAgentApp(&configs.AgentConfig{
+		Address:        "localhost:8080",
+		PollInterval:   1,
+		ReportInterval: 1,
+		LogLevel:       "info",
+		TLSCertFile:    certs.clientCertFile,
+	})
+	assert.ErrorIs(t, err, ErrTLSCertKeyPair)
+	assert.Nil(t, app)
+
+	// по gRPC шифрование обеспечивает TLS-канал
+	app, err = NewAgentApp(&configs.AgentConfig{
+		PollInterval:   1,
+		ReportInterval: 1,
+		LogLevel:       "info",
+		Protocol:       ProtocolGRPC,
+		GRPCAddress:    "localhost:3200",
+		CryptoKey:      "public.pem",
+		TLSCAFile:      certs.caFile,
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, app)
+
+	// TLS не включается молча для адреса со схемой http://
+	app, err = NewAgentApp(&configs.AgentConfig{
+		Address:        "http://localhost:8080",
+		PollInterval:   1,
+		ReportInterval: 1,
+		LogLevel:       "info",
+		TLSCAFile:      certs.caFile,
+	})
+	assert.ErrorIs(t, err, ErrTLSPlainAddress)
+	assert.Nil(t, app)
+}
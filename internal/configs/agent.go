@@ -1,10 +1,25 @@
 package configs
 
 type AgentConfig struct {
-	Address        string
-	PollInterval   int
-	ReportInterval int
-	LogLevel       string
+	Address              string
+	PollInterval         int
+	ReportInterval       int
+	RateLimit            int
+	LogLevel             string
+	Protocol             string
+	GRPCAddress          string
+	Key                  string
+	CryptoKey            string
+	TLSCAFile            string
+	TLSCertFile          string
+	TLSKeyFile           string
+	SpoolDir             string
+	SpoolMaxSize         int
+	Collectors           string
+	DisabledCollectors   string
+	CollectorIntervals   string
+	CustomCollectorsPath string
+	Instance             string
 }
 
 type AgentOption func(cfg *AgentConfig)
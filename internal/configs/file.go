@@ -0,0 +1,156 @@
+package configs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownConfigKey   = errors.New("unknown config key")
+	ErrInvalidConfigValue = errors.New("invalid config value")
+)
+
+func ApplyConfigFile(fs *flag.FlagSet, path string, keys map[string]string) error {
+	if path == "" {
+		return nil
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	for key, raw := range values {
+		name, ok := keys[key]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownConfigKey, key)
+		}
+
+		if explicit[name] {
+			continue
+		}
+
+		f := fs.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownConfigKey, key)
+		}
+
+		value, err := configValueString(f, raw)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfigValue, key, err)
+		}
+
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfigValue, key, err)
+		}
+	}
+
+	return nil
+}
+
+func readConfigFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	default:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&values); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func configValueString(f *flag.Flag, raw any) (string, error) {
+	switch v := raw.(type) {
+	case string:
+		if _, isInt := f.Value.(flag.Getter).Get().(int); isInt {
+			if _, err := strconv.Atoi(v); err != nil {
+				return durationSeconds(v)
+			}
+		}
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			part, err := configScalarString(f, item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, ","), nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		parts := make([]string, 0, len(v))
+		for _, key := range keys {
+			part, err := configScalarString(f, v[key])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, key+"="+part)
+		}
+		return strings.Join(parts, ","), nil
+	case nil:
+		return "", errors.New("null value")
+	default:
+		return "", fmt.Errorf("unsupported value type %T", raw)
+	}
+}
+
+func configScalarString(f *flag.Flag, raw any) (string, error) {
+	switch raw.(type) {
+	case []any, map[string]any:
+		return "", fmt.Errorf("nested value type %T", raw)
+	}
+	return configValueString(f, raw)
+}
+
+func durationSeconds(s string) (string, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return "", err
+	}
+
+	if d%time.Second != 0 {
+		return "", fmt.Errorf("duration %s is not a whole number of seconds", s)
+	}
+
+	return strconv.Itoa(int(d / time.Second)), nil
+}
@@ -0,0 +1,165 @@
+package configs_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/configs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfigKeys = map[string]string{
+	"address":        "a",
+	"store_interval": "i",
+	"restore":        "r",
+}
+
+type testFlags struct {
+	fs       *flag.FlagSet
+	addr     *string
+	interval *int
+	restore  *bool
+}
+
+func newTestFlags() testFlags {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	return testFlags{
+		fs:       fs,
+		addr:     fs.String("a", ":8080", ""),
+		interval: fs.Int("i", 300, ""),
+		restore:  fs.Bool("r", true, ""),
+	}
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestApplyConfigFile(t *testing.T) {
+	tests := []struct {
+		name             string
+		file             string
+		content          string
+		args             []string
+		expectedAddr     string
+		expectedInterval int
+		expectedRestore  bool
+	}{
+		{
+			name:             "json file overrides defaults",
+			file:             "config.json",
+			content:          `{"address": "file:9000", "store_interval": 5, "restore": false}`,
+			expectedAddr:     "file:9000",
+			expectedInterval: 5,
+			expectedRestore:  false,
+		},
+		{
+			name:             "yaml file with duration",
+			file:             "config.yaml",
+			content:          "address: yaml:9000\nstore_interval: 1m\n",
+			expectedAddr:     "yaml:9000",
+			expectedInterval: 60,
+			expectedRestore:  true,
+		},
+		{
+			name:             "json duration string",
+			file:             "config.json",
+			content:          `{"store_interval": "1s"}`,
+			expectedAddr:     ":8080",
+			expectedInterval: 1,
+			expectedRestore:  true,
+		},
+		{
+			name:             "flags override file",
+			file:             "config.json",
+			content:          `{"address": "file:9000", "store_interval": "10s"}`,
+			args:             []string{"-a", "flag:9000"},
+			expectedAddr:     "flag:9000",
+			expectedInterval: 10,
+			expectedRestore:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file, tt.content)
+
+			f := newTestFlags()
+			require.NoError(t, f.fs.Parse(tt.args))
+			require.NoError(t, configs.ApplyConfigFile(f.fs, path, testConfigKeys))
+
+			assert.Equal(t, tt.expectedAddr, *f.addr)
+			assert.Equal(t, tt.expectedInterval, *f.interval)
+			assert.Equal(t, tt.expectedRestore, *f.restore)
+		})
+	}
+}
+
+func TestApplyConfigFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr error
+	}{
+		{name: "unknown json key", file: "config.json", content: `{"adress": "x"}`, wantErr: configs.ErrUnknownConfigKey},
+		{name: "unknown yaml key", file: "config.yml", content: "foo: bar\n", wantErr: configs.ErrUnknownConfigKey},
+		{name: "invalid duration", file: "config.json", content: `{"store_interval": "soon"}`, wantErr: configs.ErrInvalidConfigValue},
+		{name: "fractional duration", file: "config.json", content: `{"store_interval": "1500ms"}`, wantErr: configs.ErrInvalidConfigValue},
+		{name: "invalid bool", file: "config.json", content: `{"restore": "maybe"}`, wantErr: configs.ErrInvalidConfigValue},
+		{name: "nested value", file: "config.json", content: `{"address": {"host": {"name": "x"}}}`, wantErr: configs.ErrInvalidConfigValue},
+		{name: "nested list", file: "config.json", content: `{"address": [["x"]]}`, wantErr: configs.ErrInvalidConfigValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file, tt.content)
+
+			f := newTestFlags()
+			require.NoError(t, f.fs.Parse(nil))
+
+			err := configs.ApplyConfigFile(f.fs, path, testConfigKeys)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestApplyConfigFile_ListsAndMaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{name: "json list", file: "config.json", content: `{"address": ["runtime", "system"]}`, expected: "runtime,system"},
+		{name: "yaml list", file: "config.yaml", content: "address:\n  - runtime\n  - system\n", expected: "runtime,system"},
+		{name: "yaml map", file: "config.yaml", content: "address:\n  system: 10s\n  queue: 1m\n", expected: "queue=1m,system=10s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file, tt.content)
+
+			f := newTestFlags()
+			require.NoError(t, f.fs.Parse(nil))
+
+			require.NoError(t, configs.ApplyConfigFile(f.fs, path, testConfigKeys))
+			assert.Equal(t, tt.expected, *f.addr)
+		})
+	}
+}
+
+func TestApplyConfigFile_InvalidFile(t *testing.T) {
+	f := newTestFlags()
+	require.NoError(t, f.fs.Parse(nil))
+
+	assert.NoError(t, configs.ApplyConfigFile(f.fs, "", testConfigKeys))
+	assert.Error(t, configs.ApplyConfigFile(f.fs, filepath.Join(t.TempDir(), "missing.json"), testConfigKeys))
+	assert.Error(t, configs.ApplyConfigFile(f.fs, writeConfigFile(t, "config.json", "{"), testConfigKeys))
+	assert.Error(t, configs.ApplyConfigFile(f.fs, writeConfigFile(t, "config.yaml", "a: [\n"), testConfigKeys))
+}
@@ -1,12 +1,30 @@
 package configs
 
 type ServerConfig struct {
-	Addr            string
-	StoreInterval   int
-	FileStoragePath string
-	Restore         bool
-	DatabaseDSN     string
-	LogLevel        string
+	Addr                   string
+	StoreInterval          int
+	FileStoragePath        string
+	Restore                bool
+	DatabaseDSN            string
+	LogLevel               string
+	AlertRulesPath         string
+	AlertInterval          int
+	HistoryFilePath        string
+	HistorySize            int
+	HistoryRetention       int
+	GRPCAddr               string
+	Key                    string
+	CryptoKey              string
+	TrustedSubnet          string
+	TLSCertFile            string
+	TLSKeyFile             string
+	TLSClientCAFile        string
+	StatsDAddr             string
+	StatsDFlushInterval    int
+	GraphiteAddr           string
+	GraphiteFlushInterval  int
+	GraphiteMaxConnections int
+	SetWindow              int
 }
 
 type ServerOption func(*ServerConfig)
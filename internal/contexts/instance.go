@@ -0,0 +1,16 @@
+package contexts
+
+import "context"
+
+type instanceKeyType struct{}
+
+var instanceKey = instanceKeyType{}
+
+func SetInstanceToContext(ctx context.Context, instance string) context.Context {
+	return context.WithValue(ctx, instanceKey, instance)
+}
+
+func GetInstanceFromContext(ctx context.Context) string {
+	instance, _ := ctx.Value(instanceKey).(string)
+	return instance
+}
@@ -0,0 +1,16 @@
+package contexts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAndGetInstanceFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, GetInstanceFromContext(ctx))
+
+	ctx = SetInstanceToContext(ctx, "web01")
+	assert.Equal(t, "web01", GetInstanceFromContext(ctx))
+}
@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/rsa"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 	"time"
 
@@ -13,23 +17,37 @@ import (
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 )
 
+var ErrMetricsRejected = errors.New("metrics rejected by server")
+
 type MetricUpdateFacade struct {
 	client     *resty.Client
 	serverAddr string
+	key        string
+	publicKey  *rsa.PublicKey
+	instance   string
 }
 
-func NewMetricUpdateFacade(client *resty.Client, serverAddr string) *MetricUpdateFacade {
+func NewMetricUpdateFacade(
+	client *resty.Client,
+	serverAddr string,
+	key string,
+	publicKey *rsa.PublicKey,
+	instance string,
+) *MetricUpdateFacade {
 	client.
 		SetRetryCount(3).
 		SetRetryWaitTime(1 * time.Second).
 		SetRetryMaxWaitTime(5 * time.Second).
 		AddRetryCondition(func(r *resty.Response, err error) bool {
-			return err != nil || (r != nil && r.StatusCode() >= 400)
+			return err != nil || (r != nil && r.StatusCode() >= 500)
 		})
 
 	return &MetricUpdateFacade{
 		client:     client,
 		serverAddr: serverAddr,
+		key:        key,
+		publicKey:  publicKey,
+		instance:   instance,
 	}
 }
 
@@ -40,15 +58,33 @@ func (f *MetricUpdateFacade) Updates(ctx context.Context, req []types.Metrics) e
 	}
 	addr += "/updates/"
 
-	compressedBody, err := compressBody(req)
+	body, err := compressBody(req)
 
 	if err != nil {
 		return err
 	}
 
-	resp, err := f.client.R().
+	if f.publicKey != nil {
+		body, err = types.EncryptRSA(f.publicKey, body)
+		if err != nil {
+			return err
+		}
+	}
+
+	request := f.client.R()
+	if ip, err := getOutboundIP(addr); err == nil {
+		request.SetHeader("X-Real-IP", ip.String())
+	}
+	if f.key != "" {
+		request.SetHeader(types.HashSHA256Header, types.GetHashSHA256(types.InstanceHashData(f.instance, body), f.key))
+	}
+	if f.instance != "" {
+		request.SetHeader(types.InstanceIDHeader, f.instance)
+	}
+
+	resp, err := request.
 		SetContext(ctx).
-		SetBody(compressedBody).
+		SetBody(body).
 		SetHeader("Content-Type", "application/json").
 		SetHeader("Content-Encoding", "gzip").
 		Post(addr)
@@ -57,9 +93,12 @@ func (f *MetricUpdateFacade) Updates(ctx context.Context, req []types.Metrics) e
 		return err
 	}
 
-	if resp.StatusCode() >= 400 {
+	if resp.StatusCode() >= 500 {
 		return fmt.Errorf("server returned status %d: %s", resp.StatusCode(), resp.String())
 	}
+	if resp.StatusCode() >= 400 {
+		return fmt.Errorf("%w: server returned status %d: %s", ErrMetricsRejected, resp.StatusCode(), resp.String())
+	}
 
 	return nil
 }
@@ -84,3 +123,27 @@ func compressBody(data []types.Metrics) ([]byte, error) {
 
 	return buf.Bytes(), nil
 }
+
+func getOutboundIP(addr string) (net.IP, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
@@ -0,0 +1,127 @@
+package facades
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type MetricUpdateGRPCFacade struct {
+	mu           sync.Mutex
+	client       pb.MetricServiceClient
+	serverAddr   string
+	instance     string
+	stream       pb.MetricService_UpdatesClient
+	cancelStream context.CancelFunc
+}
+
+func NewMetricUpdateGRPCFacade(client pb.MetricServiceClient, serverAddr string, instance string) *MetricUpdateGRPCFacade {
+	return &MetricUpdateGRPCFacade{client: client, serverAddr: serverAddr, instance: instance}
+}
+
+func (f *MetricUpdateGRPCFacade) Updates(ctx context.Context, req []types.Metrics) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stream, err := f.getStream()
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&pb.UpdatesRequest{Metrics: pb.NewMetrics(req)}); err != nil {
+		f.resetStream()
+		return err
+	}
+
+	type recvResult struct {
+		resp *pb.UpdatesResponse
+		err  error
+	}
+	recvCh := make(chan recvResult, 1)
+	go func() {
+		resp, err := stream.Recv()
+		recvCh <- recvResult{resp: resp, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		f.resetStream()
+		return ctx.Err()
+	case res := <-recvCh:
+		if res.err != nil {
+			f.resetStream()
+			if isRejected(res.err) {
+				return fmt.Errorf("%w: %v", ErrMetricsRejected, res.err)
+			}
+			return res.err
+		}
+		if res.resp.GetError() != "" {
+			return fmt.Errorf("%w: %s", ErrMetricsRejected, res.resp.GetError())
+		}
+	}
+
+	return nil
+}
+
+func (f *MetricUpdateGRPCFacade) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.stream != nil {
+		f.stream.CloseSend()
+	}
+	f.resetStream()
+}
+
+func (f *MetricUpdateGRPCFacade) getStream() (pb.MetricService_UpdatesClient, error) {
+	if f.stream != nil {
+		return f.stream, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if ip, err := getOutboundIP("grpc://" + f.serverAddr); err == nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-real-ip", ip.String())
+	}
+	if f.instance != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, types.InstanceIDHeader, f.instance)
+	}
+
+	stream, err := f.client.Updates(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	f.stream = stream
+	f.cancelStream = cancel
+
+	return stream, nil
+}
+
+func isRejected(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument,
+		codes.NotFound,
+		codes.AlreadyExists,
+		codes.PermissionDenied,
+		codes.FailedPrecondition,
+		codes.Unimplemented,
+		codes.Unauthenticated:
+		return true
+	}
+	return false
+}
+
+func (f *MetricUpdateGRPCFacade) resetStream() {
+	if f.cancelStream != nil {
+		f.cancelStream()
+	}
+	f.stream = nil
+	f.cancelStream = nil
+}
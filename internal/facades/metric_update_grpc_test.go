@@ -0,0 +1,156 @@
+package facades
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testMetricServer struct {
+	pb.UnimplementedMetricServiceServer
+	realIP   atomic.Value
+	instance atomic.Value
+	streams  atomic.Int32
+	received chan []*pb.Metric
+	respond  func(req *pb.UpdatesRequest) (*pb.UpdatesResponse, error)
+}
+
+func (s *testMetricServer) Updates(stream pb.MetricService_UpdatesServer) error {
+	s.streams.Add(1)
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok && len(md.Get("x-real-ip")) > 0 {
+		s.realIP.Store(md.Get("x-real-ip")[0])
+	}
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok && len(md.Get(types.InstanceIDHeader)) > 0 {
+		s.instance.Store(md.Get(types.InstanceIDHeader)[0])
+	}
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.received <- req.GetMetrics()
+
+		resp, err := s.respond(req)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func newTestGRPCClient(t *testing.T, srv *testMetricServer) pb.MetricServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+
+	s := grpc.NewServer()
+	pb.RegisterMetricServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMetricServiceClient(conn)
+}
+
+func TestMetricUpdateGRPCFacade_Updates_ReusesStream(t *testing.T) {
+	srv := &testMetricServer{
+		received: make(chan []*pb.Metric, 10),
+		respond: func(req *pb.UpdatesRequest) (*pb.UpdatesResponse, error) {
+			return &pb.UpdatesResponse{Updated: int32(len(req.GetMetrics()))}, nil
+		},
+	}
+	facade := NewMetricUpdateGRPCFacade(newTestGRPCClient(t, srv), "127.0.0.1:3200", "web01")
+	defer facade.Close()
+
+	val := 42.0
+	req := []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: &val}}
+
+	require.NoError(t, facade.Updates(context.Background(), req))
+	require.NoError(t, facade.Updates(context.Background(), req))
+
+	assert.Equal(t, "metric1", (<-srv.received)[0].GetId())
+	assert.Equal(t, "metric1", (<-srv.received)[0].GetId())
+	assert.Equal(t, int32(1), srv.streams.Load())
+	assert.Equal(t, "127.0.0.1", srv.realIP.Load())
+	assert.Equal(t, "web01", srv.instance.Load())
+}
+
+func TestMetricUpdateGRPCFacade_Updates_ServerReportsError(t *testing.T) {
+	srv := &testMetricServer{
+		received: make(chan []*pb.Metric, 10),
+		respond: func(req *pb.UpdatesRequest) (*pb.UpdatesResponse, error) {
+			return &pb.UpdatesResponse{Error: "metric value is required"}, nil
+		},
+	}
+	facade := NewMetricUpdateGRPCFacade(newTestGRPCClient(t, srv), "127.0.0.1:3200", "")
+	defer facade.Close()
+
+	err := facade.Updates(context.Background(), []types.Metrics{{ID: "metric1", MType: types.Gauge}})
+	assert.ErrorIs(t, err, ErrMetricsRejected)
+	assert.Contains(t, err.Error(), "metric value is required")
+}
+
+func TestMetricUpdateGRPCFacade_Updates_ReconnectsAfterStreamFailure(t *testing.T) {
+	var calls atomic.Int32
+	srv := &testMetricServer{
+		received: make(chan []*pb.Metric, 10),
+		respond: func(req *pb.UpdatesRequest) (*pb.UpdatesResponse, error) {
+			if calls.Add(1) == 1 {
+				return nil, errors.New("stream broken")
+			}
+			return &pb.UpdatesResponse{Updated: 1}, nil
+		},
+	}
+	facade := NewMetricUpdateGRPCFacade(newTestGRPCClient(t, srv), "127.0.0.1:3200", "")
+	defer facade.Close()
+
+	delta := int64(1)
+	req := []types.Metrics{{ID: "PollCount", MType: types.Counter, Delta: &delta}}
+
+	assert.Error(t, facade.Updates(context.Background(), req))
+	assert.NoError(t, facade.Updates(context.Background(), req))
+	assert.Equal(t, int32(2), srv.streams.Load())
+}
+
+func TestMetricUpdateGRPCFacade_Updates_ContextCanceled(t *testing.T) {
+	srv := &testMetricServer{
+		received: make(chan []*pb.Metric, 10),
+		respond: func(req *pb.UpdatesRequest) (*pb.UpdatesResponse, error) {
+			time.Sleep(time.Second)
+			return &pb.UpdatesResponse{}, nil
+		},
+	}
+	facade := NewMetricUpdateGRPCFacade(newTestGRPCClient(t, srv), "127.0.0.1:3200", "")
+	defer facade.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := facade.Updates(ctx, []types.Metrics{{ID: "metric1", MType: types.Gauge}})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
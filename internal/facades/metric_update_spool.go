@@ -0,0 +1,244 @@
+package facades
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+const spoolFileExt = ".json"
+
+type MetricsUpdater interface {
+	Updates(ctx context.Context, req []types.Metrics) error
+}
+
+type MetricUpdateSpoolFacade struct {
+	mu        sync.Mutex
+	next      MetricsUpdater
+	dir       string
+	maxSize   int64
+	seq       uint64
+	replaying bool
+}
+
+func NewMetricUpdateSpoolFacade(
+	next MetricsUpdater,
+	dir string,
+	maxSize int64,
+) *MetricUpdateSpoolFacade {
+	return &MetricUpdateSpoolFacade{
+		next:    next,
+		dir:     dir,
+		maxSize: maxSize,
+	}
+}
+
+func (f *MetricUpdateSpoolFacade) Updates(ctx context.Context, req []types.Metrics) error {
+	f.mu.Lock()
+	files, err := f.listFiles()
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		err := f.next.Updates(ctx, req)
+		if err == nil || errors.Is(err, ErrMetricsRejected) {
+			return err
+		}
+
+		f.mu.Lock()
+		spoolErr := f.store(req)
+		f.mu.Unlock()
+		if spoolErr != nil {
+			return fmt.Errorf("%w; failed to spool batch: %v", err, spoolErr)
+		}
+		return err
+	}
+
+	f.mu.Lock()
+	err = f.store(req)
+	replaying := f.replaying
+	if err == nil {
+		f.replaying = true
+	}
+	f.mu.Unlock()
+	if err != nil || replaying {
+		return err
+	}
+
+	return f.replay(ctx)
+}
+
+func (f *MetricUpdateSpoolFacade) replay(ctx context.Context) error {
+	for {
+		path, batch, err := f.nextBatch()
+		if err != nil || path == "" {
+			return err
+		}
+
+		err = f.next.Updates(ctx, batch)
+		if err != nil && !errors.Is(err, ErrMetricsRejected) {
+			f.mu.Lock()
+			f.replaying = false
+			f.mu.Unlock()
+			return err
+		}
+
+		f.mu.Lock()
+		removeErr := os.Remove(path)
+		f.mu.Unlock()
+		if removeErr != nil && !os.IsNotExist(removeErr) {
+			return removeErr
+		}
+
+		if err != nil {
+			logger.Log.Errorw("Dropping spooled batch rejected by server", "path", path, "size", len(batch), "error", err)
+			continue
+		}
+		logger.Log.Infow("Spooled batch delivered", "path", path, "size", len(batch))
+	}
+}
+
+func (f *MetricUpdateSpoolFacade) nextBatch() (string, []types.Metrics, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for {
+		files, err := f.listFiles()
+		if err != nil {
+			f.replaying = false
+			return "", nil, err
+		}
+		if len(files) == 0 {
+			f.replaying = false
+			return "", nil, nil
+		}
+
+		path := files[0].path
+		batch, err := f.load(path)
+		if err != nil {
+			logger.Log.Errorw("Dropping unreadable spooled batch", "path", path, "error", err)
+			os.Remove(path)
+			continue
+		}
+
+		return path, batch, nil
+	}
+}
+
+func (f *MetricUpdateSpoolFacade) store(req []types.Metrics) error {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	f.seq++
+	name := fmt.Sprintf("%020d-%010d%s", time.Now().UnixNano(), f.seq, spoolFileExt)
+	path := filepath.Join(f.dir, name)
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	logger.Log.Warnw("Batch spooled for later delivery", "path", path, "size", len(req))
+
+	return f.evict()
+}
+
+func (f *MetricUpdateSpoolFacade) evict() error {
+	if f.maxSize <= 0 {
+		return nil
+	}
+
+	files, err := f.listFiles()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+
+	for _, file := range files {
+		if total <= f.maxSize {
+			break
+		}
+		if err := os.Remove(file.path); err != nil {
+			return err
+		}
+		total -= file.size
+		logger.Log.Warnw("Spool size limit exceeded, oldest batch evicted", "path", file.path)
+	}
+
+	return nil
+}
+
+type spoolFile struct {
+	path string
+	size int64
+}
+
+func (f *MetricUpdateSpoolFacade) listFiles() ([]spoolFile, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []spoolFile
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), spoolFileExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, spoolFile{
+			path: filepath.Join(f.dir, entry.Name()),
+			size: info.Size(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+
+	return files, nil
+}
+
+func (f *MetricUpdateSpoolFacade) load(path string) ([]types.Metrics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch []types.Metrics
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
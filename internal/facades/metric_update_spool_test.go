@@ -0,0 +1,277 @@
+package facades
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spoolBatch(id string, delta int64) []types.Metrics {
+	return []types.Metrics{{ID: id, MType: types.Counter, Delta: &delta}}
+}
+
+func spoolFiles(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+spoolFileExt))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestMetricUpdateSpoolFacade_SuccessWithoutSpool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	next := NewMockMetricsUpdater(ctrl)
+	facade := NewMetricUpdateSpoolFacade(next, dir, 0)
+
+	batch := spoolBatch("PollCount", 1)
+	next.EXPECT().Updates(gomock.Any(), batch).Return(nil)
+
+	require.NoError(t, facade.Updates(context.Background(), batch))
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+func TestMetricUpdateSpoolFacade_FailureThenReplayInOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := filepath.Join(t.TempDir(), "spool")
+	next := NewMockMetricsUpdater(ctrl)
+	facade := NewMetricUpdateSpoolFacade(next, dir, 0)
+
+	first := spoolBatch("PollCount", 1)
+	second := spoolBatch("PollCount", 2)
+	third := spoolBatch("PollCount", 3)
+
+	errDown := errors.New("server down")
+
+	gomock.InOrder(
+		next.EXPECT().Updates(gomock.Any(), first).Return(errDown),
+		// сервер всё ещё недоступен: повтор упирается в первую пачку
+		next.EXPECT().Updates(gomock.Any(), first).Return(errDown),
+		next.EXPECT().Updates(gomock.Any(), first).Return(nil),
+		next.EXPECT().Updates(gomock.Any(), second).Return(nil),
+		next.EXPECT().Updates(gomock.Any(), third).Return(nil),
+	)
+
+	err := facade.Updates(context.Background(), first)
+	assert.ErrorIs(t, err, errDown)
+	assert.Len(t, spoolFiles(t, dir), 1)
+
+	err = facade.Updates(context.Background(), second)
+	assert.ErrorIs(t, err, errDown)
+	assert.Len(t, spoolFiles(t, dir), 2)
+
+	require.NoError(t, facade.Updates(context.Background(), third))
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+func TestMetricUpdateSpoolFacade_SurvivesRestart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	next := NewMockMetricsUpdater(ctrl)
+
+	first := spoolBatch("PollCount", 5)
+	second := spoolBatch("PollCount", 6)
+
+	next.EXPECT().Updates(gomock.Any(), first).Return(errors.New("server down"))
+	assert.Error(t, NewMetricUpdateSpoolFacade(next, dir, 0).Updates(context.Background(), first))
+
+	gomock.InOrder(
+		next.EXPECT().Updates(gomock.Any(), first).Return(nil),
+		next.EXPECT().Updates(gomock.Any(), second).Return(nil),
+	)
+
+	restarted := NewMetricUpdateSpoolFacade(next, dir, 0)
+	require.NoError(t, restarted.Updates(context.Background(), second))
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+func TestMetricUpdateSpoolFacade_EvictsOldest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	next := NewMockMetricsUpdater(ctrl)
+
+	batches := [][]types.Metrics{
+		spoolBatch("a", 1),
+		spoolBatch("b", 2),
+		spoolBatch("c", 3),
+	}
+
+	facade := NewMetricUpdateSpoolFacade(next, dir, 0)
+	require.NoError(t, facade.store(batches[0]))
+	files := spoolFiles(t, dir)
+	require.Len(t, files, 1)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+
+	// места хватает ровно на две пачки
+	facade.maxSize = 2 * info.Size()
+	require.NoError(t, facade.store(batches[1]))
+	require.NoError(t, facade.store(batches[2]))
+	assert.Len(t, spoolFiles(t, dir), 2)
+
+	errDown := errors.New("server down")
+	// новая пачка вытесняет "b", в очереди остаются "c" и "d"
+	gomock.InOrder(
+		next.EXPECT().Updates(gomock.Any(), batches[2]).Return(nil),
+		next.EXPECT().Updates(gomock.Any(), spoolBatch("d", 4)).Return(errDown),
+	)
+
+	err = facade.Updates(context.Background(), spoolBatch("d", 4))
+	assert.ErrorIs(t, err, errDown)
+	assert.Len(t, spoolFiles(t, dir), 1)
+}
+
+func TestMetricUpdateSpoolFacade_DropsCorruptedFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	next := NewMockMetricsUpdater(ctrl)
+	facade := NewMetricUpdateSpoolFacade(next, dir, 0)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000001-0000000001.json"), []byte("{broken"), 0644))
+
+	batch := spoolBatch("PollCount", 1)
+	next.EXPECT().Updates(gomock.Any(), batch).Return(nil)
+
+	require.NoError(t, facade.Updates(context.Background(), batch))
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+func TestMetricUpdateSpoolFacade_RejectedBatchNotSpooled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	next := NewMockMetricsUpdater(ctrl)
+	facade := NewMetricUpdateSpoolFacade(next, dir, 0)
+
+	batch := spoolBatch("PollCount", 1)
+	next.EXPECT().Updates(gomock.Any(), batch).Return(fmt.Errorf("%w: status 400", ErrMetricsRejected))
+
+	err := facade.Updates(context.Background(), batch)
+	assert.ErrorIs(t, err, ErrMetricsRejected)
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+func TestMetricUpdateSpoolFacade_DropsRejectedSpooledBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir := t.TempDir()
+	next := NewMockMetricsUpdater(ctrl)
+	facade := NewMetricUpdateSpoolFacade(next, dir, 0)
+
+	rejected := spoolBatch("a", 1)
+	batch := spoolBatch("b", 2)
+	require.NoError(t, facade.store(rejected))
+
+	// отклонённая сервером пачка удаляется и не блокирует очередь
+	gomock.InOrder(
+		next.EXPECT().Updates(gomock.Any(), rejected).Return(fmt.Errorf("%w: status 409", ErrMetricsRejected)),
+		next.EXPECT().Updates(gomock.Any(), batch).Return(nil),
+	)
+
+	require.NoError(t, facade.Updates(context.Background(), batch))
+	assert.Empty(t, spoolFiles(t, dir))
+}
+
+type blockingUpdater struct {
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (u *blockingUpdater) Updates(ctx context.Context, req []types.Metrics) error {
+	if u.calls.Add(1) == 1 {
+		close(u.started)
+		<-u.release
+	}
+	return nil
+}
+
+func TestMetricUpdateSpoolFacade_SendDoesNotHoldLock(t *testing.T) {
+	dir := t.TempDir()
+	updater := &blockingUpdater{started: make(chan struct{}), release: make(chan struct{})}
+	facade := NewMetricUpdateSpoolFacade(updater, dir, 0)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- facade.Updates(context.Background(), spoolBatch("slow", 1))
+	}()
+	<-updater.started
+
+	// медленная отправка не блокирует следующую пачку
+	second := make(chan error, 1)
+	go func() {
+		second <- facade.Updates(context.Background(), spoolBatch("fast", 1))
+	}()
+	select {
+	case err := <-second:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("second update blocked by in-flight send")
+	}
+
+	close(updater.release)
+	assert.NoError(t, <-done)
+}
+
+type concurrencyRecorder struct {
+	mu        sync.Mutex
+	delivered map[string]int
+}
+
+func (r *concurrencyRecorder) Updates(ctx context.Context, req []types.Metrics) error {
+	time.Sleep(5 * time.Millisecond)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.delivered[req[0].ID]++
+
+	return nil
+}
+
+func TestMetricUpdateSpoolFacade_ConcurrentReplay(t *testing.T) {
+	dir := t.TempDir()
+	recorder := &concurrencyRecorder{delivered: make(map[string]int)}
+	facade := NewMetricUpdateSpoolFacade(recorder, dir, 0)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, facade.store(spoolBatch(fmt.Sprintf("spooled%d", i), 1)))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, facade.Updates(context.Background(), spoolBatch(fmt.Sprintf("m%d", i), 1)))
+		}(i)
+	}
+	wg.Wait()
+
+	// досылка выполняется одним вызовом, и каждая пачка доставляется ровно один раз
+	require.Len(t, recorder.delivered, 12)
+	for id, count := range recorder.delivered {
+		assert.Equal(t, 1, count, id)
+	}
+	assert.Empty(t, spoolFiles(t, dir))
+}
@@ -1,15 +1,23 @@
 package facades
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMetricUpdateFacade_Update_Success(t *testing.T) {
@@ -20,7 +28,7 @@ func TestMetricUpdateFacade_Update_Success(t *testing.T) {
 	defer ts.Close()
 
 	client := resty.New()
-	facade := NewMetricUpdateFacade(client, ts.URL)
+	facade := NewMetricUpdateFacade(client, ts.URL, "", nil, "")
 
 	val := 42.0
 	m := types.Metrics{
@@ -43,7 +51,7 @@ func TestMetricUpdateFacade_Update_HTTPError(t *testing.T) {
 	defer ts.Close()
 
 	client := resty.New()
-	facade := NewMetricUpdateFacade(client, ts.URL)
+	facade := NewMetricUpdateFacade(client, ts.URL, "", nil, "")
 
 	val := int64(10)
 	m := types.Metrics{
@@ -59,6 +67,23 @@ func TestMetricUpdateFacade_Update_HTTPError(t *testing.T) {
 	assert.Contains(t, err.Error(), "server returned status 500")
 }
 
+func TestMetricUpdateFacade_Update_Rejected(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	facade := NewMetricUpdateFacade(resty.New(), ts.URL, "", nil, "")
+
+	val := 42.0
+	err := facade.Updates(context.Background(), []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: &val}})
+	assert.ErrorIs(t, err, ErrMetricsRejected)
+	// ответ 4xx не повторяется
+	assert.Equal(t, int32(1), calls.Load())
+}
+
 func TestMetricUpdateFacade_Update_ContextCanceled(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		select {}
@@ -66,7 +91,7 @@ func TestMetricUpdateFacade_Update_ContextCanceled(t *testing.T) {
 	defer ts.Close()
 
 	client := resty.New()
-	facade := NewMetricUpdateFacade(client, ts.URL)
+	facade := NewMetricUpdateFacade(client, ts.URL, "", nil, "")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -95,7 +120,7 @@ func TestMetricUpdateFacade_AddsHTTPPrefix(t *testing.T) {
 	addr = strings.TrimPrefix(addr, "https://")
 
 	client := resty.New()
-	facade := NewMetricUpdateFacade(client, addr)
+	facade := NewMetricUpdateFacade(client, addr, "", nil, "")
 
 	val := int64(10)
 	m := types.Metrics{
@@ -108,3 +133,127 @@ func TestMetricUpdateFacade_AddsHTTPPrefix(t *testing.T) {
 	err := facade.Updates(context.Background(), req)
 	assert.NoError(t, err)
 }
+
+func TestMetricUpdateFacade_Update_SignsBody(t *testing.T) {
+	const key = "secret"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, types.CheckHashSHA256(body, key, r.Header.Get(types.HashSHA256Header)))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	facade := NewMetricUpdateFacade(resty.New(), ts.URL, key, nil, "")
+
+	val := 42.0
+	err := facade.Updates(context.Background(), []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: &val}})
+	assert.NoError(t, err)
+}
+
+func TestMetricUpdateFacade_Update_NoKeyNoHash(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(types.HashSHA256Header))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	facade := NewMetricUpdateFacade(resty.New(), ts.URL, "", nil, "")
+
+	val := 42.0
+	err := facade.Updates(context.Background(), []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: &val}})
+	assert.NoError(t, err)
+}
+
+func TestMetricUpdateFacade_Update_SendsInstance(t *testing.T) {
+	const key = "secret"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "web01", r.Header.Get(types.InstanceIDHeader))
+		// идентификатор инстанса входит в подпись
+		data := types.InstanceHashData("web01", body)
+		assert.NoError(t, types.CheckHashSHA256(data, key, r.Header.Get(types.HashSHA256Header)))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	facade := NewMetricUpdateFacade(resty.New(), ts.URL, key, nil, "web01")
+
+	val := 42.0
+	err := facade.Updates(context.Background(), []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: &val}})
+	assert.NoError(t, err)
+}
+
+func TestMetricUpdateFacade_Update_EncryptsBody(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	val := 42.0
+	req := []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: &val}}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		plain, err := types.DecryptRSA(privateKey, body)
+		assert.NoError(t, err)
+
+		gzr, err := gzip.NewReader(bytes.NewReader(plain))
+		assert.NoError(t, err)
+
+		var got []types.Metrics
+		assert.NoError(t, json.NewDecoder(gzr).Decode(&got))
+		assert.Equal(t, req, got)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	facade := NewMetricUpdateFacade(resty.New(), ts.URL, "", &privateKey.PublicKey, "")
+
+	err = facade.Updates(context.Background(), req)
+	assert.NoError(t, err)
+}
+
+func TestMetricUpdateFacade_Update_SetsRealIP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "127.0.0.1", r.Header.Get("X-Real-IP"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	facade := NewMetricUpdateFacade(resty.New(), ts.URL, "", nil, "")
+
+	val := 42.0
+	err := facade.Updates(context.Background(), []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: &val}})
+	assert.NoError(t, err)
+}
+
+func TestGetOutboundIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "with port", addr: "http://127.0.0.1:8080/updates/", expected: "127.0.0.1"},
+		{name: "http default port", addr: "http://127.0.0.1/updates/", expected: "127.0.0.1"},
+		{name: "https default port", addr: "https://127.0.0.1/updates/", expected: "127.0.0.1"},
+		{name: "invalid url", addr: "http://[::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, err := getOutboundIP(tt.addr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ip.String())
+		})
+	}
+}
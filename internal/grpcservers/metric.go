@@ -0,0 +1,162 @@
+package grpcservers
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/jmoiron/sqlx"
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MetricUpdater interface {
+	Update(ctx context.Context, metric types.Metrics) error
+}
+
+type MetricGetter interface {
+	Get(ctx context.Context, id types.MetricID) (*types.Metrics, error)
+}
+
+type MetricLister interface {
+	List(ctx context.Context) ([]types.Metrics, error)
+}
+
+type MetricServer struct {
+	pb.UnimplementedMetricServiceServer
+
+	val     func(m types.Metrics) error
+	idVal   func(metricType string, metricName string) error
+	updater MetricUpdater
+	getter  MetricGetter
+	lister  MetricLister
+
+	db       *sqlx.DB
+	txSetter func(ctx context.Context, tx *sqlx.Tx) context.Context
+}
+
+func NewMetricServer(
+	val func(m types.Metrics) error,
+	idVal func(metricType string, metricName string) error,
+	updater MetricUpdater,
+	getter MetricGetter,
+	lister MetricLister,
+	db *sqlx.DB,
+	txSetter func(ctx context.Context, tx *sqlx.Tx) context.Context,
+) *MetricServer {
+	return &MetricServer{
+		val:      val,
+		idVal:    idVal,
+		updater:  updater,
+		getter:   getter,
+		lister:   lister,
+		db:       db,
+		txSetter: txSetter,
+	}
+}
+
+func (s *MetricServer) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.UpdateResponse, error) {
+	metric := req.GetMetric().ToMetrics()
+
+	if err := s.val(metric); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.updater.Update(ctx, metric); err != nil {
+		return nil, status.Error(codes.Internal, types.ErrInternalServerError.Error())
+	}
+
+	return &pb.UpdateResponse{Metric: pb.NewMetric(metric)}, nil
+}
+
+func (s *MetricServer) Updates(stream pb.MetricService_UpdatesServer) error {
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := stream.Send(s.updateBatch(stream.Context(), req.GetMetrics())); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *MetricServer) updateBatch(ctx context.Context, batch []*pb.Metric) *pb.UpdatesResponse {
+	metrics := make([]types.Metrics, 0, len(batch))
+	for _, m := range batch {
+		metric := m.ToMetrics()
+		if err := s.val(metric); err != nil {
+			return &pb.UpdatesResponse{Error: err.Error()}
+		}
+		metrics = append(metrics, metric)
+	}
+
+	if err := s.applyBatch(ctx, metrics); err != nil {
+		return &pb.UpdatesResponse{Error: types.ErrInternalServerError.Error()}
+	}
+
+	return &pb.UpdatesResponse{Updated: int32(len(metrics))}
+}
+
+func (s *MetricServer) applyBatch(ctx context.Context, metrics []types.Metrics) error {
+	if s.db == nil {
+		return s.update(ctx, metrics)
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if err := s.update(s.txSetter(ctx, tx), metrics); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *MetricServer) update(ctx context.Context, metrics []types.Metrics) error {
+	for _, metric := range metrics {
+		if err := s.updater.Update(ctx, metric); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MetricServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
+	id := req.GetId().ToMetricID()
+
+	if err := s.idVal(id.MType, id.ID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	metric, err := s.getter.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, types.ErrMetricNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, types.ErrInvalidLabels) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, types.ErrInternalServerError.Error())
+	}
+
+	return &pb.GetResponse{Metric: pb.NewMetric(*metric)}, nil
+}
+
+func (s *MetricServer) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
+	metrics, err := s.lister.List(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, types.ErrInternalServerError.Error())
+	}
+
+	return &pb.ListResponse{Metrics: pb.NewMetrics(metrics)}, nil
+}
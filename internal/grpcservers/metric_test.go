@@ -0,0 +1,249 @@
+package grpcservers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/yp-metrics/internal/contexts"
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func startMetricServer(t *testing.T, srv *MetricServer) pb.MetricServiceClient {
+	lis := bufconn.Listen(1024 * 1024)
+
+	s := grpc.NewServer()
+	pb.RegisterMetricServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMetricServiceClient(conn)
+}
+
+func TestMetricServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUpdater := NewMockMetricUpdater(ctrl)
+	mockGetter := NewMockMetricGetter(ctrl)
+	mockLister := NewMockMetricLister(ctrl)
+
+	client := startMetricServer(t, NewMetricServer(
+		validators.ValidateMetricBody,
+		validators.ValidateMetricIDPath,
+		mockUpdater,
+		mockGetter,
+		mockLister,
+		nil,
+		nil,
+	))
+
+	ctx := context.Background()
+	value := 1.5
+	delta := int64(2)
+	gauge := types.Metrics{ID: "Alloc", MType: types.Gauge, Value: &value}
+	counter := types.Metrics{ID: "PollCount", MType: types.Counter, Delta: &delta}
+
+	t.Run("update", func(t *testing.T) {
+		mockUpdater.EXPECT().Update(gomock.Any(), gauge).Return(nil)
+
+		resp, err := client.Update(ctx, &pb.UpdateRequest{Metric: pb.NewMetric(gauge)})
+		require.NoError(t, err)
+		assert.Equal(t, gauge, resp.GetMetric().ToMetrics())
+	})
+
+	t.Run("update invalid", func(t *testing.T) {
+		_, err := client.Update(ctx, &pb.UpdateRequest{Metric: &pb.Metric{Id: "x", Type: "unknown"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("update service error", func(t *testing.T) {
+		mockUpdater.EXPECT().Update(gomock.Any(), gauge).Return(errors.New("fail"))
+
+		_, err := client.Update(ctx, &pb.UpdateRequest{Metric: pb.NewMetric(gauge)})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("updates stream", func(t *testing.T) {
+		mockUpdater.EXPECT().Update(gomock.Any(), gauge).Return(nil).Times(2)
+		mockUpdater.EXPECT().Update(gomock.Any(), counter).Return(nil)
+
+		stream, err := client.Updates(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, stream.Send(&pb.UpdatesRequest{Metrics: pb.NewMetrics([]types.Metrics{gauge, counter})}))
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), resp.GetUpdated())
+		assert.Empty(t, resp.GetError())
+
+		require.NoError(t, stream.Send(&pb.UpdatesRequest{Metrics: []*pb.Metric{{Id: "bad", Type: types.Gauge}}}))
+		resp, err = stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), resp.GetUpdated())
+		assert.Equal(t, validators.ErrValueIsRequired.Error(), resp.GetError())
+
+		require.NoError(t, stream.Send(&pb.UpdatesRequest{Metrics: pb.NewMetrics([]types.Metrics{gauge})}))
+		resp, err = stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), resp.GetUpdated())
+
+		require.NoError(t, stream.CloseSend())
+	})
+
+	t.Run("updates stream service error", func(t *testing.T) {
+		mockUpdater.EXPECT().Update(gomock.Any(), gauge).Return(nil)
+		mockUpdater.EXPECT().Update(gomock.Any(), counter).Return(errors.New("fail"))
+
+		stream, err := client.Updates(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, stream.Send(&pb.UpdatesRequest{Metrics: pb.NewMetrics([]types.Metrics{gauge, counter})}))
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		// пакет применяется целиком или не применяется вовсе
+		assert.Equal(t, int32(0), resp.GetUpdated())
+		assert.Equal(t, types.ErrInternalServerError.Error(), resp.GetError())
+
+		require.NoError(t, stream.CloseSend())
+	})
+
+	t.Run("get", func(t *testing.T) {
+		mockGetter.EXPECT().Get(gomock.Any(), types.MetricID{ID: "Alloc", MType: types.Gauge}).Return(&gauge, nil)
+
+		resp, err := client.Get(ctx, &pb.GetRequest{Id: &pb.MetricID{Id: "Alloc", Type: types.Gauge}})
+		require.NoError(t, err)
+		assert.Equal(t, gauge, resp.GetMetric().ToMetrics())
+	})
+
+	t.Run("get with labels", func(t *testing.T) {
+		labeled := types.Metrics{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web01"}, Value: &value}
+		mockGetter.EXPECT().Get(gomock.Any(), types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "instance=web01"}).Return(&labeled, nil)
+
+		resp, err := client.Get(ctx, &pb.GetRequest{Id: &pb.MetricID{Id: "Alloc", Type: types.Gauge, Labels: map[string]string{"instance": "web01"}}})
+		require.NoError(t, err)
+		assert.Equal(t, labeled, resp.GetMetric().ToMetrics())
+	})
+
+	t.Run("get invalid labels", func(t *testing.T) {
+		mockGetter.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, types.ErrInvalidLabels)
+
+		_, err := client.Get(ctx, &pb.GetRequest{Id: &pb.MetricID{Id: "Alloc", Type: types.Gauge, Labels: map[string]string{"a=b": "c"}}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		mockGetter.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, types.ErrMetricNotFound)
+
+		_, err := client.Get(ctx, &pb.GetRequest{Id: &pb.MetricID{Id: "missing", Type: types.Gauge}})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("get internal error", func(t *testing.T) {
+		mockGetter.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, types.ErrInternalServerError)
+
+		_, err := client.Get(ctx, &pb.GetRequest{Id: &pb.MetricID{Id: "Alloc", Type: types.Gauge}})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("get invalid", func(t *testing.T) {
+		_, err := client.Get(ctx, &pb.GetRequest{Id: &pb.MetricID{Id: "Alloc"}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return([]types.Metrics{gauge, counter}, nil)
+
+		resp, err := client.List(ctx, &pb.ListRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.GetMetrics(), 2)
+		assert.Equal(t, counter, resp.GetMetrics()[1].ToMetrics())
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return(nil, errors.New("fail"))
+
+		_, err := client.List(ctx, &pb.ListRequest{})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestMetricServer_UpdatesTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mockUpdater := NewMockMetricUpdater(ctrl)
+
+	client := startMetricServer(t, NewMetricServer(
+		validators.ValidateMetricBody,
+		validators.ValidateMetricIDPath,
+		mockUpdater,
+		NewMockMetricGetter(ctrl),
+		NewMockMetricLister(ctrl),
+		sqlx.NewDb(db, "sqlmock"),
+		contexts.SetTxToContext,
+	))
+
+	value := 1.5
+	delta := int64(2)
+	gauge := types.Metrics{ID: "Alloc", MType: types.Gauge, Value: &value}
+	counter := types.Metrics{ID: "PollCount", MType: types.Counter, Delta: &delta}
+
+	stream, err := client.Updates(context.Background())
+	require.NoError(t, err)
+
+	// успешный пакет фиксируется одной транзакцией
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mockUpdater.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ types.Metrics) error {
+		assert.NotNil(t, contexts.GetTxFromContext(ctx))
+		return nil
+	}).Times(2)
+
+	require.NoError(t, stream.Send(&pb.UpdatesRequest{Metrics: pb.NewMetrics([]types.Metrics{gauge, counter})}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), resp.GetUpdated())
+
+	// ошибка любой метрики откатывает весь пакет
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mockUpdater.EXPECT().Update(gomock.Any(), gauge).Return(nil)
+	mockUpdater.EXPECT().Update(gomock.Any(), counter).Return(errors.New("fail"))
+
+	require.NoError(t, stream.Send(&pb.UpdatesRequest{Metrics: pb.NewMetrics([]types.Metrics{gauge, counter})}))
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), resp.GetUpdated())
+	assert.Equal(t, types.ErrInternalServerError.Error(), resp.GetError())
+
+	require.NoError(t, stream.CloseSend())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type AlertLister interface {
+	List(ctx context.Context) ([]types.Alert, error)
+}
+
+func AlertListHandler(svc AlertLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		alerts, err := svc.List(r.Context())
+		if err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if state := r.URL.Query().Get("state"); state != "" {
+			filtered := make([]types.Alert, 0, len(alerts))
+			for _, alert := range alerts {
+				if alert.State == state {
+					filtered = append(filtered, alert)
+				}
+			}
+			alerts = filtered
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(alerts); err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertListHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := NewMockAlertLister(ctrl)
+	handler := AlertListHandler(mockLister)
+
+	alerts := []types.Alert{
+		{Rule: types.AlertRule{Name: "a", Kind: types.AlertThreshold}, State: types.AlertFiring},
+		{Rule: types.AlertRule{Name: "b", Kind: types.AlertAbsence}, State: types.AlertResolved},
+	}
+
+	t.Run("all alerts", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return(alerts, nil)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/alerts", nil)
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var got []types.Alert
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Len(t, got, 2)
+	})
+
+	t.Run("filter by state", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return(alerts, nil)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/alerts?state=firing", nil)
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var got []types.Alert
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		require.Len(t, got, 1)
+		assert.Equal(t, "a", got[0].Rule.Name)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return(nil, errors.New("fail"))
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/alerts", nil)
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type InstanceLister interface {
+	List(ctx context.Context) ([]types.Instance, error)
+}
+
+func InstanceListHandler(svc InstanceLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		instances, err := svc.List(r.Context())
+		if err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(instances); err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceListHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := NewMockInstanceLister(ctrl)
+	handler := InstanceListHandler(mockLister)
+
+	t.Run("instances", func(t *testing.T) {
+		lastSeen := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		mockLister.EXPECT().List(gomock.Any()).Return([]types.Instance{{ID: "web01", LastSeen: lastSeen}}, nil)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/instances", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var got []map[string]any
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		require.Len(t, got, 1)
+		assert.Equal(t, "web01", got[0]["id"])
+		assert.Equal(t, "2025-01-01T12:00:00Z", got[0]["last_seen"])
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return(nil, errors.New("boom"))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/instances", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
@@ -14,7 +14,7 @@ type MetricGetterBody interface {
 }
 
 func MetricGetBodyHandler(
-	val func(metricType string, metricName string) error,
+	val func(id types.MetricID) error,
 	svc MetricGetterBody,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -27,12 +27,12 @@ func MetricGetBodyHandler(
 			return
 		}
 
-		err := val(metricID.MType, metricID.ID)
+		err := val(metricID)
 		if err != nil {
 			switch err {
 			case validators.ErrNameIsRequired:
 				http.Error(w, err.Error(), http.StatusNotFound)
-			case validators.ErrInvalidMetricType, validators.ErrTypeIsRequired:
+			case validators.ErrInvalidMetricType, validators.ErrTypeIsRequired, validators.ErrInvalidLabel:
 				http.Error(w, err.Error(), http.StatusBadRequest)
 			default:
 				http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
@@ -25,13 +25,16 @@ func TestMetricGetBodyHandler(t *testing.T) {
 
 	mockSvc := NewMockMetricGetterBody(ctrl)
 
-	validate := func(metricType, metricName string) error {
-		if metricName == "" {
+	validate := func(id types.MetricID) error {
+		if id.ID == "" {
 			return validators.ErrNameIsRequired
 		}
-		if metricType != "gauge" && metricType != "counter" {
+		if id.MType != "gauge" && id.MType != "counter" {
 			return validators.ErrInvalidMetricType
 		}
+		if id.Labels == "host=" {
+			return validators.ErrInvalidLabel
+		}
 		return nil
 	}
 
@@ -106,6 +109,19 @@ func TestMetricGetBodyHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("empty label value returns bad request", func(t *testing.T) {
+		body := `{"id":"metric1","type":"gauge","labels":{"host":""}}`
+		req := httptest.NewRequest(http.MethodPost, "/some-url", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
 	t.Run("service returns error results in 500", func(t *testing.T) {
 		metricID := types.MetricID{ID: "metric1", MType: "gauge"}
 
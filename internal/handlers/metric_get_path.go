@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 	"github.com/sbilibin2017/yp-metrics/internal/validators"
@@ -36,11 +38,18 @@ func MetricGetPathHandler(
 
 		}
 
+		labels, err := parseLabelsQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		metricID := types.NewMetricID(metricType, metricName)
 		if metricID == nil {
 			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
 			return
 		}
+		metricID.Labels = labels.String()
 
 		metric, err := svc.Get(r.Context(), *metricID)
 
@@ -55,6 +64,22 @@ func MetricGetPathHandler(
 			}
 		}
 
+		if r.URL.Query().Has("q") && (metric.MType == types.Histogram || metric.MType == types.Summary) {
+			valueString, err := getMetricQuantileString(*metric, r.URL.Query().Get("q"))
+			if err != nil {
+				if errors.Is(err, types.ErrInvalidQuantile) {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(valueString))
+			return
+		}
+
 		valueString, err := types.GetMetricValueString(*metric)
 
 		if err != nil {
@@ -67,3 +92,26 @@ func MetricGetPathHandler(
 		w.Write([]byte(valueString))
 	}
 }
+
+func parseLabelsQuery(r *http.Request) (types.Labels, error) {
+	labels, err := types.ParseLabels(r.URL.Query().Get("labels"))
+	if err != nil {
+		return nil, err
+	}
+	if err := validators.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func getMetricQuantileString(metric types.Metrics, raw string) (string, error) {
+	q, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return "", types.ErrInvalidQuantile
+	}
+	quantile, err := types.GetMetricQuantile(metric, q)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(quantile, 'f', -1, 64), nil
+}
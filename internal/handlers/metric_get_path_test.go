@@ -13,6 +13,7 @@ import (
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 	"github.com/sbilibin2017/yp-metrics/internal/validators"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMetricGetPathHandler(t *testing.T) {
@@ -136,3 +137,114 @@ func TestMetricGetPathHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricGetPathHandler_Labels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := NewMockMetricGetterPath(ctrl)
+
+	makeRequest := func(query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/value/gauge/Alloc?"+query, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("type", types.Gauge)
+		rctx.URLParams.Add("name", "Alloc")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	val := 7.0
+	mockGetter.EXPECT().
+		Get(gomock.Any(), types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "host=web01,region=eu"}).
+		Return(&types.Metrics{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"host": "web01", "region": "eu"}, Value: &val}, nil)
+
+	// порядок меток в запросе не важен — ключ строится по каноническому виду
+	rec := httptest.NewRecorder()
+	MetricGetPathHandler(validators.ValidateMetricIDPath, mockGetter).ServeHTTP(rec, makeRequest("labels=region%3Deu%2Chost%3Dweb01"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "7", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	MetricGetPathHandler(validators.ValidateMetricIDPath, mockGetter).ServeHTTP(rec, makeRequest("labels=host"))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestMetricGetPathHandler_HistogramQuantile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := NewMockMetricGetterPath(ctrl)
+
+	makeRequest := func(query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/value/histogram/latency?"+query, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("type", types.Histogram)
+		rctx.URLParams.Add("name", "latency")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	mockGetter.EXPECT().
+		Get(gomock.Any(), types.MetricID{ID: "latency", MType: types.Histogram}).
+		Return(&types.Metrics{
+			ID:        "latency",
+			MType:     types.Histogram,
+			Histogram: &types.HistogramValue{Bounds: []float64{0.1, 0.5, 1}, Counts: []int64{50, 40, 10, 0}, Sum: 20},
+		}, nil).
+		Times(5)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantBody string
+	}{
+		{"quantile", "q=0.5", http.StatusOK, "0.1"},
+		{"interpolated quantile", "q=0.95", http.StatusOK, "0.75"},
+		{"without quantile", "", http.StatusOK, "count=100 sum=20"},
+		{"invalid quantile", "q=2", http.StatusBadRequest, types.ErrInvalidQuantile.Error() + "\n"},
+		{"malformed quantile", "q=p99", http.StatusBadRequest, types.ErrInvalidQuantile.Error() + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			MetricGetPathHandler(validators.ValidateMetricIDPath, mockGetter).ServeHTTP(rec, makeRequest(tt.query))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestMetricGetPathHandler_SummaryQuantile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := NewMockMetricGetterPath(ctrl)
+
+	summary, err := types.NewSummaryValue(1).Merge(types.NewSummaryValue(2))
+	require.NoError(t, err)
+	summary, err = summary.Merge(types.NewSummaryValue(100))
+	require.NoError(t, err)
+
+	mockGetter.EXPECT().
+		Get(gomock.Any(), types.MetricID{ID: "rt", MType: types.Summary}).
+		Return(&types.Metrics{ID: "rt", MType: types.Summary, Summary: &summary}, nil).
+		Times(2)
+
+	makeRequest := func(query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/value/summary/rt?"+query, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("type", types.Summary)
+		rctx.URLParams.Add("name", "rt")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	rec := httptest.NewRecorder()
+	MetricGetPathHandler(validators.ValidateMetricIDPath, mockGetter).ServeHTTP(rec, makeRequest("q=1"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "100", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	MetricGetPathHandler(validators.ValidateMetricIDPath, mockGetter).ServeHTTP(rec, makeRequest(""))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "count=3 sum=103", rec.Body.String())
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+)
+
+type MetricHistoryGetterPath interface {
+	History(ctx context.Context, id types.MetricID, from time.Time, to time.Time) (*types.MetricHistory, error)
+}
+
+var (
+	ErrInvalidHistoryTime  = errors.New("invalid history time, expected RFC3339 or unix seconds")
+	ErrInvalidHistoryRange = errors.New("history range start is after its end")
+)
+
+func MetricHistoryPathHandler(
+	val func(metricType string, metricName string) error,
+	svc MetricHistoryGetterPath,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		metricType := chi.URLParam(r, "type")
+		metricName := chi.URLParam(r, "name")
+
+		err := val(metricType, metricName)
+		if err != nil {
+			switch err {
+			case validators.ErrNameIsRequired:
+				http.Error(w, err.Error(), http.StatusNotFound)
+			case validators.ErrInvalidMetricType,
+				validators.ErrTypeIsRequired:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			}
+			return
+		}
+
+		from, err := parseHistoryTime(r.URL.Query().Get("from"), time.Unix(0, 0))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		to, err := parseHistoryTime(r.URL.Query().Get("to"), time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if from.After(to) {
+			http.Error(w, ErrInvalidHistoryRange.Error(), http.StatusBadRequest)
+			return
+		}
+
+		labels, err := parseLabelsQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		metricID := types.NewMetricID(metricType, metricName)
+		metricID.Labels = labels.String()
+
+		history, err := svc.History(r.Context(), *metricID, from, to)
+		if err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(history); err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func parseHistoryTime(value string, def time.Time) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+
+	if ts, err := time.Parse(time.RFC3339, value); err == nil {
+		return ts, nil
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidHistoryTime
+	}
+
+	return time.Unix(seconds, 0), nil
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/handlers/metric_history_path.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockMetricHistoryGetterPath is a mock of MetricHistoryGetterPath interface.
+type MockMetricHistoryGetterPath struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricHistoryGetterPathMockRecorder
+}
+
+// MockMetricHistoryGetterPathMockRecorder is the mock recorder for MockMetricHistoryGetterPath.
+type MockMetricHistoryGetterPathMockRecorder struct {
+	mock *MockMetricHistoryGetterPath
+}
+
+// NewMockMetricHistoryGetterPath creates a new mock instance.
+func NewMockMetricHistoryGetterPath(ctrl *gomock.Controller) *MockMetricHistoryGetterPath {
+	mock := &MockMetricHistoryGetterPath{ctrl: ctrl}
+	mock.recorder = &MockMetricHistoryGetterPathMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricHistoryGetterPath) EXPECT() *MockMetricHistoryGetterPathMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockMetricHistoryGetterPath) History(ctx context.Context, id types.MetricID, from, to time.Time) (*types.MetricHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, id, from, to)
+	ret0, _ := ret[0].(*types.MetricHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockMetricHistoryGetterPathMockRecorder) History(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockMetricHistoryGetterPath)(nil).History), ctx, id, from, to)
+}
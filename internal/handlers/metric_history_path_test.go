@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricHistoryPathHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricHistoryGetterPath(ctrl)
+	handler := MetricHistoryPathHandler(validators.ValidateMetricIDPath, mockSvc)
+
+	makeRequest := func(metricType, metricName, query string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/history/"+metricType+"/"+metricName+query, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("type", metricType)
+		rctx.URLParams.Add("name", metricName)
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	id := types.MetricID{ID: "HeapAlloc", MType: types.Gauge}
+
+	t.Run("success with unix and RFC3339 range", func(t *testing.T) {
+		v := 1.5
+		history := &types.MetricHistory{
+			ID:      id.ID,
+			MType:   id.MType,
+			Samples: []types.MetricSample{{Timestamp: time.Unix(150, 0).UTC(), Value: &v}},
+		}
+
+		mockSvc.EXPECT().
+			History(gomock.Any(), id, time.Unix(100, 0), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)).
+			Return(history, nil)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, makeRequest("gauge", "HeapAlloc", "?from=100&to=2025-01-01T00:00:00Z"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var got types.MetricHistory
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+		assert.Equal(t, "HeapAlloc", got.ID)
+		require.Len(t, got.Samples, 1)
+		assert.Equal(t, 1.5, *got.Samples[0].Value)
+	})
+
+	t.Run("default range", func(t *testing.T) {
+		mockSvc.EXPECT().
+			History(gomock.Any(), id, time.Unix(0, 0), gomock.Any()).
+			Return(&types.MetricHistory{ID: id.ID, MType: id.MType, Samples: []types.MetricSample{}}, nil)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, makeRequest("gauge", "HeapAlloc", ""))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("invalid from", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, makeRequest("gauge", "HeapAlloc", "?from=yesterday"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), ErrInvalidHistoryTime.Error())
+	})
+
+	t.Run("from after to", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, makeRequest("gauge", "HeapAlloc", "?from=200&to=100"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), ErrInvalidHistoryRange.Error())
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, makeRequest("unknown", "HeapAlloc", ""))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockSvc.EXPECT().
+			History(gomock.Any(), id, gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("fail"))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, makeRequest("gauge", "HeapAlloc", ""))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
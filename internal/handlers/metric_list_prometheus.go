@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+func MetricListPrometheusHandler(svc MetricLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		metricsList, err := svc.List(r.Context())
+		if err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+
+		body, err := types.GetMetricsPrometheus(metricsList, openMetrics)
+		if err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if openMetrics {
+			w.Header().Set("Content-Type", types.OpenMetricsContentType)
+		} else {
+			w.Header().Set("Content-Type", types.PrometheusContentType)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(body))
+	}
+}
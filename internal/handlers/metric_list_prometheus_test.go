@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricListPrometheusHandler(t *testing.T) {
+	float64Ptr := func(f float64) *float64 {
+		return &f
+	}
+
+	int64Ptr := func(i int64) *int64 {
+		return &i
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := NewMockMetricLister(ctrl)
+
+	handler := MetricListPrometheusHandler(mockLister)
+
+	metrics := []types.Metrics{
+		{ID: "load", MType: types.Gauge, Value: float64Ptr(12.3)},
+		{ID: "hits", MType: types.Counter, Delta: int64Ptr(42)},
+	}
+
+	t.Run("prometheus text format", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return(metrics, nil)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, types.PrometheusContentType, rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "# TYPE load gauge\nload 12.3\n")
+		assert.Contains(t, rec.Body.String(), "# TYPE hits counter\nhits 42\n")
+		assert.NotContains(t, rec.Body.String(), "# EOF")
+	})
+
+	t.Run("openmetrics negotiated", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return(metrics, nil)
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5")
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, types.OpenMetricsContentType, rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), "hits_total 42\n")
+		assert.Contains(t, rec.Body.String(), "# EOF\n")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockLister.EXPECT().List(gomock.Any()).Return(nil, errors.New("fail"))
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), types.ErrInternalServerError.Error())
+	})
+}
@@ -108,4 +108,18 @@ func TestMetricUpdateBodyHandler(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		assert.Equal(t, types.ErrInternalServerError.Error()+"\n", rec.Body.String())
 	})
+
+	t.Run("histogram bounds mismatch", func(t *testing.T) {
+		mockSvc.EXPECT().Update(gomock.Any(), gomock.Any()).Return(types.ErrHistogramBoundsMismatch)
+
+		body := `{"id":"latency","type":"histogram","histogram":{"bounds":[0.5],"counts":[1,0],"sum":0.1}}`
+		req := httptest.NewRequest(http.MethodPost, "/update/", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		handlers.MetricUpdateBodyHandler(validators.ValidateMetricBody, mockSvc).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, types.ErrHistogramBoundsMismatch.Error()+"\n", rec.Body.String())
+	})
 }
@@ -32,6 +32,7 @@ func MetricUpdatePathHandler(
 			case validators.ErrInvalidMetricType,
 				validators.ErrInvalidGaugeValue,
 				validators.ErrInvalidCounterValue,
+				validators.ErrInvalidSummaryValue,
 				validators.ErrTypeIsRequired,
 				validators.ErrValueIsRequired:
 				http.Error(w, err.Error(), http.StatusBadRequest)
@@ -40,7 +41,14 @@ func MetricUpdatePathHandler(
 
 		}
 
+		labels, err := parseLabelsQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		metric := types.NewMetrics(metricType, metricName, metricValue)
+		metric.Labels = labels
 
 		if err := svc.Update(r.Context(), *metric); err != nil {
 			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/sbilibin2017/yp-metrics/internal/types"
@@ -38,7 +39,11 @@ func MetricUpdatesBodyHandler(
 					validators.ErrInvalidGaugeValue,
 					validators.ErrInvalidCounterValue,
 					validators.ErrTypeIsRequired,
-					validators.ErrValueIsRequired:
+					validators.ErrValueIsRequired,
+					validators.ErrInvalidLabel,
+					validators.ErrInvalidHistogram,
+					validators.ErrInvalidSummaryValue,
+					validators.ErrInvalidSetMember:
 					http.Error(w, err.Error(), http.StatusBadRequest)
 				}
 				return
@@ -47,6 +52,10 @@ func MetricUpdatesBodyHandler(
 
 		for _, metric := range metrics {
 			if err := svc.Update(r.Context(), metric); err != nil {
+				if errors.Is(err, types.ErrHistogramBoundsMismatch) {
+					http.Error(w, err.Error(), http.StatusConflict)
+					return
+				}
 				http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
 				return
 			}
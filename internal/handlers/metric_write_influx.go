@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricUpdaterInflux interface {
+	Update(ctx context.Context, metric types.Metrics) error
+}
+
+func MetricWriteInfluxHandler(
+	val func(m types.Metrics) error,
+	svc MetricUpdaterInflux,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			metrics    []types.Metrics
+			lineErrors []types.InfluxLineError
+		)
+
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		lineNum := 0
+		for scanner.Scan() {
+			lineNum++
+
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			parsed, err := types.ParseInfluxLine(line)
+			if err == nil {
+				for _, m := range parsed {
+					if err = val(m); err != nil {
+						err = fmt.Errorf("%s: %w", m.ID, err)
+						break
+					}
+				}
+			}
+			if err != nil {
+				lineErrors = append(lineErrors, types.InfluxLineError{Line: lineNum, Error: err.Error()})
+				continue
+			}
+
+			metrics = append(metrics, parsed...)
+		}
+
+		if err := scanner.Err(); err != nil {
+			http.Error(w, "Failed to read body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for _, metric := range metrics {
+			if err := svc.Update(r.Context(), metric); err != nil {
+				http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if len(lineErrors) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string][]types.InfluxLineError{"errors": lineErrors})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
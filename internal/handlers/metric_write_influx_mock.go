@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/handlers/metric_write_influx.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockMetricUpdaterInflux is a mock of MetricUpdaterInflux interface.
+type MockMetricUpdaterInflux struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricUpdaterInfluxMockRecorder
+}
+
+// MockMetricUpdaterInfluxMockRecorder is the mock recorder for MockMetricUpdaterInflux.
+type MockMetricUpdaterInfluxMockRecorder struct {
+	mock *MockMetricUpdaterInflux
+}
+
+// NewMockMetricUpdaterInflux creates a new mock instance.
+func NewMockMetricUpdaterInflux(ctrl *gomock.Controller) *MockMetricUpdaterInflux {
+	mock := &MockMetricUpdaterInflux{ctrl: ctrl}
+	mock.recorder = &MockMetricUpdaterInfluxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricUpdaterInflux) EXPECT() *MockMetricUpdaterInfluxMockRecorder {
+	return m.recorder
+}
+
+// Update mocks base method.
+func (m *MockMetricUpdaterInflux) Update(ctx context.Context, metric types.Metrics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, metric)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockMetricUpdaterInfluxMockRecorder) Update(ctx, metric interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockMetricUpdaterInflux)(nil).Update), ctx, metric)
+}
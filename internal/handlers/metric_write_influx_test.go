@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricWriteInfluxHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricUpdaterInflux(ctrl)
+
+	idle := 92.5
+	processes := 12.0
+	gomock.InOrder(
+		mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "cpu.usage_idle", MType: types.Gauge, Labels: types.Labels{"host": "web01"}, Value: &idle}).Return(nil),
+		mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "cpu.processes", MType: types.Gauge, Labels: types.Labels{"host": "web01"}, Value: &processes}).Return(nil),
+	)
+
+	body := "# telegraf\ncpu,host=web01 usage_idle=92.5,processes=12i 1700000000000000000\n\n"
+	req := httptest.NewRequest(http.MethodPost, "/write", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	MetricWriteInfluxHandler(validators.ValidateMetricBody, mockSvc).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestMetricWriteInfluxHandler_PartialWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricUpdaterInflux(ctrl)
+
+	value := 1.0
+	mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "mem.used", MType: types.Gauge, Value: &value}).Return(nil)
+
+	body := strings.Join([]string{
+		"cpu",
+		"mem used=1",
+		`app status="ok"`,
+	}, "\n")
+	req := httptest.NewRequest(http.MethodPost, "/write", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	MetricWriteInfluxHandler(validators.ValidateMetricBody, mockSvc).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var resp map[string][]types.InfluxLineError
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp["errors"], 2)
+	assert.Equal(t, 1, resp["errors"][0].Line)
+	assert.Equal(t, 3, resp["errors"][1].Line)
+	assert.Contains(t, resp["errors"][1].Error, "app.status")
+}
+
+func TestMetricWriteInfluxHandler_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricUpdaterInflux(ctrl)
+
+	req := httptest.NewRequest(http.MethodPost, "/write", strings.NewReader("cpu value=1"))
+	rec := httptest.NewRecorder()
+
+	MetricWriteInfluxHandler(alwaysInvalid(validators.ErrInvalidGaugeValue), mockSvc).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), validators.ErrInvalidGaugeValue.Error())
+}
+
+func TestMetricWriteInfluxHandler_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricUpdaterInflux(ctrl)
+	mockSvc.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+	req := httptest.NewRequest(http.MethodPost, "/write", strings.NewReader("cpu value=1"))
+	rec := httptest.NewRecorder()
+
+	MetricWriteInfluxHandler(validators.ValidateMetricBody, mockSvc).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
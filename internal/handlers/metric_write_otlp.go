@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type MetricUpdaterOTLP interface {
+	Update(ctx context.Context, metric types.Metrics) error
+}
+
+func MetricWriteOTLPHandler(
+	val func(m types.Metrics) error,
+	svc MetricUpdaterOTLP,
+) http.HandlerFunc {
+	tracker := types.NewOTLPCumulativeTracker(types.OTLPCumulativeSeriesTTL)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+		var (
+			unmarshal func([]byte, proto.Message) error
+			marshal   func(proto.Message) ([]byte, error)
+		)
+		switch contentType {
+		case "application/x-protobuf", "application/protobuf":
+			unmarshal, marshal = proto.Unmarshal, proto.Marshal
+		case "application/json":
+			unmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal
+			marshal = protojson.Marshal
+		default:
+			http.Error(w, "Unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var req colmetricspb.ExportMetricsServiceRequest
+		if err := unmarshal(body, &req); err != nil {
+			http.Error(w, "Invalid OTLP payload: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		points, rejected := types.ParseOTLPRequest(&req)
+
+		for _, p := range points {
+			m := p.Metrics
+			if p.Cumulative {
+				m.Delta = new(int64)
+			}
+			if err := val(m); err != nil {
+				rejected.Count++
+				rejected.Reasons = append(rejected.Reasons, fmt.Sprintf("%s: %v", m.ID, err))
+				continue
+			}
+
+			if p.Cumulative {
+				err = tracker.Apply(p, func(delta int64) error {
+					m.Delta = &delta
+					return svc.Update(r.Context(), m)
+				})
+			} else {
+				err = svc.Update(r.Context(), m)
+			}
+			if err != nil {
+				http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		resp := &colmetricspb.ExportMetricsServiceResponse{}
+		if rejected.Count > 0 {
+			resp.PartialSuccess = &colmetricspb.ExportMetricsPartialSuccess{
+				RejectedDataPoints: rejected.Count,
+				ErrorMessage:       strings.Join(rejected.Reasons, "; "),
+			}
+		}
+
+		out, err := marshal(resp)
+		if err != nil {
+			http.Error(w, types.ErrInternalServerError.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write(out)
+	}
+}
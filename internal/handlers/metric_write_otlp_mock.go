@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/handlers/metric_write_otlp.go
+
+// Package handlers is a generated GoMock package.
+package handlers
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockMetricUpdaterOTLP is a mock of MetricUpdaterOTLP interface.
+type MockMetricUpdaterOTLP struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricUpdaterOTLPMockRecorder
+}
+
+// MockMetricUpdaterOTLPMockRecorder is the mock recorder for MockMetricUpdaterOTLP.
+type MockMetricUpdaterOTLPMockRecorder struct {
+	mock *MockMetricUpdaterOTLP
+}
+
+// NewMockMetricUpdaterOTLP creates a new mock instance.
+func NewMockMetricUpdaterOTLP(ctrl *gomock.Controller) *MockMetricUpdaterOTLP {
+	mock := &MockMetricUpdaterOTLP{ctrl: ctrl}
+	mock.recorder = &MockMetricUpdaterOTLPMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricUpdaterOTLP) EXPECT() *MockMetricUpdaterOTLPMockRecorder {
+	return m.recorder
+}
+
+// Update mocks base method.
+func (m *MockMetricUpdaterOTLP) Update(ctx context.Context, metric types.Metrics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, metric)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockMetricUpdaterOTLPMockRecorder) Update(ctx, metric interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockMetricUpdaterOTLP)(nil).Update), ctx, metric)
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func otlpCumulativeRequest(total int64) *colmetricspb.ExportMetricsServiceRequest {
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Metrics: []*metricspb.Metric{{
+					Name: "jobs.total",
+					Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+						IsMonotonic:            true,
+						AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+						DataPoints: []*metricspb.NumberDataPoint{{
+							StartTimeUnixNano: 1,
+							Value:             &metricspb.NumberDataPoint_AsInt{AsInt: total},
+						}},
+					}},
+				}},
+			}},
+		}},
+	}
+}
+
+func TestMetricWriteOTLPHandler_Protobuf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricUpdaterOTLP(ctrl)
+
+	first, second := int64(0), int64(4)
+	gomock.InOrder(
+		mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "jobs.total", MType: types.Counter, Delta: &first}).Return(nil),
+		mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "jobs.total", MType: types.Counter, Delta: &second}).Return(nil),
+	)
+
+	handler := MetricWriteOTLPHandler(validators.ValidateMetricBody, mockSvc)
+
+	// первая точка задаёт базу, следующие превращаются в приращение относительно предыдущей
+	for _, total := range []int64{10, 14} {
+		body, err := proto.Marshal(otlpCumulativeRequest(total))
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-protobuf", rec.Header().Get("Content-Type"))
+
+		var resp colmetricspb.ExportMetricsServiceResponse
+		require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Nil(t, resp.GetPartialSuccess())
+	}
+}
+
+func TestMetricWriteOTLPHandler_JSONPartialSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricUpdaterOTLP(ctrl)
+
+	value := 0.5
+	mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "cpu.utilization", MType: types.Gauge, Value: &value}).Return(nil)
+
+	body := `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[
+		{"name":"cpu.utilization","gauge":{"dataPoints":[{"asDouble":0.5}]}},
+		{"name":"latency","histogram":{"dataPoints":[{"count":"1"}]}}
+	]}]}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/metrics", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec := httptest.NewRecorder()
+
+	MetricWriteOTLPHandler(validators.ValidateMetricBody, mockSvc).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp colmetricspb.ExportMetricsServiceResponse
+	require.NoError(t, protojson.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, int64(1), resp.GetPartialSuccess().GetRejectedDataPoints())
+	assert.Contains(t, resp.GetPartialSuccess().GetErrorMessage(), "latency")
+}
+
+func TestMetricWriteOTLPHandler_BadRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantCode    int
+	}{
+		{"unsupported content type", "text/plain", "cpu 1", http.StatusUnsupportedMediaType},
+		{"invalid protobuf", "application/x-protobuf", "\xff\xff", http.StatusBadRequest},
+		{"invalid json", "application/json", "{", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/metrics", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+
+			MetricWriteOTLPHandler(validators.ValidateMetricBody, NewMockMetricUpdaterOTLP(ctrl)).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestMetricWriteOTLPHandler_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricUpdaterOTLP(ctrl)
+	mockSvc.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+	body, err := proto.Marshal(otlpCumulativeRequest(1))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	rec := httptest.NewRecorder()
+
+	MetricWriteOTLPHandler(validators.ValidateMetricBody, mockSvc).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestMetricWriteOTLPHandler_ServiceErrorKeepsBaseline(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := NewMockMetricUpdaterOTLP(ctrl)
+
+	baseline, delta := int64(0), int64(4)
+	gomock.InOrder(
+		mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "jobs.total", MType: types.Counter, Delta: &baseline}).Return(nil),
+		mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "jobs.total", MType: types.Counter, Delta: &delta}).Return(errors.New("db error")),
+		mockSvc.EXPECT().Update(gomock.Any(), types.Metrics{ID: "jobs.total", MType: types.Counter, Delta: &delta}).Return(nil),
+	)
+
+	handler := MetricWriteOTLPHandler(validators.ValidateMetricBody, mockSvc)
+
+	// повтор после ошибки записи даёт то же приращение
+	for _, tt := range []struct {
+		total    int64
+		wantCode int
+	}{{10, http.StatusOK}, {14, http.StatusInternalServerError}, {14, http.StatusOK}} {
+		body, err := proto.Marshal(otlpCumulativeRequest(tt.total))
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/metrics", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, tt.wantCode, rec.Code)
+	}
+}
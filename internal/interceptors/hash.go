@@ -0,0 +1,173 @@
+package interceptors
+
+import (
+	"context"
+
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var errInvalidRequestHash = status.Error(codes.InvalidArgument, "invalid request hash")
+
+func HashUnaryInterceptor(key string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if key == "" || info.FullMethod != pb.MetricService_Update_FullMethodName {
+			return handler(ctx, req)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return nil, errInvalidRequestHash
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		hash := metadataValue(md, types.HashSHA256Metadata)
+
+		if err := checkMessageHash(msg, key, metadataValue(md, types.InstanceIDHeader), hash); err != nil {
+			return nil, errInvalidRequestHash
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func HashStreamInterceptor(key string) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if key == "" || info.FullMethod != pb.MetricService_Updates_FullMethodName {
+			return handler(srv, ss)
+		}
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		return handler(srv, &hashServerStream{
+			ServerStream: ss,
+			key:          key,
+			instance:     metadataValue(md, types.InstanceIDHeader),
+		})
+	}
+}
+
+type hashServerStream struct {
+	grpc.ServerStream
+	key      string
+	instance string
+}
+
+func (s *hashServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	req, ok := m.(*pb.UpdatesRequest)
+	if !ok {
+		return errInvalidRequestHash
+	}
+
+	hash := req.GetHash()
+	req.Hash = ""
+	if err := checkMessageHash(req, s.key, s.instance, hash); err != nil {
+		return errInvalidRequestHash
+	}
+
+	return nil
+}
+
+func HashUnaryClientInterceptor(key string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if msg, ok := req.(proto.Message); ok && key != "" {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			hash, err := messageHash(msg, key, metadataValue(md, types.InstanceIDHeader))
+			if err != nil {
+				return err
+			}
+			ctx = metadata.AppendToOutgoingContext(ctx, types.HashSHA256Metadata, hash)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func HashStreamClientInterceptor(key string) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil || key == "" {
+			return stream, err
+		}
+		md, _ := metadata.FromOutgoingContext(ctx)
+		return &hashClientStream{
+			ClientStream: stream,
+			key:          key,
+			instance:     metadataValue(md, types.InstanceIDHeader),
+		}, nil
+	}
+}
+
+type hashClientStream struct {
+	grpc.ClientStream
+	key      string
+	instance string
+}
+
+func (s *hashClientStream) SendMsg(m any) error {
+	if req, ok := m.(*pb.UpdatesRequest); ok {
+		signed := proto.Clone(req).(*pb.UpdatesRequest)
+		signed.Hash = ""
+
+		hash, err := messageHash(signed, s.key, s.instance)
+		if err != nil {
+			return err
+		}
+		signed.Hash = hash
+		m = signed
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func messageHash(msg proto.Message, key string, instance string) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+	return types.GetHashSHA256(types.InstanceHashData(instance, data), key), nil
+}
+
+func checkMessageHash(msg proto.Message, key string, instance string, hash string) error {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return types.CheckHashSHA256(types.InstanceHashData(instance, data), key, hash)
+}
+
+func metadataValue(md metadata.MD, name string) string {
+	if values := md.Get(name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
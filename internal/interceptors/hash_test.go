@@ -0,0 +1,198 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func signedUpdateRequest(t *testing.T, key string) (*pb.UpdateRequest, string) {
+	value := 1.5
+	req := &pb.UpdateRequest{Metric: &pb.Metric{Id: "Alloc", Type: types.Gauge, Value: &value}}
+	hash, err := messageHash(req, key, "")
+	require.NoError(t, err)
+	return req, hash
+}
+
+func TestHashUnaryInterceptor(t *testing.T) {
+	req, hash := signedUpdateRequest(t, "secret")
+
+	withHash := func(hash string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(types.HashSHA256Metadata, hash))
+	}
+
+	tests := []struct {
+		name         string
+		key          string
+		ctx          context.Context
+		method       string
+		expectedCode codes.Code
+	}{
+		{"no key", "", context.Background(), pb.MetricService_Update_FullMethodName, codes.OK},
+		{"valid hash", "secret", withHash(hash), pb.MetricService_Update_FullMethodName, codes.OK},
+		{"wrong key", "other", withHash(hash), pb.MetricService_Update_FullMethodName, codes.InvalidArgument},
+		{"missing hash", "secret", context.Background(), pb.MetricService_Update_FullMethodName, codes.InvalidArgument},
+		{"read method not checked", "secret", context.Background(), pb.MetricService_Get_FullMethodName, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := HashUnaryInterceptor(tt.key)
+			_, err := interceptor(tt.ctx, req, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				return "ok", nil
+			})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+type recvServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	msg *pb.UpdatesRequest
+}
+
+func (s *recvServerStream) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+func (s *recvServerStream) RecvMsg(m any) error {
+	proto.Merge(m.(*pb.UpdatesRequest), s.msg)
+	return nil
+}
+
+type sendClientStream struct {
+	grpc.ClientStream
+	sent *pb.UpdatesRequest
+}
+
+func (s *sendClientStream) SendMsg(m any) error {
+	s.sent = m.(*pb.UpdatesRequest)
+	return nil
+}
+
+func TestHashStreamInterceptors(t *testing.T) {
+	value := 2.0
+	req := &pb.UpdatesRequest{Metrics: []*pb.Metric{{Id: "Alloc", Type: types.Gauge, Value: &value}}}
+
+	// клиент подписывает каждое сообщение потока
+	client := &sendClientStream{}
+	interceptor := HashStreamClientInterceptor("secret")
+	stream, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, pb.MetricService_Updates_FullMethodName,
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return client, nil
+		})
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(req))
+	require.NotEmpty(t, client.sent.GetHash())
+	assert.Empty(t, req.GetHash())
+
+	// подпись сообщений потока инстанса включает его идентификатор
+	instanceClient := &sendClientStream{}
+	instanceCtx := metadata.AppendToOutgoingContext(context.Background(), types.InstanceIDHeader, "web01")
+	stream, err = interceptor(instanceCtx, &grpc.StreamDesc{}, nil, pb.MetricService_Updates_FullMethodName,
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return instanceClient, nil
+		})
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(req))
+
+	withInstance := func(instance string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(types.InstanceIDHeader, instance))
+	}
+
+	tests := []struct {
+		name         string
+		key          string
+		ctx          context.Context
+		msg          *pb.UpdatesRequest
+		expectedCode codes.Code
+	}{
+		{"valid hash", "secret", nil, client.sent, codes.OK},
+		{"wrong key", "other", nil, client.sent, codes.InvalidArgument},
+		{"unsigned message", "secret", nil, req, codes.InvalidArgument},
+		{"valid instance hash", "secret", withInstance("web01"), instanceClient.sent, codes.OK},
+		{"spoofed instance", "secret", withInstance("web02"), instanceClient.sent, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := HashStreamInterceptor(tt.key)
+			err := server(nil, &recvServerStream{ctx: tt.ctx, msg: tt.msg}, &grpc.StreamServerInfo{FullMethod: pb.MetricService_Updates_FullMethodName},
+				func(srv any, ss grpc.ServerStream) error {
+					var got pb.UpdatesRequest
+					return ss.RecvMsg(&got)
+				})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestHashUnaryInterceptor_Instance(t *testing.T) {
+	value := 1.5
+	req := &pb.UpdateRequest{Metric: &pb.Metric{Id: "Alloc", Type: types.Gauge, Value: &value}}
+
+	var hash string
+	client := HashUnaryClientInterceptor("secret")
+	ctx := metadata.AppendToOutgoingContext(context.Background(), types.InstanceIDHeader, "web01")
+	err := client(ctx, pb.MetricService_Update_FullMethodName, req, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			hash = md.Get(types.HashSHA256Metadata)[0]
+			return nil
+		})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		instance     string
+		expectedCode codes.Code
+	}{
+		{"same instance", "web01", codes.OK},
+		// подмена идентификатора инстанса ломает подпись
+		{"spoofed instance", "web02", codes.InvalidArgument},
+		{"instance removed", "", codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.Pairs(types.HashSHA256Metadata, hash)
+			if tt.instance != "" {
+				md.Set(types.InstanceIDHeader, tt.instance)
+			}
+			interceptor := HashUnaryInterceptor("secret")
+			_, err := interceptor(metadata.NewIncomingContext(context.Background(), md), req,
+				&grpc.UnaryServerInfo{FullMethod: pb.MetricService_Update_FullMethodName},
+				func(ctx context.Context, req any) (any, error) {
+					return "ok", nil
+				})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestHashUnaryClientInterceptor(t *testing.T) {
+	req, hash := signedUpdateRequest(t, "secret")
+
+	interceptor := HashUnaryClientInterceptor("secret")
+	err := interceptor(context.Background(), pb.MetricService_Update_FullMethodName, req, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, ok := metadata.FromOutgoingContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, []string{hash}, md.Get(types.HashSHA256Metadata))
+			return nil
+		})
+	require.NoError(t, err)
+}
@@ -0,0 +1,75 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func InstanceUnaryInterceptor(instanceSetter func(ctx context.Context, instance string) context.Context) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, err := withInstance(ctx, instanceSetter)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func InstanceStreamInterceptor(instanceSetter func(ctx context.Context, instance string) context.Context) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := withInstance(ss.Context(), instanceSetter)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &instanceServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type instanceServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *instanceServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func withInstance(ctx context.Context, instanceSetter func(ctx context.Context, instance string) context.Context) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx, nil
+	}
+
+	values := md.Get(types.InstanceIDHeader)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	instance := strings.TrimSpace(values[0])
+	if instance == "" {
+		return ctx, nil
+	}
+
+	if err := validators.ValidateInstanceID(instance); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return instanceSetter(ctx, instance), nil
+}
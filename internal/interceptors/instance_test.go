@@ -0,0 +1,64 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/contexts"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestInstanceUnaryInterceptor(t *testing.T) {
+	withInstanceID := func(instance string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(types.InstanceIDHeader, instance))
+	}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		expectedCode codes.Code
+		wantInstance string
+	}{
+		{name: "no metadata", ctx: context.Background(), expectedCode: codes.OK},
+		{name: "instance set", ctx: withInstanceID("web01"), expectedCode: codes.OK, wantInstance: "web01"},
+		{name: "trimmed", ctx: withInstanceID(" web01 "), expectedCode: codes.OK, wantInstance: "web01"},
+		{name: "too long", ctx: withInstanceID(strings.Repeat("a", 256)), expectedCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			interceptor := InstanceUnaryInterceptor(contexts.SetInstanceToContext)
+			_, err := interceptor(tt.ctx, "req", testInfo, func(ctx context.Context, req any) (any, error) {
+				got = contexts.GetInstanceFromContext(ctx)
+				return "resp", nil
+			})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Equal(t, tt.wantInstance, got)
+		})
+	}
+}
+
+func TestInstanceStreamInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/metrics.MetricService/Updates", IsClientStream: true, IsServerStream: true}
+	interceptor := InstanceStreamInterceptor(contexts.SetInstanceToContext)
+
+	var got string
+	stream := &testServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-instance-id", "web01"))}
+	err := interceptor(nil, stream, info, func(srv any, stream grpc.ServerStream) error {
+		got = contexts.GetInstanceFromContext(stream.Context())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "web01", got)
+
+	invalid := &testServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-instance-id", "web\x0001"))}
+	err = interceptor(nil, invalid, info, func(srv any, stream grpc.ServerStream) error { return nil })
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
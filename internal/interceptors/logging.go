@@ -0,0 +1,49 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func LoggingUnaryInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	logger.Log.Desugar().Info("gRPC request",
+		zap.String("method", info.FullMethod),
+		zap.Duration("duration", time.Since(start)),
+		zap.String("code", status.Code(err).String()),
+	)
+
+	return resp, err
+}
+
+func LoggingStreamInterceptor(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+
+	logger.Log.Desugar().Info("gRPC stream",
+		zap.String("method", info.FullMethod),
+		zap.Duration("duration", time.Since(start)),
+		zap.String("code", status.Code(err).String()),
+	)
+
+	return err
+}
@@ -0,0 +1,38 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoggingUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/metrics.MetricService/Update"}
+
+	resp, err := LoggingUnaryInterceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return "resp", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+
+	_, err = LoggingUnaryInterceptor(context.Background(), "req", info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.InvalidArgument, "bad")
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLoggingStreamInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/metrics.MetricService/Updates", IsClientStream: true, IsServerStream: true}
+
+	called := false
+	err := LoggingStreamInterceptor(nil, nil, info, func(srv any, stream grpc.ServerStream) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -0,0 +1,59 @@
+package interceptors
+
+import (
+	"context"
+	"net"
+
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TrustedSubnetUnaryInterceptor(subnet *net.IPNet) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if info.FullMethod == pb.MetricService_Update_FullMethodName && !isTrustedPeer(ctx, subnet) {
+			return nil, status.Error(codes.PermissionDenied, "forbidden")
+		}
+		return handler(ctx, req)
+	}
+}
+
+func TrustedSubnetStreamInterceptor(subnet *net.IPNet) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if info.FullMethod == pb.MetricService_Updates_FullMethodName && !isTrustedPeer(ss.Context(), subnet) {
+			return status.Error(codes.PermissionDenied, "forbidden")
+		}
+		return handler(srv, ss)
+	}
+}
+
+func isTrustedPeer(ctx context.Context, subnet *net.IPNet) bool {
+	if subnet == nil {
+		return true
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+
+	values := md.Get("x-real-ip")
+	if len(values) == 0 {
+		return false
+	}
+
+	ip := net.ParseIP(values[0])
+	return ip != nil && subnet.Contains(ip)
+}
@@ -0,0 +1,73 @@
+package interceptors
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	pb "github.com/sbilibin2017/yp-metrics/internal/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestTrustedSubnetUnaryInterceptor(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	withIP := func(ip string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-real-ip", ip))
+	}
+
+	tests := []struct {
+		name         string
+		subnet       *net.IPNet
+		ctx          context.Context
+		method       string
+		expectedCode codes.Code
+	}{
+		{name: "trusted update", subnet: subnet, ctx: withIP("10.1.1.1"), method: pb.MetricService_Update_FullMethodName, expectedCode: codes.OK},
+		{name: "untrusted update", subnet: subnet, ctx: withIP("192.168.1.1"), method: pb.MetricService_Update_FullMethodName, expectedCode: codes.PermissionDenied},
+		{name: "update without metadata", subnet: subnet, ctx: context.Background(), method: pb.MetricService_Update_FullMethodName, expectedCode: codes.PermissionDenied},
+		{name: "untrusted get allowed", subnet: subnet, ctx: withIP("192.168.1.1"), method: pb.MetricService_Get_FullMethodName, expectedCode: codes.OK},
+		{name: "no subnet configured", subnet: nil, ctx: context.Background(), method: pb.MetricService_Update_FullMethodName, expectedCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := TrustedSubnetUnaryInterceptor(tt.subnet)
+			_, err := interceptor(tt.ctx, "req", &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				return "resp", nil
+			})
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestTrustedSubnetStreamInterceptor(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	info := &grpc.StreamServerInfo{FullMethod: pb.MetricService_Updates_FullMethodName, IsClientStream: true, IsServerStream: true}
+	interceptor := TrustedSubnetStreamInterceptor(subnet)
+
+	trusted := &testServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-real-ip", "10.0.0.5"))}
+	err = interceptor(nil, trusted, info, func(srv any, stream grpc.ServerStream) error { return nil })
+	assert.NoError(t, err)
+
+	untrusted := &testServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-real-ip", "172.16.0.1"))}
+	err = interceptor(nil, untrusted, info, func(srv any, stream grpc.ServerStream) error { return nil })
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,46 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TxUnaryInterceptor(
+	db *sqlx.DB,
+	txSetter func(ctx context.Context, tx *sqlx.Tx) context.Context,
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if db == nil {
+			return handler(ctx, req)
+		}
+
+		tx, err := db.Beginx()
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to begin transaction")
+		}
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				tx.Rollback()
+				panic(rec)
+			}
+		}()
+
+		resp, err := handler(txSetter(ctx, tx), req)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := tx.Commit(); err != nil {
+			tx.Rollback()
+			return nil, status.Error(codes.Internal, "failed to commit transaction")
+		}
+
+		return resp, nil
+	}
+}
@@ -0,0 +1,81 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sbilibin2017/yp-metrics/internal/contexts"
+)
+
+var testInfo = &grpc.UnaryServerInfo{FullMethod: "/metrics.MetricService/Update"}
+
+func TestTxUnaryInterceptor_SuccessCommit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	interceptor := TxUnaryInterceptor(sqlx.NewDb(db, "sqlmock"), contexts.SetTxToContext)
+
+	resp, err := interceptor(context.Background(), "req", testInfo, func(ctx context.Context, req any) (any, error) {
+		require.NotNil(t, contexts.GetTxFromContext(ctx))
+		return "resp", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxUnaryInterceptor_HandlerErrorRollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	interceptor := TxUnaryInterceptor(sqlx.NewDb(db, "sqlmock"), contexts.SetTxToContext)
+
+	_, err = interceptor(context.Background(), "req", testInfo, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.InvalidArgument, "bad")
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTxUnaryInterceptor_BeginError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin().WillReturnError(assert.AnError)
+
+	interceptor := TxUnaryInterceptor(sqlx.NewDb(db, "sqlmock"), contexts.SetTxToContext)
+
+	_, err = interceptor(context.Background(), "req", testInfo, func(ctx context.Context, req any) (any, error) {
+		t.Fatal("handler should not be called")
+		return nil, nil
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestTxUnaryInterceptor_NoDB(t *testing.T) {
+	interceptor := TxUnaryInterceptor(nil, contexts.SetTxToContext)
+
+	resp, err := interceptor(context.Background(), "req", testInfo, func(ctx context.Context, req any) (any, error) {
+		assert.Nil(t, contexts.GetTxFromContext(ctx))
+		return "resp", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+}
@@ -0,0 +1,198 @@
+package listeners
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+const maxPendingSummaries = 10000
+
+type MetricUpdater interface {
+	Update(ctx context.Context, metric types.Metrics) error
+}
+
+type metricBatch struct {
+	mu        sync.Mutex
+	index     map[types.MetricID]int
+	metrics   []types.Metrics
+	summaries int
+	dropped   int
+}
+
+func newMetricBatch() *metricBatch {
+	return &metricBatch{index: make(map[types.MetricID]int)}
+}
+
+func (b *metricBatch) add(m types.Metrics) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if m.MType == types.Summary {
+		b.appendSummary(m)
+		return
+	}
+
+	id := m.MetricID()
+
+	i, ok := b.index[id]
+	if !ok {
+		if m.Delta != nil {
+			delta := *m.Delta
+			m.Delta = &delta
+		}
+		b.index[id] = len(b.metrics)
+		b.metrics = append(b.metrics, m)
+		return
+	}
+
+	if m.MType == types.Counter && m.Delta != nil && b.metrics[i].Delta != nil {
+		*b.metrics[i].Delta += *m.Delta
+		return
+	}
+
+	b.metrics[i] = m
+}
+
+func (b *metricBatch) requeue(metrics []types.Metrics) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, m := range metrics {
+		if m.MType == types.Summary {
+			b.appendSummary(m)
+			continue
+		}
+
+		id := m.MetricID()
+		i, ok := b.index[id]
+		switch {
+		case !ok:
+			b.index[id] = len(b.metrics)
+			b.metrics = append(b.metrics, m)
+		case m.MType == types.Counter && m.Delta != nil && b.metrics[i].Delta != nil:
+			*b.metrics[i].Delta += *m.Delta
+		}
+	}
+}
+
+func (b *metricBatch) appendSummary(m types.Metrics) {
+	if b.summaries >= maxPendingSummaries {
+		b.dropped++
+		return
+	}
+	b.summaries++
+	b.metrics = append(b.metrics, m)
+}
+
+func (b *metricBatch) drain() []types.Metrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	metrics := b.metrics
+	b.metrics = nil
+	b.index = make(map[types.MetricID]int)
+	b.summaries = 0
+
+	return metrics
+}
+
+func (b *metricBatch) takeDropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	dropped := b.dropped
+	b.dropped = 0
+
+	return dropped
+}
+
+type batchFlusher struct {
+	updater  MetricUpdater
+	db       *sqlx.DB
+	txSetter func(ctx context.Context, tx *sqlx.Tx) context.Context
+}
+
+func (f *batchFlusher) flush(ctx context.Context, metrics []types.Metrics) (int, error) {
+	if len(metrics) == 0 {
+		return 0, nil
+	}
+
+	if f.db == nil {
+		return f.update(ctx, metrics)
+	}
+
+	tx, err := f.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := f.update(f.txSetter(ctx, tx), metrics); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(metrics), nil
+}
+
+func (f *batchFlusher) update(ctx context.Context, metrics []types.Metrics) (int, error) {
+	for i, m := range metrics {
+		if m.Delta != nil {
+			delta := *m.Delta
+			m.Delta = &delta
+		}
+		if m.Histogram != nil {
+			histogram := m.Histogram.Clone()
+			m.Histogram = &histogram
+		}
+		if err := f.updater.Update(ctx, m); err != nil {
+			logger.Log.Errorw("Failed to update metric from listener", "id", m.ID, "type", m.MType, "error", err)
+			return i, err
+		}
+	}
+	return len(metrics), nil
+}
+
+func flushBatch(ctx context.Context, source string, batch *metricBatch, flusher *batchFlusher, requeue bool) {
+	if dropped := batch.takeDropped(); dropped > 0 {
+		logger.Log.Warnw("Pending summary limit reached, observations dropped", "source", source, "dropped", dropped, "limit", maxPendingSummaries)
+	}
+
+	metrics := batch.drain()
+	applied, err := flusher.flush(ctx, metrics)
+	if err != nil {
+		pending := metrics[applied:]
+		if requeue {
+			batch.requeue(pending)
+			logger.Log.Errorw("Failed to flush metrics, retrying on next flush", "source", source, "count", len(pending), "error", err)
+			return
+		}
+		logger.Log.Errorw("Failed to flush metrics, dropping", "source", source, "dropped", len(pending), "error", err)
+		return
+	}
+	if len(metrics) > 0 {
+		logger.Log.Debugw("Metrics flushed", "source", source, "count", len(metrics))
+	}
+}
+
+func isTrustedAddr(subnet *net.IPNet, addr net.Addr) bool {
+	if subnet == nil {
+		return true
+	}
+
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		ip = a.IP
+	case *net.TCPAddr:
+		ip = a.IP
+	}
+	return ip != nil && subnet.Contains(ip)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/listeners/batch.go
+
+// Package listeners is a generated GoMock package.
+package listeners
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockMetricUpdater is a mock of MetricUpdater interface.
+type MockMetricUpdater struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricUpdaterMockRecorder
+}
+
+// MockMetricUpdaterMockRecorder is the mock recorder for MockMetricUpdater.
+type MockMetricUpdaterMockRecorder struct {
+	mock *MockMetricUpdater
+}
+
+// NewMockMetricUpdater creates a new mock instance.
+func NewMockMetricUpdater(ctrl *gomock.Controller) *MockMetricUpdater {
+	mock := &MockMetricUpdater{ctrl: ctrl}
+	mock.recorder = &MockMetricUpdaterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricUpdater) EXPECT() *MockMetricUpdaterMockRecorder {
+	return m.recorder
+}
+
+// Update mocks base method.
+func (m *MockMetricUpdater) Update(ctx context.Context, metric types.Metrics) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, metric)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockMetricUpdaterMockRecorder) Update(ctx, metric interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockMetricUpdater)(nil).Update), ctx, metric)
+}
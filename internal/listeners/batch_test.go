@@ -0,0 +1,223 @@
+package listeners
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	gomock "github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/yp-metrics/internal/contexts"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func TestMetricBatch(t *testing.T) {
+	b := newMetricBatch()
+
+	first := int64(1)
+	b.add(types.Metrics{ID: "hits", MType: types.Counter, Delta: &first})
+	b.add(types.Metrics{ID: "temp", MType: types.Gauge, Value: float64Ptr(1)})
+	b.add(types.Metrics{ID: "hits", MType: types.Counter, Delta: int64Ptr(2)})
+	b.add(types.Metrics{ID: "temp", MType: types.Gauge, Value: float64Ptr(5)})
+
+	metrics := b.drain()
+	require.Len(t, metrics, 2)
+	assert.Equal(t, int64(3), *metrics[0].Delta)
+	assert.Equal(t, 5.0, *metrics[1].Value)
+
+	// исходное значение счётчика не изменяется
+	assert.Equal(t, int64(1), first)
+
+	assert.Empty(t, b.drain())
+}
+
+func TestBatchFlusher_WithoutDB(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	updater := NewMockMetricUpdater(ctrl)
+	f := &batchFlusher{updater: updater}
+
+	metrics := []types.Metrics{
+		{ID: "hits", MType: types.Counter, Delta: int64Ptr(1)},
+		{ID: "temp", MType: types.Gauge, Value: float64Ptr(2)},
+	}
+
+	gomock.InOrder(
+		updater.EXPECT().Update(gomock.Any(), metrics[0]).Return(nil),
+		updater.EXPECT().Update(gomock.Any(), metrics[1]).Return(nil),
+	)
+
+	applied, err := f.flush(context.Background(), metrics)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, applied)
+
+	applied, err = f.flush(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, applied)
+}
+
+func TestBatchFlusher_UpdateError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	updater := NewMockMetricUpdater(ctrl)
+	f := &batchFlusher{updater: updater}
+
+	errUpdate := errors.New("update failed")
+	gomock.InOrder(
+		updater.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil),
+		updater.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errUpdate),
+	)
+
+	applied, err := f.flush(context.Background(), []types.Metrics{
+		{ID: "temp", MType: types.Gauge, Value: float64Ptr(2)},
+		{ID: "hits", MType: types.Counter, Delta: int64Ptr(1)},
+	})
+	assert.ErrorIs(t, err, errUpdate)
+	// без БД уже записанные метрики не откатываются
+	assert.Equal(t, 1, applied)
+}
+
+func TestMetricBatch_Summary(t *testing.T) {
+	b := newMetricBatch()
+
+	b.add(types.Metrics{ID: "latency", MType: types.Summary, Value: float64Ptr(120)})
+	b.add(types.Metrics{ID: "latency", MType: types.Summary, Value: float64Ptr(80)})
+
+	// каждое наблюдение summary отправляется отдельно
+	metrics := b.drain()
+	require.Len(t, metrics, 2)
+	assert.Equal(t, 120.0, *metrics[0].Value)
+	assert.Equal(t, 80.0, *metrics[1].Value)
+}
+
+func TestMetricBatch_Requeue(t *testing.T) {
+	b := newMetricBatch()
+
+	b.add(types.Metrics{ID: "hits", MType: types.Counter, Delta: int64Ptr(2)})
+	b.add(types.Metrics{ID: "temp", MType: types.Gauge, Value: float64Ptr(7)})
+
+	b.requeue([]types.Metrics{
+		{ID: "hits", MType: types.Counter, Delta: int64Ptr(3)},
+		{ID: "temp", MType: types.Gauge, Value: float64Ptr(1)},
+		{ID: "load", MType: types.Gauge, Value: float64Ptr(0.5)},
+		{ID: "latency", MType: types.Summary, Value: float64Ptr(10)},
+	})
+
+	metrics := b.drain()
+	require.Len(t, metrics, 4)
+	// счётчики суммируются, более свежие значения gauge не перезаписываются
+	assert.Equal(t, int64(5), *metrics[0].Delta)
+	assert.Equal(t, 7.0, *metrics[1].Value)
+	assert.Equal(t, "load", metrics[2].ID)
+	assert.Equal(t, "latency", metrics[3].ID)
+}
+
+func TestFlushBatch_Requeue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	updater := NewMockMetricUpdater(ctrl)
+	f := &batchFlusher{updater: updater}
+	b := newMetricBatch()
+
+	b.add(types.Metrics{ID: "hits", MType: types.Counter, Delta: int64Ptr(1)})
+
+	gomock.InOrder(
+		updater.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("update failed")),
+		updater.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("update failed")),
+	)
+
+	// при периодическом сбросе пачка возвращается в очередь
+	flushBatch(context.Background(), "test", b, f, true)
+	require.Len(t, b.metrics, 1)
+
+	// при финальном сбросе метрики отбрасываются
+	flushBatch(context.Background(), "test", b, f, false)
+	assert.Empty(t, b.metrics)
+}
+
+func TestBatchFlusher_TxRollback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	updater := NewMockMetricUpdater(ctrl)
+	gomock.InOrder(
+		updater.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil),
+		updater.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.New("update failed")),
+	)
+
+	f := &batchFlusher{updater: updater, db: sqlx.NewDb(db, "sqlmock"), txSetter: contexts.SetTxToContext}
+
+	// откат транзакции отменяет всю пачку
+	applied, err := f.flush(context.Background(), []types.Metrics{
+		{ID: "temp", MType: types.Gauge, Value: float64Ptr(2)},
+		{ID: "hits", MType: types.Counter, Delta: int64Ptr(1)},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 0, applied)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFlushBatch_RequeueKeepsOriginalDelta(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	updater := NewMockMetricUpdater(ctrl)
+	f := &batchFlusher{updater: updater}
+	b := newMetricBatch()
+
+	b.add(types.Metrics{ID: "hits", MType: types.Counter, Delta: int64Ptr(2)})
+
+	var deltas []int64
+	updater.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, m types.Metrics) error {
+			deltas = append(deltas, *m.Delta)
+			// как и сервис, прибавляем сохранённое значение на месте
+			*m.Delta += 100
+			return errors.New("save failed")
+		},
+	).Times(2)
+
+	flushBatch(context.Background(), "test", b, f, true)
+	flushBatch(context.Background(), "test", b, f, true)
+
+	// повторная отправка несёт исходную дельту, а не накопленную сумму
+	assert.Equal(t, []int64{2, 2}, deltas)
+}
+
+func TestMetricBatch_SummaryLimit(t *testing.T) {
+	b := newMetricBatch()
+
+	for i := 0; i < maxPendingSummaries+5; i++ {
+		b.add(types.Metrics{ID: "latency", MType: types.Summary, Value: float64Ptr(1)})
+	}
+	b.requeue([]types.Metrics{{ID: "latency", MType: types.Summary, Value: float64Ptr(2)}})
+
+	assert.Equal(t, 6, b.takeDropped())
+	assert.Equal(t, 0, b.takeDropped())
+	assert.Len(t, b.drain(), maxPendingSummaries)
+
+	// после сброса очередь снова принимает наблюдения
+	b.add(types.Metrics{ID: "latency", MType: types.Summary, Value: float64Ptr(3)})
+	assert.Len(t, b.drain(), 1)
+}
@@ -0,0 +1,197 @@
+package listeners
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+const (
+	graphiteMaxLineSize = 4096
+	graphiteIdleTimeout = 2 * time.Minute
+)
+
+type GraphiteServer struct {
+	addr           string
+	flushInterval  time.Duration
+	maxConnections int
+	trustedSubnet  *net.IPNet
+	flusher        *batchFlusher
+	batch          *metricBatch
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+}
+
+func NewGraphiteServer(
+	addr string,
+	flushInterval time.Duration,
+	maxConnections int,
+	trustedSubnet *net.IPNet,
+	updater MetricUpdater,
+	db *sqlx.DB,
+	txSetter func(ctx context.Context, tx *sqlx.Tx) context.Context,
+) *GraphiteServer {
+	return &GraphiteServer{
+		addr:           addr,
+		flushInterval:  flushInterval,
+		maxConnections: maxConnections,
+		trustedSubnet:  trustedSubnet,
+		flusher:        &batchFlusher{updater: updater, db: db, txSetter: txSetter},
+		batch:          newMetricBatch(),
+		conns:          make(map[net.Conn]struct{}),
+	}
+}
+
+func (s *GraphiteServer) Listen() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.listener = listener
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *GraphiteServer) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+func (s *GraphiteServer) Serve(ctx context.Context) error {
+	s.mu.Lock()
+	listener := s.listener
+	s.mu.Unlock()
+
+	if listener == nil {
+		return net.ErrClosed
+	}
+
+	var wg sync.WaitGroup
+
+	acceptDone := make(chan error, 1)
+	go func() {
+		acceptDone <- s.accept(listener, &wg)
+	}()
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			listener.Close()
+			<-acceptDone
+			s.closeConns()
+			wg.Wait()
+			s.flush(context.WithoutCancel(ctx), false)
+			return nil
+		case err := <-acceptDone:
+			s.closeConns()
+			wg.Wait()
+			s.flush(ctx, false)
+			return err
+		case <-ticker.C:
+			s.flush(ctx, true)
+		}
+	}
+}
+
+func (s *GraphiteServer) accept(listener net.Listener, wg *sync.WaitGroup) error {
+	slots := make(chan struct{}, s.maxConnections)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		if !isTrustedAddr(s.trustedSubnet, conn.RemoteAddr()) {
+			logger.Log.Warnw("Rejecting Graphite connection from untrusted address", "remote", conn.RemoteAddr().String())
+			conn.Close()
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			logger.Log.Warnw("Graphite connection limit reached, rejecting connection", "remote", conn.RemoteAddr().String())
+			conn.Close()
+			continue
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				conn.Close()
+				<-slots
+				wg.Done()
+			}()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *GraphiteServer) handle(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, graphiteMaxLineSize), graphiteMaxLineSize)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(graphiteIdleTimeout))
+		if !scanner.Scan() {
+			break
+		}
+
+		line := scanner.Text()
+		m, err := types.ParseGraphiteLine(line)
+		if err != nil {
+			logger.Log.Debugw("Skipping invalid Graphite line", "line", line, "error", err)
+			continue
+		}
+
+		s.batch.add(m)
+	}
+
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		logger.Log.Debugw("Graphite connection closed", "remote", conn.RemoteAddr().String(), "error", err)
+	}
+}
+
+func (s *GraphiteServer) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *GraphiteServer) flush(ctx context.Context, requeue bool) {
+	flushBatch(ctx, "graphite", s.batch, s.flusher, requeue)
+}
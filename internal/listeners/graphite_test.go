@@ -0,0 +1,207 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphiteServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mu      sync.Mutex
+		updates []types.Metrics
+	)
+
+	updater := NewMockMetricUpdater(ctrl)
+	updater.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, m types.Metrics) error {
+			mu.Lock()
+			defer mu.Unlock()
+			updates = append(updates, m)
+			return nil
+		},
+	).AnyTimes()
+
+	srv := NewGraphiteServer("127.0.0.1:0", time.Hour, 10, nil, updater, nil, nil)
+	require.NoError(t, srv.Listen())
+	require.NotNil(t, srv.Addr())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx)
+	}()
+
+	// несколько одновременных соединений, одно из которых остаётся открытым до остановки сервера
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			conn, err := net.Dial("tcp", srv.Addr().String())
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			fmt.Fprintf(conn, "hosts.web%d.load %d 1700000000\nbroken line\n", i, i)
+		}(i)
+	}
+	wg.Wait()
+
+	idle, err := net.Dial("tcp", srv.Addr().String())
+	require.NoError(t, err)
+	defer idle.Close()
+	fmt.Fprint(idle, "hosts.db.load 0.5 1700000000\nhosts.db.load 0.75 1700000001\n")
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Graphite server did not stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, updates, 4)
+
+	byID := make(map[string]types.Metrics)
+	for _, m := range updates {
+		assert.Equal(t, types.Gauge, m.MType)
+		byID[m.ID] = m
+	}
+	assert.Equal(t, 0.75, *byID["hosts.db.load"].Value)
+	assert.Equal(t, 2.0, *byID["hosts.web2.load"].Value)
+}
+
+func TestGraphiteServer_ConnectionLimit(t *testing.T) {
+	srv := NewGraphiteServer("127.0.0.1:0", time.Hour, 1, nil, nil, nil, nil)
+	require.NoError(t, srv.Listen())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx)
+	}()
+
+	first, err := net.Dial("tcp", srv.Addr().String())
+	require.NoError(t, err)
+	defer first.Close()
+
+	require.Eventually(t, func() bool {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return len(srv.conns) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// второе соединение сверх лимита сервер сразу закрывает
+	second, err := net.Dial("tcp", srv.Addr().String())
+	require.NoError(t, err)
+	defer second.Close()
+
+	second.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = second.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.False(t, isTimeout(err))
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Graphite server did not stop")
+	}
+}
+
+func TestGraphiteServer_TrustedSubnet(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	srv := NewGraphiteServer("127.0.0.1:0", time.Hour, 10, subnet, nil, nil, nil)
+	require.NoError(t, srv.Listen())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx)
+	}()
+
+	// соединение с адреса вне доверенной подсети сервер сразу закрывает
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.False(t, isTimeout(err))
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestGraphiteServer_PeriodicFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flushed := make(chan types.Metrics, 10)
+
+	updater := NewMockMetricUpdater(ctrl)
+	updater.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, m types.Metrics) error {
+			flushed <- m
+			return nil
+		},
+	).AnyTimes()
+
+	srv := NewGraphiteServer("127.0.0.1:0", 50*time.Millisecond, 10, nil, updater, nil, nil)
+	require.NoError(t, srv.Listen())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Serve(ctx)
+
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	fmt.Fprint(conn, "queue.size 7 1700000000\n")
+
+	select {
+	case m := <-flushed:
+		assert.Equal(t, "queue.size", m.ID)
+		assert.Equal(t, 7.0, *m.Value)
+	case <-time.After(time.Second):
+		t.Fatal("metrics were not flushed")
+	}
+}
+
+func TestGraphiteServer_ServeWithoutListen(t *testing.T) {
+	srv := NewGraphiteServer("127.0.0.1:0", time.Second, 1, nil, nil, nil, nil)
+	assert.Nil(t, srv.Addr())
+	assert.ErrorIs(t, srv.Serve(context.Background()), net.ErrClosed)
+}
+
+func TestGraphiteServer_ListenError(t *testing.T) {
+	srv := NewGraphiteServer("256.0.0.1:0", time.Second, 1, nil, nil, nil, nil)
+	assert.Error(t, srv.Listen())
+}
+
+func isTimeout(err error) bool {
+	ne, ok := err.(net.Error)
+	return ok && ne.Timeout()
+}
@@ -0,0 +1,137 @@
+package listeners
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+const statsDMaxPacketSize = 65535
+
+type StatsDServer struct {
+	addr          string
+	flushInterval time.Duration
+	trustedSubnet *net.IPNet
+	flusher       *batchFlusher
+	batch         *metricBatch
+
+	mu   sync.Mutex
+	conn net.PacketConn
+}
+
+func NewStatsDServer(
+	addr string,
+	flushInterval time.Duration,
+	trustedSubnet *net.IPNet,
+	updater MetricUpdater,
+	db *sqlx.DB,
+	txSetter func(ctx context.Context, tx *sqlx.Tx) context.Context,
+) *StatsDServer {
+	return &StatsDServer{
+		addr:          addr,
+		flushInterval: flushInterval,
+		trustedSubnet: trustedSubnet,
+		flusher:       &batchFlusher{updater: updater, db: db, txSetter: txSetter},
+		batch:         newMetricBatch(),
+	}
+}
+
+func (s *StatsDServer) Listen() error {
+	conn, err := net.ListenPacket("udp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *StatsDServer) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.LocalAddr()
+}
+
+func (s *StatsDServer) Serve(ctx context.Context) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		return net.ErrClosed
+	}
+
+	readDone := make(chan error, 1)
+	go func() {
+		readDone <- s.read(conn)
+	}()
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			<-readDone
+			s.flush(context.WithoutCancel(ctx), false)
+			return nil
+		case err := <-readDone:
+			s.flush(ctx, false)
+			return err
+		case <-ticker.C:
+			s.flush(ctx, true)
+		}
+	}
+}
+
+func (s *StatsDServer) read(conn net.PacketConn) error {
+	buf := make([]byte, statsDMaxPacketSize)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		if !isTrustedAddr(s.trustedSubnet, addr) {
+			logger.Log.Debugw("Dropping StatsD packet from untrusted address", "remote", addr.String())
+			continue
+		}
+
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			m, err := types.ParseStatsDLine(line)
+			if err != nil {
+				logger.Log.Debugw("Skipping invalid StatsD line", "line", line, "error", err)
+				continue
+			}
+
+			s.batch.add(m)
+		}
+	}
+}
+
+func (s *StatsDServer) flush(ctx context.Context, requeue bool) {
+	flushBatch(ctx, "statsd", s.batch, s.flusher, requeue)
+}
@@ -0,0 +1,168 @@
+package listeners
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsDServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		mu      sync.Mutex
+		updates []types.Metrics
+	)
+
+	updater := NewMockMetricUpdater(ctrl)
+	updater.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, m types.Metrics) error {
+			mu.Lock()
+			defer mu.Unlock()
+			updates = append(updates, m)
+			return nil
+		},
+	).AnyTimes()
+
+	srv := NewStatsDServer("127.0.0.1:0", time.Hour, nil, updater, nil, nil)
+	require.NoError(t, srv.Listen())
+	require.NotNil(t, srv.Addr())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx)
+	}()
+
+	conn, err := net.Dial("udp", srv.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	packets := []string{
+		"requests:1|c\nrequests:2|c|@0.5\ntemperature:3.2|g",
+		"latency:120|ms\nbroken line\ntemperature:4.5|g",
+		"requests:1|c\nlatency:80|ms",
+	}
+	for _, p := range packets {
+		_, err := conn.Write([]byte(p))
+		require.NoError(t, err)
+	}
+
+	// ждём, пока пакеты будут прочитаны, затем останавливаем сервер — он сбрасывает пачку
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("StatsD server did not stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// одна пачка: по одному обновлению на метрику, наблюдения таймера сохраняются все
+	require.Len(t, updates, 4)
+
+	byID := make(map[string]types.Metrics)
+	var latencies []float64
+	for _, m := range updates {
+		byID[m.ID] = m
+		if m.ID == "latency" {
+			assert.Equal(t, types.Summary, m.MType)
+			latencies = append(latencies, *m.Value)
+		}
+	}
+	assert.Equal(t, int64(6), *byID["requests"].Delta)
+	assert.Equal(t, 4.5, *byID["temperature"].Value)
+	assert.Equal(t, []float64{120, 80}, latencies)
+}
+
+func TestStatsDServer_PeriodicFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flushed := make(chan types.Metrics, 10)
+
+	updater := NewMockMetricUpdater(ctrl)
+	updater.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, m types.Metrics) error {
+			flushed <- m
+			return nil
+		},
+	).AnyTimes()
+
+	srv := NewStatsDServer("127.0.0.1:0", 50*time.Millisecond, nil, updater, nil, nil)
+	require.NoError(t, srv.Listen())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go srv.Serve(ctx)
+
+	conn, err := net.Dial("udp", srv.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("jobs:5|c"))
+	require.NoError(t, err)
+
+	select {
+	case m := <-flushed:
+		assert.Equal(t, "jobs", m.ID)
+		assert.Equal(t, int64(5), *m.Delta)
+	case <-time.After(time.Second):
+		t.Fatal("metrics were not flushed")
+	}
+}
+
+func TestStatsDServer_TrustedSubnet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, subnet, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+
+	// пакеты с адресов вне доверенной подсети отбрасываются
+	updater := NewMockMetricUpdater(ctrl)
+	updater.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	srv := NewStatsDServer("127.0.0.1:0", time.Hour, subnet, updater, nil, nil)
+	require.NoError(t, srv.Listen())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(ctx)
+	}()
+
+	conn, err := net.Dial("udp", srv.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("jobs:5|c"))
+	require.NoError(t, err)
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestStatsDServer_ServeWithoutListen(t *testing.T) {
+	srv := NewStatsDServer("127.0.0.1:0", time.Second, nil, nil, nil, nil)
+	assert.Nil(t, srv.Addr())
+	assert.ErrorIs(t, srv.Serve(context.Background()), net.ErrClosed)
+}
+
+func TestStatsDServer_ListenError(t *testing.T) {
+	srv := NewStatsDServer("256.0.0.1:0", time.Second, nil, nil, nil, nil)
+	assert.Error(t, srv.Listen())
+}
@@ -1,19 +1,34 @@
 package logger
 
 import (
+	"sync"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 var Log *zap.SugaredLogger = zap.NewNop().Sugar()
 
-func Initialize(level string) error {
-	lvl, err := zap.ParseAtomicLevel(level)
-	if err != nil {
+var (
+	level       = zap.NewAtomicLevel()
+	mu          sync.Mutex
+	initialized bool
+)
+
+func Initialize(lvl string) error {
+	if err := SetLevel(lvl); err != nil {
 		return err
 	}
 
+	mu.Lock()
+	defer mu.Unlock()
+
+	if initialized {
+		return nil
+	}
+
 	cfg := zap.NewProductionConfig()
-	cfg.Level = lvl
+	cfg.Level = level
 
 	baseLogger, err := cfg.Build()
 	if err != nil {
@@ -21,5 +36,15 @@ func Initialize(level string) error {
 	}
 
 	Log = baseLogger.Sugar()
+	initialized = true
+	return nil
+}
+
+func SetLevel(lvl string) error {
+	parsed, err := zapcore.ParseLevel(lvl)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(parsed)
 	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 )
 
 func TestInitialize_Initialize(t *testing.T) {
@@ -49,6 +51,27 @@ func TestInitialize_Initialize(t *testing.T) {
 	}
 }
 
+func TestSetLevel(t *testing.T) {
+	resetLogger()
+	require.NoError(t, Initialize("info"))
+	log := Log
+
+	assert.False(t, Log.Desugar().Core().Enabled(zapcore.DebugLevel))
+
+	// уровень меняется без пересоздания логгера
+	require.NoError(t, SetLevel("debug"))
+	assert.True(t, Log.Desugar().Core().Enabled(zapcore.DebugLevel))
+	assert.Same(t, log, Log)
+
+	require.NoError(t, Initialize("warn"))
+	assert.False(t, Log.Desugar().Core().Enabled(zapcore.InfoLevel))
+	assert.Same(t, log, Log)
+
+	assert.Error(t, SetLevel("notalevel"))
+	assert.False(t, Log.Desugar().Core().Enabled(zapcore.InfoLevel))
+}
+
 func resetLogger() {
 	Log = nil
+	initialized = false
 }
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"io"
+	"net/http"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+func CryptoMiddleware(key *rsa.PrivateKey) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body.Close()
+
+			if len(body) > 0 {
+				body, err = types.DecryptRSA(key, body)
+				if err != nil {
+					http.Error(w, "Failed to decrypt request body", http.StatusBadRequest)
+					return
+				}
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCryptoMiddleware(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	plain := []byte(`[{"id":"Alloc","type":"gauge","value":1}]`)
+	large := bytes.Repeat(plain, 100)
+
+	encrypt := func(data []byte) []byte {
+		out, err := types.EncryptRSA(&key.PublicKey, data)
+		require.NoError(t, err)
+		return out
+	}
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		w.Write(data)
+	})
+
+	tests := []struct {
+		name           string
+		key            *rsa.PrivateKey
+		body           []byte
+		expectedStatus int
+		expectedBody   []byte
+	}{
+		{name: "small payload", key: key, body: encrypt(plain), expectedStatus: http.StatusOK, expectedBody: plain},
+		{name: "large payload", key: key, body: encrypt(large), expectedStatus: http.StatusOK, expectedBody: large},
+		{name: "empty body", key: key, body: nil, expectedStatus: http.StatusOK},
+		{name: "plain body rejected", key: key, body: plain, expectedStatus: http.StatusBadRequest},
+		{name: "no key configured", key: nil, body: plain, expectedStatus: http.StatusOK, expectedBody: plain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/updates/", bytes.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+
+			CryptoMiddleware(tt.key)(echo).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != nil {
+				assert.Equal(t, tt.expectedBody, rec.Body.Bytes())
+			}
+			if tt.expectedStatus == http.StatusOK && tt.expectedBody == nil {
+				assert.Empty(t, rec.Body.Bytes())
+			}
+		})
+	}
+}
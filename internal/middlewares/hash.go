@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+func HashMiddleware(key string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, "Failed to read request body", http.StatusBadRequest)
+					return
+				}
+				r.Body.Close()
+
+				if err := types.CheckHashSHA256(types.InstanceHashData(r.Header.Get(types.InstanceIDHeader), body), key, r.Header.Get(types.HashSHA256Header)); err != nil {
+					http.Error(w, "Invalid request hash", http.StatusBadRequest)
+					return
+				}
+
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			hw := &hashResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(hw, r)
+
+			w.Header().Set(types.HashSHA256Header, types.GetHashSHA256(hw.body.Bytes(), key))
+			w.WriteHeader(hw.statusCode)
+			w.Write(hw.body.Bytes())
+		})
+	}
+}
+
+type hashResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *hashResponseWriter) WriteHeader(code int) {
+	w.statusCode = code
+}
+
+func (w *hashResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
@@ -0,0 +1,117 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashMiddleware(t *testing.T) {
+	const key = "secret"
+	body := `[{"id":"Alloc","type":"gauge","value":1}]`
+
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(data)
+	})
+
+	tests := []struct {
+		name           string
+		key            string
+		method         string
+		instance       string
+		hash           string
+		expectedStatus int
+		expectedBody   string
+		expectSigned   bool
+	}{
+		{
+			name:           "valid hash",
+			key:            key,
+			method:         http.MethodPost,
+			hash:           types.GetHashSHA256([]byte(body), key),
+			expectedStatus: http.StatusCreated,
+			expectedBody:   body,
+			expectSigned:   true,
+		},
+		{
+			name:           "valid hash with instance",
+			key:            key,
+			method:         http.MethodPost,
+			instance:       "web01",
+			hash:           types.GetHashSHA256(types.InstanceHashData("web01", []byte(body)), key),
+			expectedStatus: http.StatusCreated,
+			expectedBody:   body,
+			expectSigned:   true,
+		},
+		{
+			name:           "spoofed instance",
+			key:            key,
+			method:         http.MethodPost,
+			instance:       "web02",
+			hash:           types.GetHashSHA256(types.InstanceHashData("web01", []byte(body)), key),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "hash mismatch",
+			key:            key,
+			method:         http.MethodPost,
+			hash:           types.GetHashSHA256([]byte(body), "other"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing hash",
+			key:            key,
+			method:         http.MethodPost,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "get request without hash",
+			key:            key,
+			method:         http.MethodGet,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   body,
+			expectSigned:   true,
+		},
+		{
+			name:           "no key configured",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   body,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/updates/", strings.NewReader(body))
+			if tt.hash != "" {
+				req.Header.Set(types.HashSHA256Header, tt.hash)
+			}
+			if tt.instance != "" {
+				req.Header.Set(types.InstanceIDHeader, tt.instance)
+			}
+			rec := httptest.NewRecorder()
+
+			HashMiddleware(tt.key)(echo).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+
+			respHash := rec.Header().Get(types.HashSHA256Header)
+			if tt.expectSigned {
+				assert.Equal(t, types.GetHashSHA256(rec.Body.Bytes(), tt.key), respHash)
+			} else {
+				assert.Empty(t, respHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+)
+
+func InstanceMiddleware(instanceSetter func(ctx context.Context, instance string) context.Context) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			instance := strings.TrimSpace(r.Header.Get(types.InstanceIDHeader))
+			if instance == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := validators.ValidateInstanceID(instance); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(instanceSetter(r.Context(), instance)))
+		})
+	}
+}
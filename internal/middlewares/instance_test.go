@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sbilibin2017/yp-metrics/internal/contexts"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+func TestInstanceMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		wantCode     int
+		wantInstance string
+	}{
+		{"no header", "", http.StatusOK, ""},
+		{"instance set", "web01", http.StatusOK, "web01"},
+		{"trimmed", "  web01 ", http.StatusOK, "web01"},
+		{"control characters", "web\x0001", http.StatusBadRequest, ""},
+		{"too long", strings.Repeat("a", 256), http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := InstanceMiddleware(contexts.SetInstanceToContext)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = contexts.GetInstanceFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/updates/", nil)
+			if tt.header != "" {
+				req.Header.Set(types.InstanceIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantInstance, got)
+		})
+	}
+}
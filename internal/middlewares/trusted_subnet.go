@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+)
+
+func TrustedSubnetMiddleware(subnet *net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subnet == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip := net.ParseIP(r.Header.Get("X-Real-IP"))
+			if ip == nil || !subnet.Contains(ip) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedSubnetMiddleware(t *testing.T) {
+	_, subnet, err := net.ParseCIDR("192.168.1.0/24")
+	require.NoError(t, err)
+
+	_, subnet6, err := net.ParseCIDR("fd00::/8")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		subnet         *net.IPNet
+		realIP         string
+		expectedStatus int
+	}{
+		{name: "ip in subnet", subnet: subnet, realIP: "192.168.1.10", expectedStatus: http.StatusOK},
+		{name: "ip outside subnet", subnet: subnet, realIP: "10.0.0.1", expectedStatus: http.StatusForbidden},
+		{name: "missing header", subnet: subnet, expectedStatus: http.StatusForbidden},
+		{name: "invalid ip", subnet: subnet, realIP: "not-an-ip", expectedStatus: http.StatusForbidden},
+		{name: "ipv6 in subnet", subnet: subnet6, realIP: "fd12::1", expectedStatus: http.StatusOK},
+		{name: "no subnet configured", subnet: nil, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := TrustedSubnetMiddleware(tt.subnet)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/updates/", nil)
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, called)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package proto
+
+import (
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+func NewMetric(m types.Metrics) *Metric {
+	return &Metric{
+		Id:        m.ID,
+		Type:      m.MType,
+		Delta:     m.Delta,
+		Value:     m.Value,
+		Labels:    m.Labels,
+		Histogram: newHistogram(m.Histogram),
+		Summary:   newSummary(m.Summary),
+		Members:   m.Members,
+		Set:       newSet(m.Set),
+	}
+}
+
+func NewMetrics(metrics []types.Metrics) []*Metric {
+	result := make([]*Metric, 0, len(metrics))
+	for _, m := range metrics {
+		result = append(result, NewMetric(m))
+	}
+	return result
+}
+
+func (m *Metric) ToMetrics() types.Metrics {
+	if m == nil {
+		return types.Metrics{}
+	}
+	return types.Metrics{
+		ID:        m.GetId(),
+		MType:     m.GetType(),
+		Labels:    newLabels(m.GetLabels()),
+		Delta:     m.Delta,
+		Value:     m.Value,
+		Histogram: m.GetHistogram().toHistogramValue(),
+		Summary:   m.GetSummary().toSummaryValue(),
+		Members:   m.GetMembers(),
+		Set:       m.GetSet().toSetValue(),
+	}
+}
+
+func (id *MetricID) ToMetricID() types.MetricID {
+	return types.MetricID{
+		ID:     id.GetId(),
+		MType:  id.GetType(),
+		Labels: newLabels(id.GetLabels()).String(),
+	}
+}
+
+func newLabels(labels map[string]string) types.Labels {
+	if len(labels) == 0 {
+		return nil
+	}
+	return types.Labels(labels)
+}
+
+func newHistogram(h *types.HistogramValue) *Histogram {
+	if h == nil {
+		return nil
+	}
+	return &Histogram{
+		Bounds: h.Bounds,
+		Counts: h.Counts,
+		Sum:    h.Sum,
+	}
+}
+
+func (h *Histogram) toHistogramValue() *types.HistogramValue {
+	if h == nil {
+		return nil
+	}
+	return &types.HistogramValue{
+		Bounds: h.GetBounds(),
+		Counts: h.GetCounts(),
+		Sum:    h.GetSum(),
+	}
+}
+
+func newSummary(s *types.SummaryValue) *Summary {
+	if s == nil {
+		return nil
+	}
+	return &Summary{
+		Alpha:    s.Alpha,
+		Count:    s.Count,
+		Sum:      s.Sum,
+		Min:      s.Min,
+		Max:      s.Max,
+		Zero:     s.Zero,
+		Positive: newSummaryBins(s.Positive),
+		Negative: newSummaryBins(s.Negative),
+	}
+}
+
+func (s *Summary) toSummaryValue() *types.SummaryValue {
+	if s == nil {
+		return nil
+	}
+	return &types.SummaryValue{
+		Alpha:    s.GetAlpha(),
+		Count:    s.GetCount(),
+		Sum:      s.GetSum(),
+		Min:      s.GetMin(),
+		Max:      s.GetMax(),
+		Zero:     s.GetZero(),
+		Positive: toSummaryBins(s.GetPositive()),
+		Negative: toSummaryBins(s.GetNegative()),
+	}
+}
+
+func newSummaryBins(bins map[int]int64) map[int32]int64 {
+	if len(bins) == 0 {
+		return nil
+	}
+	result := make(map[int32]int64, len(bins))
+	for k, v := range bins {
+		result[int32(k)] = v
+	}
+	return result
+}
+
+func toSummaryBins(bins map[int32]int64) map[int]int64 {
+	if len(bins) == 0 {
+		return nil
+	}
+	result := make(map[int]int64, len(bins))
+	for k, v := range bins {
+		result[int(k)] = v
+	}
+	return result
+}
+
+func newSet(s *types.SetValue) *Set {
+	if s == nil {
+		return nil
+	}
+	set := &Set{
+		Precision: uint32(s.Precision),
+		Registers: s.Registers,
+	}
+	if !s.Start.IsZero() {
+		set.StartUnixNano = s.Start.UnixNano()
+	}
+	return set
+}
+
+func (s *Set) toSetValue() *types.SetValue {
+	if s == nil {
+		return nil
+	}
+	set := &types.SetValue{
+		Precision: uint8(s.GetPrecision()),
+		Registers: s.GetRegisters(),
+	}
+	if s.GetStartUnixNano() != 0 {
+		set.Start = time.Unix(0, s.GetStartUnixNano())
+	}
+	return set
+}
@@ -0,0 +1,71 @@
+package proto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMetricConversion(t *testing.T) {
+	value := 1.5
+	delta := int64(3)
+
+	metrics := []types.Metrics{
+		{ID: "Alloc", MType: types.Gauge, Value: &value},
+		{ID: "PollCount", MType: types.Counter, Delta: &delta},
+	}
+
+	converted := NewMetrics(metrics)
+	assert.Len(t, converted, 2)
+	assert.Equal(t, "Alloc", converted[0].GetId())
+	assert.Equal(t, types.Gauge, converted[0].GetType())
+	assert.Equal(t, 1.5, converted[0].GetValue())
+	assert.Nil(t, converted[0].Delta)
+
+	for i, m := range converted {
+		assert.Equal(t, metrics[i], m.ToMetrics())
+	}
+
+	id := &MetricID{Id: "Alloc", Type: types.Gauge}
+	assert.Equal(t, types.MetricID{ID: "Alloc", MType: types.Gauge}, id.ToMetricID())
+
+	labeledID := &MetricID{Id: "Alloc", Type: types.Gauge, Labels: map[string]string{"region": "eu", "host": "web01"}}
+	assert.Equal(t, types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "host=web01,region=eu"}, labeledID.ToMetricID())
+
+	var nilMetric *Metric
+	assert.Equal(t, types.Metrics{}, nilMetric.ToMetrics())
+
+	var nilID *MetricID
+	assert.Equal(t, types.MetricID{}, nilID.ToMetricID())
+}
+
+func TestMetricConversion_ExtendedTypes(t *testing.T) {
+	value := 0.25
+	summary := types.NewSummaryValue(0.25)
+	negative := types.NewSummaryValue(-3)
+	summary, err := summary.Merge(negative)
+	require.NoError(t, err)
+	set := types.NewSetValue([]string{"alice", "bob"}, time.Unix(100, 0))
+
+	metrics := []types.Metrics{
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web01"}, Value: &value},
+		{ID: "latency", MType: types.Histogram, Histogram: &types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{1, 2, 0}, Sum: 1.2}},
+		{ID: "rt", MType: types.Summary, Summary: &summary},
+		{ID: "users", MType: types.Set, Members: []string{"alice", "bob"}},
+		{ID: "users", MType: types.Set, Set: &set},
+	}
+
+	// метрики всех типов переживают сериализацию в protobuf без потерь
+	for _, m := range metrics {
+		data, err := proto.Marshal(NewMetric(m))
+		require.NoError(t, err)
+
+		var decoded Metric
+		require.NoError(t, proto.Unmarshal(data, &decoded))
+		assert.Equal(t, m, decoded.ToMetrics())
+	}
+}
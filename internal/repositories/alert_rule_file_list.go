@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type AlertRuleFileListRepository struct {
+	mu         sync.RWMutex
+	pathToFile string
+}
+
+func NewAlertRuleFileListRepository(pathToFile string) *AlertRuleFileListRepository {
+	return &AlertRuleFileListRepository{pathToFile: pathToFile}
+}
+
+func (r *AlertRuleFileListRepository) List(ctx context.Context) ([]types.AlertRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	file, err := os.Open(r.pathToFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rules []types.AlertRule
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
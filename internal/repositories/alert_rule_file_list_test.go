@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleFileListRepository_List(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid rules", func(t *testing.T) {
+		path := filepath.Join(dir, "rules.json")
+		content := `[
+			{"name": "high_heap", "metric": {"id": "HeapAlloc", "type": "gauge"}, "kind": "threshold", "operator": ">", "threshold": 1000, "for": 30},
+			{"name": "no_polls", "metric": {"id": "PollCount", "type": "counter"}, "kind": "absence", "for": 60}
+		]`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		rules, err := NewAlertRuleFileListRepository(path).List(context.Background())
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, types.AlertRule{
+			Name:      "high_heap",
+			Metric:    types.MetricID{ID: "HeapAlloc", MType: types.Gauge},
+			Kind:      types.AlertThreshold,
+			Operator:  ">",
+			Threshold: 1000,
+			For:       30,
+		}, rules[0])
+		assert.Equal(t, types.AlertAbsence, rules[1].Kind)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		path := filepath.Join(dir, "unknown.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[{"name": "x", "severity": "high"}]`), 0644))
+
+		_, err := NewAlertRuleFileListRepository(path).List(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewAlertRuleFileListRepository(filepath.Join(dir, "missing.json")).List(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type CustomCollectorFileListRepository struct {
+	mu         sync.RWMutex
+	pathToFile string
+}
+
+func NewCustomCollectorFileListRepository(pathToFile string) *CustomCollectorFileListRepository {
+	return &CustomCollectorFileListRepository{pathToFile: pathToFile}
+}
+
+func (r *CustomCollectorFileListRepository) List(ctx context.Context) ([]types.CustomCollector, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	file, err := os.Open(r.pathToFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var collectors []types.CustomCollector
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&collectors); err != nil {
+		return nil, err
+	}
+
+	return collectors, nil
+}
@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomCollectorFileListRepository_List(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid collectors", func(t *testing.T) {
+		path := filepath.Join(dir, "collectors.json")
+		content := `[
+			{"name": "queue", "exec": ["/usr/local/bin/queue-stats", "-v"], "interval": 10, "timeout": 2},
+			{"name": "cache", "file": "/var/run/cache.metrics"}
+		]`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		collectors, err := NewCustomCollectorFileListRepository(path).List(context.Background())
+		require.NoError(t, err)
+		require.Len(t, collectors, 2)
+		assert.Equal(t, types.CustomCollector{
+			Name:     "queue",
+			Exec:     []string{"/usr/local/bin/queue-stats", "-v"},
+			Interval: 10,
+			Timeout:  2,
+		}, collectors[0])
+		assert.Equal(t, "/var/run/cache.metrics", collectors[1].File)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		path := filepath.Join(dir, "unknown.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[{"name": "x", "shell": "echo"}]`), 0644))
+
+		_, err := NewCustomCollectorFileListRepository(path).List(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewCustomCollectorFileListRepository(filepath.Join(dir, "missing.json")).List(context.Background())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"bufio"
+	"io"
+)
+
+const maxFileRecordSize = 16 << 20
+
+func newFileRecordScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxFileRecordSize)
+	return scanner
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type InstanceMemoryListRepository struct {
+	registry *InstanceRegistry
+}
+
+func NewInstanceMemoryListRepository(
+	registry *InstanceRegistry,
+) *InstanceMemoryListRepository {
+	return &InstanceMemoryListRepository{registry: registry}
+}
+
+func (r *InstanceMemoryListRepository) List(
+	ctx context.Context,
+) ([]types.Instance, error) {
+	return r.registry.list(), nil
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type InstanceRegistry struct {
+	mu       sync.RWMutex
+	lastSeen map[string]time.Time
+}
+
+func NewInstanceRegistry() *InstanceRegistry {
+	return &InstanceRegistry{lastSeen: make(map[string]time.Time)}
+}
+
+func (r *InstanceRegistry) touch(instance string, ts time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ts.After(r.lastSeen[instance]) {
+		r.lastSeen[instance] = ts
+	}
+}
+
+func (r *InstanceRegistry) list() []types.Instance {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	instances := make([]types.Instance, 0, len(r.lastSeen))
+	for id, ts := range r.lastSeen {
+		instances = append(instances, types.Instance{ID: id, LastSeen: ts})
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].ID < instances[j].ID
+	})
+
+	return instances
+}
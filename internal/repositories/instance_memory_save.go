@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+type InstanceMemorySaveRepository struct {
+	registry *InstanceRegistry
+}
+
+func NewInstanceMemorySaveRepository(
+	registry *InstanceRegistry,
+) *InstanceMemorySaveRepository {
+	return &InstanceMemorySaveRepository{registry: registry}
+}
+
+func (r *InstanceMemorySaveRepository) Save(
+	ctx context.Context,
+	instance string,
+	lastSeen time.Time,
+) error {
+	r.registry.touch(instance, lastSeen)
+	return nil
+}
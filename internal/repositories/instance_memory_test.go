@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceMemoryRepository_SaveAndList(t *testing.T) {
+	ctx := context.Background()
+	registry := NewInstanceRegistry()
+
+	saver := NewInstanceMemorySaveRepository(registry)
+	lister := NewInstanceMemoryListRepository(registry)
+
+	instances, err := lister.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, saver.Save(ctx, "web02", base))
+	require.NoError(t, saver.Save(ctx, "web01", base.Add(time.Minute)))
+	require.NoError(t, saver.Save(ctx, "web02", base.Add(2*time.Minute)))
+	// более старая отметка не откатывает время последнего обращения
+	require.NoError(t, saver.Save(ctx, "web01", base))
+
+	instances, err = lister.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []types.Instance{
+		{ID: "web01", LastSeen: base.Add(time.Minute)},
+		{ID: "web02", LastSeen: base.Add(2 * time.Minute)},
+	}, instances)
+}
@@ -29,7 +29,7 @@ func (r *MetricDBGetRepository) Get(
 
 	exec := getExecutor(ctx, r.db, r.txGetter)
 
-	err := exec.GetContext(ctx, &metric, metricGetQuery, id.ID, id.MType)
+	err := exec.GetContext(ctx, &metric, metricGetQuery, id.ID, id.MType, id.Labels)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -41,7 +41,7 @@ func (r *MetricDBGetRepository) Get(
 }
 
 const metricGetQuery = `
-SELECT id, mtype, delta, value
+SELECT id, mtype, labels, delta, value, histogram, summary, "set"
 FROM content.metrics
-WHERE id = $1 AND mtype = $2
+WHERE id = $1 AND mtype = $2 AND labels = $3
 `
@@ -60,9 +60,13 @@ func setupMetricDBListPostgresContainer(t *testing.T) (*sqlx.DB, func()) {
 	CREATE TABLE IF NOT EXISTS content.metrics (
 		id TEXT NOT NULL,
 		mtype TEXT NOT NULL,
+		labels TEXT NOT NULL DEFAULT '',
 		delta BIGINT,
 		value DOUBLE PRECISION,
-		PRIMARY KEY (id, mtype)
+		histogram JSONB,
+		summary JSONB,
+		"set" JSONB,
+		PRIMARY KEY (id, mtype, labels)
 	);
 	`
 	_, err = db.ExecContext(ctx, schema)
@@ -116,6 +120,11 @@ func TestMetricDBListRepository_List(t *testing.T) {
 		require.Equal(t, expected.Delta, got.Delta)
 		require.Equal(t, expected.Value, got.Value)
 	}
+
+	series, err := repo.ListSeries(ctx, "metric1", "gauge")
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	require.Equal(t, 10.5, *series[0].Value)
 }
 
 func ptrInt64(v int64) *int64 {
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricDBHistoryListRepository struct {
+	db       *sqlx.DB
+	txGetter func(ctx context.Context) *sqlx.Tx
+}
+
+func NewMetricDBHistoryListRepository(
+	db *sqlx.DB,
+	txGetter func(ctx context.Context) *sqlx.Tx,
+) *MetricDBHistoryListRepository {
+	return &MetricDBHistoryListRepository{db: db, txGetter: txGetter}
+}
+
+func (r *MetricDBHistoryListRepository) List(
+	ctx context.Context,
+	id types.MetricID,
+	from time.Time,
+	to time.Time,
+) ([]types.MetricSample, error) {
+	samples := make([]types.MetricSample, 0)
+	exec := getExecutor(ctx, r.db, r.txGetter)
+	err := exec.SelectContext(ctx, &samples, metricHistoryListQuery, id.ID, id.MType, id.Labels, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+const metricHistoryListQuery = `
+SELECT ts AS timestamp, delta, value, histogram, summary
+FROM content.metric_history
+WHERE id = $1 AND mtype = $2 AND labels = $3 AND ts >= $4 AND ts <= $5
+ORDER BY ts
+`
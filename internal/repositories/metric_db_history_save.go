@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricDBHistorySaveRepository struct {
+	db       *sqlx.DB
+	txGetter func(ctx context.Context) *sqlx.Tx
+}
+
+func NewMetricDBHistorySaveRepository(
+	db *sqlx.DB,
+	txGetter func(ctx context.Context) *sqlx.Tx,
+) *MetricDBHistorySaveRepository {
+	return &MetricDBHistorySaveRepository{db: db, txGetter: txGetter}
+}
+
+func (r *MetricDBHistorySaveRepository) Save(
+	ctx context.Context,
+	id types.MetricID,
+	sample types.MetricSample,
+) error {
+	exec := getExecutor(ctx, r.db, r.txGetter)
+	_, err := exec.ExecContext(
+		ctx,
+		metricHistorySaveQuery,
+		id.ID,
+		id.MType,
+		id.Labels,
+		sample.Timestamp,
+		sample.Delta,
+		sample.Value,
+		sample.Histogram,
+		sample.Summary,
+	)
+	return err
+}
+
+const metricHistorySaveQuery = `
+INSERT INTO content.metric_history (id, mtype, labels, ts, delta, value, histogram, summary)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
@@ -0,0 +1,114 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sbilibin2017/yp-metrics/internal/repositories"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func setupMetricDBHistoryPostgresContainer(t *testing.T) (*sqlx.DB, func()) {
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image: "postgres:15-alpine",
+		Env: map[string]string{
+			"POSTGRES_DB":       "testdb",
+			"POSTGRES_USER":     "testuser",
+			"POSTGRES_PASSWORD": "testpass",
+		},
+		ExposedPorts: []string{"5432/tcp"},
+		WaitingFor:   wait.ForListeningPort("5432/tcp").WithStartupTimeout(30 * time.Second),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	require.NoError(t, err)
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "5432")
+	require.NoError(t, err)
+
+	dsn := fmt.Sprintf("host=%s port=%s user=testuser password=testpass dbname=testdb sslmode=disable", host, port.Port())
+
+	var db *sqlx.DB
+	for i := 0; i < 10; i++ {
+		db, err = sqlx.ConnectContext(ctx, "pgx", dsn)
+		if err == nil {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	require.NoError(t, err)
+
+	schema := `
+	CREATE SCHEMA IF NOT EXISTS content;
+
+	CREATE TABLE IF NOT EXISTS content.metric_history (
+		id TEXT NOT NULL,
+		mtype TEXT NOT NULL,
+		labels TEXT NOT NULL DEFAULT '',
+		ts TIMESTAMPTZ NOT NULL,
+		delta BIGINT,
+		value DOUBLE PRECISION,
+		histogram JSONB,
+		summary JSONB
+	);
+	`
+	_, err = db.ExecContext(ctx, schema)
+	require.NoError(t, err)
+
+	return db, func() {
+		db.Close()
+		container.Terminate(ctx)
+	}
+}
+
+func TestMetricDBHistoryRepository_SaveAndList(t *testing.T) {
+	db, cleanup := setupMetricDBHistoryPostgresContainer(t)
+	defer cleanup()
+
+	txGetter := func(ctx context.Context) *sqlx.Tx {
+		return nil
+	}
+	saver := repositories.NewMetricDBHistorySaveRepository(db, txGetter)
+	lister := repositories.NewMetricDBHistoryListRepository(db, txGetter)
+
+	ctx := context.Background()
+	id := types.MetricID{ID: "HeapAlloc", MType: types.Gauge}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		v := float64(i)
+		err := saver.Save(ctx, id, types.MetricSample{Timestamp: base.Add(time.Duration(i) * time.Minute), Value: &v})
+		require.NoError(t, err)
+	}
+
+	samples, err := lister.List(ctx, id, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	require.Equal(t, 0.0, *samples[0].Value)
+	require.Equal(t, 2.0, *samples[2].Value)
+	require.True(t, samples[1].Timestamp.Equal(base.Add(time.Minute)))
+
+	samples, err = lister.List(ctx, id, base.Add(time.Minute), base.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+
+	samples, err = lister.List(ctx, types.MetricID{ID: "missing", MType: types.Gauge}, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, samples)
+}
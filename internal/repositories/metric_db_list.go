@@ -32,7 +32,27 @@ func (r *MetricDBListRepository) List(ctx context.Context) ([]types.Metrics, err
 	return metrics, nil
 }
 
+func (r *MetricDBListRepository) ListSeries(ctx context.Context, id string, mtype string) ([]types.Metrics, error) {
+	var metrics []types.Metrics
+
+	exec := getExecutor(ctx, r.db, r.txGetter)
+
+	err := exec.SelectContext(ctx, &metrics, metricListSeriesQuery, id, mtype)
+	if err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
 const metricListQuery = `
-SELECT id, mtype, delta, value
+SELECT id, mtype, labels, delta, value, histogram, summary, "set"
+FROM content.metrics
+`
+
+const metricListSeriesQuery = `
+SELECT id, mtype, labels, delta, value, histogram, summary, "set"
 FROM content.metrics
+WHERE id = $1 AND mtype = $2
+ORDER BY labels
 `
@@ -57,9 +57,13 @@ func setupMetricDBGetPostgresContainer(t *testing.T) (*sqlx.DB, func()) {
 	CREATE TABLE IF NOT EXISTS content.metrics (
 		id TEXT NOT NULL,
 		mtype TEXT NOT NULL,
+		labels TEXT NOT NULL DEFAULT '',
 		delta BIGINT,
 		value DOUBLE PRECISION,
-		PRIMARY KEY (id, mtype)
+		histogram JSONB,
+		summary JSONB,
+		"set" JSONB,
+		PRIMARY KEY (id, mtype, labels)
 	);
 	`
 	_, err = db.ExecContext(ctx, schema)
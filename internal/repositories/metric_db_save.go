@@ -30,17 +30,24 @@ func (r *MetricDBSaveRepository) Save(
 		metricSaveQuery,
 		metrics.ID,
 		metrics.MType,
+		metrics.Labels,
 		metrics.Delta,
 		metrics.Value,
+		metrics.Histogram,
+		metrics.Summary,
+		metrics.Set,
 	)
 
 	return err
 }
 
 const metricSaveQuery = `
-INSERT INTO content.metrics (id, mtype, delta, value)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (id, mtype) DO UPDATE SET
+INSERT INTO content.metrics (id, mtype, labels, delta, value, histogram, summary, "set")
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (id, mtype, labels) DO UPDATE SET
 	delta = EXCLUDED.delta,
-	value = EXCLUDED.value
+	value = EXCLUDED.value,
+	histogram = EXCLUDED.histogram,
+	summary = EXCLUDED.summary,
+	"set" = EXCLUDED."set"
 `
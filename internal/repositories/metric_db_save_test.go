@@ -60,9 +60,13 @@ func setupMetricDBSavePostgresContainer(t *testing.T) (*sqlx.DB, func()) {
 	CREATE TABLE IF NOT EXISTS content.metrics (
 		id TEXT NOT NULL,
 		mtype TEXT NOT NULL,
+		labels TEXT NOT NULL DEFAULT '',
 		delta BIGINT,
 		value DOUBLE PRECISION,
-		PRIMARY KEY (id, mtype)
+		histogram JSONB,
+		summary JSONB,
+		"set" JSONB,
+		PRIMARY KEY (id, mtype, labels)
 	);
 	`
 	_, err = db.ExecContext(ctx, schema)
@@ -103,6 +107,68 @@ func TestMetricDBSaveRepository_Save(t *testing.T) {
 	require.Nil(t, result.Delta)
 	require.NotNil(t, result.Value)
 	require.Equal(t, *metric.Value, *result.Value)
+
+	// та же метрика с метками сохраняется отдельной строкой
+	labeled := types.Metrics{
+		ID:     "metric1",
+		MType:  "gauge",
+		Labels: types.Labels{"host": "web01"},
+		Value:  ptrFloat64(1),
+	}
+	require.NoError(t, repo.Save(ctx, labeled))
+
+	var results []types.Metrics
+	err = db.SelectContext(ctx, &results, `
+		SELECT id, mtype, labels, delta, value FROM content.metrics WHERE id=$1 ORDER BY labels
+	`, metric.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Nil(t, results[0].Labels)
+	require.Equal(t, labeled.Labels, results[1].Labels)
+	require.Equal(t, 123.45, *results[0].Value)
+
+	// гистограмма сохраняется в JSONB и читается обратно целиком
+	histogram := types.Metrics{
+		ID:    "latency",
+		MType: types.Histogram,
+		Histogram: &types.HistogramValue{
+			Bounds: []float64{0.1, 0.5},
+			Counts: []int64{1, 2, 0},
+			Sum:    0.9,
+		},
+	}
+	require.NoError(t, repo.Save(ctx, histogram))
+
+	var saved types.Metrics
+	err = db.GetContext(ctx, &saved, `
+		SELECT id, mtype, labels, delta, value, histogram FROM content.metrics WHERE id=$1
+	`, histogram.ID)
+	require.NoError(t, err)
+	require.Equal(t, histogram.Histogram, saved.Histogram)
+
+	summaryValue := types.NewSummaryValue(0.25)
+	summary := types.Metrics{ID: "rt", MType: types.Summary, Summary: &summaryValue}
+	require.NoError(t, repo.Save(ctx, summary))
+
+	saved = types.Metrics{}
+	err = db.GetContext(ctx, &saved, `
+		SELECT id, mtype, labels, delta, value, histogram, summary FROM content.metrics WHERE id=$1
+	`, summary.ID)
+	require.NoError(t, err)
+	require.Equal(t, summary.Summary, saved.Summary)
+
+	setValue := types.NewSetValue([]string{"alice", "bob"}, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	set := types.Metrics{ID: "users", MType: types.Set, Set: &setValue}
+	require.NoError(t, repo.Save(ctx, set))
+
+	saved = types.Metrics{}
+	err = db.GetContext(ctx, &saved, `
+		SELECT id, mtype, labels, delta, value, histogram, summary, "set" FROM content.metrics WHERE id=$1
+	`, set.ID)
+	require.NoError(t, err)
+	require.NotNil(t, saved.Set)
+	require.Equal(t, uint64(2), saved.Set.Cardinality())
+	require.True(t, setValue.Start.Equal(saved.Set.Start))
 }
 
 func ptrFloat64(v float64) *float64 {
@@ -1,7 +1,6 @@
 package repositories
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"io"
@@ -38,7 +37,7 @@ func (r *MetricFileGetRepository) Get(
 
 	var metricFound *types.Metrics
 
-	scanner := bufio.NewScanner(file)
+	scanner := newFileRecordScanner(file)
 	for scanner.Scan() {
 		line := scanner.Bytes()
 
@@ -47,7 +46,7 @@ func (r *MetricFileGetRepository) Get(
 			continue
 		}
 
-		if metric.ID == id.ID && metric.MType == id.MType {
+		if metric.MetricID() == id {
 			metricFound = &metric
 		}
 	}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMetricFileGetRepository_Get(t *testing.T) {
@@ -43,3 +46,21 @@ func TestMetricFileGetRepository_Get(t *testing.T) {
 	assert.NotNil(t, got.Value)
 	assert.Equal(t, *metric.Value, *got.Value)
 }
+
+func TestMetricFileGetRepository_LongLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	value := 1.0
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	encoder := json.NewEncoder(file)
+	// строка длиннее стандартного буфера bufio.Scanner в 64KB
+	require.NoError(t, encoder.Encode(types.Metrics{ID: "big", MType: types.Gauge, Value: &value, Labels: types.Labels{"tag": strings.Repeat("x", 100_000)}}))
+	require.NoError(t, encoder.Encode(types.Metrics{ID: "Alloc", MType: types.Gauge, Value: &value}))
+	require.NoError(t, file.Close())
+
+	got, err := NewMetricFileGetRepository(path).Get(context.Background(), types.MetricID{ID: "Alloc", MType: types.Gauge})
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 1.0, *got.Value)
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricFileHistoryListRepository struct {
+	mu         sync.RWMutex
+	pathToFile string
+}
+
+func NewMetricFileHistoryListRepository(pathToFile string) *MetricFileHistoryListRepository {
+	return &MetricFileHistoryListRepository{pathToFile: pathToFile}
+}
+
+func (r *MetricFileHistoryListRepository) List(
+	ctx context.Context,
+	id types.MetricID,
+	from time.Time,
+	to time.Time,
+) ([]types.MetricSample, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	samples := make([]types.MetricSample, 0)
+
+	file, err := os.Open(r.pathToFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return samples, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	idField, err := json.Marshal(id.ID)
+	if err != nil {
+		return nil, err
+	}
+	idField = append([]byte(`"id":`), idField...)
+
+	scanner := newFileRecordScanner(file)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		line := scanner.Bytes()
+		if !bytes.Contains(line, idField) {
+			continue
+		}
+
+		var header metricFileHistoryTimestamp
+		if err := json.Unmarshal(line, &header); err != nil {
+			continue
+		}
+		if header.Timestamp.Before(from) || header.Timestamp.After(to) {
+			continue
+		}
+
+		var record metricFileHistoryRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			continue
+		}
+		if record.ID != id.ID || record.MType != id.MType || record.Labels.String() != id.Labels {
+			continue
+		}
+		samples = append(samples, record.MetricSample)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Timestamp.Before(samples[j].Timestamp)
+	})
+
+	return samples, nil
+}
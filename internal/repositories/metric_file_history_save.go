@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type metricFileHistoryRecord struct {
+	ID     string       `json:"id"`
+	MType  string       `json:"type"`
+	Labels types.Labels `json:"labels,omitempty"`
+	types.MetricSample
+}
+
+type metricFileHistoryTimestamp struct {
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type MetricFileHistorySaveRepository struct {
+	mu          sync.Mutex
+	pathToFile  string
+	retention   time.Duration
+	lastCompact time.Time
+}
+
+func NewMetricFileHistorySaveRepository(pathToFile string, retention time.Duration) *MetricFileHistorySaveRepository {
+	return &MetricFileHistorySaveRepository{
+		pathToFile: pathToFile,
+		retention:  retention,
+	}
+}
+
+func (r *MetricFileHistorySaveRepository) Save(
+	ctx context.Context,
+	id types.MetricID,
+	sample types.MetricSample,
+) error {
+	labels, err := types.ParseLabels(id.Labels)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(r.pathToFile), 0755); err != nil {
+		return err
+	}
+
+	if err := r.compact(time.Now()); err != nil {
+		logger.Log.Errorw("Failed to compact metric history file", "path", r.pathToFile, "error", err)
+	}
+
+	file, err := os.OpenFile(r.pathToFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return json.NewEncoder(file).Encode(metricFileHistoryRecord{
+		ID:           id.ID,
+		MType:        id.MType,
+		Labels:       labels,
+		MetricSample: sample,
+	})
+}
+
+func (r *MetricFileHistorySaveRepository) compact(now time.Time) error {
+	if r.retention <= 0 || now.Sub(r.lastCompact) < r.retention {
+		return nil
+	}
+	r.lastCompact = now
+
+	src, err := os.Open(r.pathToFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer src.Close()
+
+	tmp := r.pathToFile + ".tmp"
+	dst, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	cutoff := now.Add(-r.retention)
+	writer := bufio.NewWriter(dst)
+	scanner := newFileRecordScanner(src)
+	for scanner.Scan() {
+		var record metricFileHistoryTimestamp
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if record.Timestamp.Before(cutoff) {
+			continue
+		}
+		writer.Write(scanner.Bytes())
+		writer.WriteByte('\n')
+	}
+
+	if err := scanner.Err(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.pathToFile)
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricFileHistoryRepository_SaveAndList(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", "history.json")
+
+	saver := NewMetricFileHistorySaveRepository(path, 0)
+	lister := NewMetricFileHistoryListRepository(path)
+
+	id := types.MetricID{ID: "PollCount", MType: types.Counter}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("missing file returns empty series", func(t *testing.T) {
+		samples, err := lister.List(ctx, id, time.Time{}, base.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, samples)
+	})
+
+	for i := int64(1); i <= 3; i++ {
+		d := i
+		require.NoError(t, saver.Save(ctx, id, types.MetricSample{Timestamp: base.Add(time.Duration(i) * time.Minute), Delta: &d}))
+	}
+	v := 1.0
+	require.NoError(t, saver.Save(ctx, types.MetricID{ID: "PollCount", MType: types.Gauge}, types.MetricSample{Timestamp: base, Value: &v}))
+
+	t.Run("file is an append log", func(t *testing.T) {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, 4, strings.Count(string(content), "\n"))
+		assert.Contains(t, string(content), `"id":"PollCount","type":"counter"`)
+	})
+
+	t.Run("all samples of metric", func(t *testing.T) {
+		samples, err := lister.List(ctx, id, time.Time{}, base.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, samples, 3)
+		assert.Equal(t, int64(1), *samples[0].Delta)
+		assert.Equal(t, int64(3), *samples[2].Delta)
+		assert.True(t, samples[0].Timestamp.Equal(base.Add(time.Minute)))
+	})
+
+	t.Run("time range filter", func(t *testing.T) {
+		samples, err := lister.List(ctx, id, base.Add(2*time.Minute), base.Add(3*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, samples, 2)
+		assert.Equal(t, int64(2), *samples[0].Delta)
+	})
+
+	t.Run("labeled series are separate", func(t *testing.T) {
+		labeled := types.MetricID{ID: "PollCount", MType: types.Counter, Labels: "host=web01"}
+		d := int64(10)
+		require.NoError(t, saver.Save(ctx, labeled, types.MetricSample{Timestamp: base, Delta: &d}))
+
+		samples, err := lister.List(ctx, labeled, time.Time{}, base.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, samples, 1)
+		assert.Equal(t, int64(10), *samples[0].Delta)
+
+		samples, err = lister.List(ctx, id, time.Time{}, base.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, samples, 3)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"labels":{"host":"web01"}`)
+	})
+}
+
+func TestMetricFileHistoryRepository_Retention(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	saver := NewMetricFileHistorySaveRepository(path, time.Hour)
+	lister := NewMetricFileHistoryListRepository(path)
+
+	id := types.MetricID{ID: "Alloc", MType: types.Gauge}
+	now := time.Now()
+
+	old, fresh := 1.0, 2.0
+	require.NoError(t, saver.Save(ctx, id, types.MetricSample{Timestamp: now.Add(-2 * time.Hour), Value: &old}))
+	require.NoError(t, saver.Save(ctx, id, types.MetricSample{Timestamp: now, Value: &fresh}))
+
+	samples, err := lister.List(ctx, id, time.Time{}, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+
+	// срок хранения истёк: следующая запись сжимает журнал
+	saver.lastCompact = now.Add(-2 * time.Hour)
+	latest := 3.0
+	require.NoError(t, saver.Save(ctx, id, types.MetricSample{Timestamp: now, Value: &latest}))
+
+	samples, err = lister.List(ctx, id, time.Time{}, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, 2.0, *samples[0].Value)
+	assert.Equal(t, 3.0, *samples[1].Value)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), "\n"))
+}
+
+func TestMetricFileHistoryListRepository_Canceled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	saver := NewMetricFileHistorySaveRepository(path, 0)
+	lister := NewMetricFileHistoryListRepository(path)
+
+	id := types.MetricID{ID: "Alloc", MType: types.Gauge}
+	v := 1.0
+	require.NoError(t, saver.Save(context.Background(), id, types.MetricSample{Timestamp: time.Now(), Value: &v}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := lister.List(ctx, id, time.Time{}, time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMetricFileHistoryRepository_CompactionFailure(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "history.json")
+
+	saver := NewMetricFileHistorySaveRepository(path, time.Hour)
+	id := types.MetricID{ID: "Alloc", MType: types.Gauge}
+	v := 1.0
+
+	require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0644))
+	// временный файл не создаётся: сжатие завершается ошибкой
+	require.NoError(t, os.Mkdir(path+".tmp", 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(path+".tmp", "busy"), nil, 0644))
+
+	// ошибка сжатия не мешает записи, следующая попытка откладывается
+	require.NoError(t, saver.Save(ctx, id, types.MetricSample{Timestamp: time.Now(), Value: &v}))
+	assert.False(t, saver.lastCompact.IsZero())
+	require.NoError(t, saver.Save(ctx, id, types.MetricSample{Timestamp: time.Now(), Value: &v}))
+
+	samples, err := NewMetricFileHistoryListRepository(path).List(ctx, id, time.Time{}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, samples, 2)
+}
@@ -25,6 +25,50 @@ func (r *MetricFileListRepository) List(ctx context.Context) ([]types.Metrics, e
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	resultMap, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.Metrics, 0, len(resultMap))
+	for _, m := range resultMap {
+		result = append(result, m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ID != result[j].ID {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].Labels.String() < result[j].Labels.String()
+	})
+
+	return result, nil
+}
+
+func (r *MetricFileListRepository) ListSeries(ctx context.Context, id string, mtype string) ([]types.Metrics, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	resultMap, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.Metrics, 0)
+	for key, m := range resultMap {
+		if key.ID == id && key.MType == mtype {
+			result = append(result, m)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Labels.String() < result[j].Labels.String()
+	})
+
+	return result, nil
+}
+
+func (r *MetricFileListRepository) load() (map[types.MetricID]types.Metrics, error) {
 	_, err := os.Stat(r.pathToFile)
 	if os.IsNotExist(err) {
 		if err := os.MkdirAll(filepath.Dir(r.pathToFile), 0755); err != nil {
@@ -35,7 +79,7 @@ func (r *MetricFileListRepository) List(ctx context.Context) ([]types.Metrics, e
 			return nil, err
 		}
 		f.Close()
-		return []types.Metrics{}, nil
+		return map[types.MetricID]types.Metrics{}, nil
 	} else if err != nil {
 		return nil, err
 	}
@@ -57,17 +101,8 @@ func (r *MetricFileListRepository) List(ctx context.Context) ([]types.Metrics, e
 			}
 			return nil, err
 		}
-		resultMap[types.MetricID{ID: m.ID, MType: m.MType}] = m
-	}
-
-	result := make([]types.Metrics, 0, len(resultMap))
-	for _, m := range resultMap {
-		result = append(result, m)
+		resultMap[m.MetricID()] = m
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].ID < result[j].ID
-	})
-
-	return result, nil
+	return resultMap, nil
 }
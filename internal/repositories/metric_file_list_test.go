@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sbilibin2017/yp-metrics/internal/types"
@@ -60,3 +61,28 @@ func TestMetricFileListRepository_List(t *testing.T) {
 	assert.True(t, foundGauge)
 	assert.True(t, foundCounter)
 }
+
+func TestMetricFileListRepository_ListSeries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	one, two, three := 1.0, 2.0, 3.0
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	encoder := json.NewEncoder(file)
+	for _, m := range []types.Metrics{
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web02"}, Value: &one},
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web01"}, Value: &one},
+		{ID: "Sys", MType: types.Gauge, Value: &three},
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web02"}, Value: &two},
+	} {
+		require.NoError(t, encoder.Encode(m))
+	}
+	require.NoError(t, file.Close())
+
+	result, err := NewMetricFileListRepository(path).ListSeries(context.Background(), "Alloc", types.Gauge)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, types.Labels{"instance": "web01"}, result[0].Labels)
+	// из журнала берётся последнее значение серии
+	assert.Equal(t, 2.0, *result[1].Value)
+}
@@ -39,3 +39,25 @@ func TestMetricFileSaveRepository_Save(t *testing.T) {
 	assert.Contains(t, string(data), `"id":"metric1"`)
 	assert.Contains(t, string(data), `"value":42`)
 }
+
+func TestMetricFileSaveRepository_Save_Summary(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "metrics_test_*.jsonl")
+	require.NoError(t, err)
+	tmpFilePath := tmpFile.Name()
+	tmpFile.Close()
+	defer os.Remove(tmpFilePath)
+
+	ctx := context.Background()
+
+	summary, err := types.NewSummaryValue(0.2).Merge(types.NewSummaryValue(-3))
+	require.NoError(t, err)
+	metric := types.Metrics{ID: "rt", MType: types.Summary, Summary: &summary}
+
+	require.NoError(t, NewMetricFileSaveRepository(tmpFilePath).Save(ctx, metric))
+
+	// скетч переживает сохранение в файл без потерь
+	got, err := NewMetricFileGetRepository(tmpFilePath).Get(ctx, metric.MetricID())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, metric.Summary, got.Summary)
+}
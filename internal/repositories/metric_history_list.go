@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type HistoryLister interface {
+	List(ctx context.Context, id types.MetricID, from time.Time, to time.Time) ([]types.MetricSample, error)
+}
+
+type MetricHistoryListerContext struct {
+	strategy HistoryLister
+}
+
+func NewMetricHistoryListerContext() *MetricHistoryListerContext {
+	return &MetricHistoryListerContext{}
+}
+
+func (m *MetricHistoryListerContext) SetContext(s HistoryLister) {
+	m.strategy = s
+}
+
+func (m *MetricHistoryListerContext) List(
+	ctx context.Context,
+	id types.MetricID,
+	from time.Time,
+	to time.Time,
+) ([]types.MetricSample, error) {
+	if m.strategy == nil {
+		return nil, errors.New("strategy is not set")
+	}
+	return m.strategy.List(ctx, id, from, to)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/repositories/metric_history_list.go
+
+// Package repositories is a generated GoMock package.
+package repositories
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockHistoryLister is a mock of HistoryLister interface.
+type MockHistoryLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistoryListerMockRecorder
+}
+
+// MockHistoryListerMockRecorder is the mock recorder for MockHistoryLister.
+type MockHistoryListerMockRecorder struct {
+	mock *MockHistoryLister
+}
+
+// NewMockHistoryLister creates a new mock instance.
+func NewMockHistoryLister(ctrl *gomock.Controller) *MockHistoryLister {
+	mock := &MockHistoryLister{ctrl: ctrl}
+	mock.recorder = &MockHistoryListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistoryLister) EXPECT() *MockHistoryListerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockHistoryLister) List(ctx context.Context, id types.MetricID, from, to time.Time) ([]types.MetricSample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, id, from, to)
+	ret0, _ := ret[0].([]types.MetricSample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockHistoryListerMockRecorder) List(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHistoryLister)(nil).List), ctx, id, from, to)
+}
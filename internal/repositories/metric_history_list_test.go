@@ -0,0 +1,47 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/repositories"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricHistoryLister_List_NoStrategy(t *testing.T) {
+	hl := repositories.NewMetricHistoryListerContext()
+
+	samples, err := hl.List(context.Background(), types.MetricID{}, time.Time{}, time.Now())
+	require.Error(t, err)
+	require.Nil(t, samples)
+	require.Equal(t, "strategy is not set", err.Error())
+}
+
+func TestMetricHistoryLister_List_WithStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := repositories.NewMockHistoryLister(ctrl)
+
+	id := types.MetricID{ID: "metric1", MType: types.Gauge}
+	from := time.Unix(0, 0)
+	to := time.Unix(1000, 0)
+	expected := []types.MetricSample{{Timestamp: time.Unix(100, 0)}}
+
+	hl := repositories.NewMetricHistoryListerContext()
+	hl.SetContext(mockLister)
+
+	mockLister.EXPECT().List(gomock.Any(), id, from, to).Return(expected, nil).Times(1)
+	samples, err := hl.List(context.Background(), id, from, to)
+	require.NoError(t, err)
+	require.Equal(t, expected, samples)
+
+	expectedErr := errors.New("list failed")
+	mockLister.EXPECT().List(gomock.Any(), id, from, to).Return(nil, expectedErr).Times(1)
+	_, err = hl.List(context.Background(), id, from, to)
+	require.Equal(t, expectedErr, err)
+}
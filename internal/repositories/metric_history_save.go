@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type HistorySaver interface {
+	Save(ctx context.Context, id types.MetricID, sample types.MetricSample) error
+}
+
+type MetricHistorySaverContext struct {
+	strategy HistorySaver
+}
+
+func NewMetricHistorySaverContext() *MetricHistorySaverContext {
+	return &MetricHistorySaverContext{}
+}
+
+func (m *MetricHistorySaverContext) SetContext(s HistorySaver) {
+	m.strategy = s
+}
+
+func (m *MetricHistorySaverContext) Save(ctx context.Context, id types.MetricID, sample types.MetricSample) error {
+	if m.strategy == nil {
+		return errors.New("strategy is not set")
+	}
+	return m.strategy.Save(ctx, id, sample)
+}
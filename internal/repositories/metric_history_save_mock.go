@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/repositories/metric_history_save.go
+
+// Package repositories is a generated GoMock package.
+package repositories
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockHistorySaver is a mock of HistorySaver interface.
+type MockHistorySaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockHistorySaverMockRecorder
+}
+
+// MockHistorySaverMockRecorder is the mock recorder for MockHistorySaver.
+type MockHistorySaverMockRecorder struct {
+	mock *MockHistorySaver
+}
+
+// NewMockHistorySaver creates a new mock instance.
+func NewMockHistorySaver(ctrl *gomock.Controller) *MockHistorySaver {
+	mock := &MockHistorySaver{ctrl: ctrl}
+	mock.recorder = &MockHistorySaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHistorySaver) EXPECT() *MockHistorySaverMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockHistorySaver) Save(ctx context.Context, id types.MetricID, sample types.MetricSample) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, id, sample)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockHistorySaverMockRecorder) Save(ctx, id, sample interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockHistorySaver)(nil).Save), ctx, id, sample)
+}
@@ -0,0 +1,42 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/repositories"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricHistorySaver_Save_NoStrategy(t *testing.T) {
+	hs := repositories.NewMetricHistorySaverContext()
+
+	err := hs.Save(context.Background(), types.MetricID{}, types.MetricSample{})
+	require.Error(t, err)
+	require.Equal(t, "strategy is not set", err.Error())
+}
+
+func TestMetricHistorySaver_Save_WithStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSaver := repositories.NewMockHistorySaver(ctrl)
+
+	id := types.MetricID{ID: "metric1", MType: types.Gauge}
+	sample := types.MetricSample{Timestamp: time.Unix(100, 0)}
+
+	hs := repositories.NewMetricHistorySaverContext()
+	hs.SetContext(mockSaver)
+
+	expectedErr := errors.New("save failed")
+
+	mockSaver.EXPECT().Save(gomock.Any(), id, sample).Return(nil).Times(1)
+	require.NoError(t, hs.Save(context.Background(), id, sample))
+
+	mockSaver.EXPECT().Save(gomock.Any(), id, sample).Return(expectedErr).Times(1)
+	require.Equal(t, expectedErr, hs.Save(context.Background(), id, sample))
+}
@@ -9,6 +9,7 @@ import (
 
 type Lister interface {
 	List(ctx context.Context) ([]types.Metrics, error)
+	ListSeries(ctx context.Context, id string, mtype string) ([]types.Metrics, error)
 }
 
 type MetricListerContext struct {
@@ -29,3 +30,10 @@ func (m *MetricListerContext) List(ctx context.Context) ([]types.Metrics, error)
 	}
 	return m.strategy.List(ctx)
 }
+
+func (m *MetricListerContext) ListSeries(ctx context.Context, id string, mtype string) ([]types.Metrics, error) {
+	if m.strategy == nil {
+		return nil, errors.New("strategy is not set")
+	}
+	return m.strategy.ListSeries(ctx, id, mtype)
+}
@@ -67,3 +67,22 @@ func TestMetricListerContext_List_WithStrategy_Error(t *testing.T) {
 	require.Nil(t, metrics)
 	require.Equal(t, expectedErr, err)
 }
+
+func TestMetricListerContext_ListSeries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := repositories.NewMetricListerContext().ListSeries(context.Background(), "Alloc", types.Gauge)
+	require.Error(t, err)
+
+	mockLister := repositories.NewMockLister(ctrl)
+	expected := []types.Metrics{{ID: "Alloc", MType: types.Gauge}}
+	mockLister.EXPECT().ListSeries(gomock.Any(), "Alloc", types.Gauge).Return(expected, nil)
+
+	lister := repositories.NewMetricListerContext()
+	lister.SetContext(mockLister)
+
+	metrics, err := lister.ListSeries(context.Background(), "Alloc", types.Gauge)
+	require.NoError(t, err)
+	require.Equal(t, expected, metrics)
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricHistoryBuffer struct {
+	mu       sync.RWMutex
+	capacity int
+	series   map[types.MetricID]*metricSampleRing
+}
+
+func NewMetricHistoryBuffer(capacity int) *MetricHistoryBuffer {
+	return &MetricHistoryBuffer{
+		capacity: capacity,
+		series:   make(map[types.MetricID]*metricSampleRing),
+	}
+}
+
+func (b *MetricHistoryBuffer) push(id types.MetricID, sample types.MetricSample) {
+	if b.capacity <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ring, ok := b.series[id]
+	if !ok {
+		ring = &metricSampleRing{capacity: b.capacity}
+		b.series[id] = ring
+	}
+	ring.push(sample)
+}
+
+func (b *MetricHistoryBuffer) between(id types.MetricID, from time.Time, to time.Time) []types.MetricSample {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	samples := make([]types.MetricSample, 0)
+
+	ring, ok := b.series[id]
+	if !ok {
+		return samples
+	}
+
+	for i := 0; i < len(ring.samples); i++ {
+		sample := ring.samples[(ring.start+i)%len(ring.samples)]
+		if sample.Timestamp.Before(from) || sample.Timestamp.After(to) {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+type metricSampleRing struct {
+	capacity int
+	start    int
+	samples  []types.MetricSample
+}
+
+func (r *metricSampleRing) push(sample types.MetricSample) {
+	if len(r.samples) < r.capacity {
+		r.samples = append(r.samples, sample)
+		return
+	}
+	r.samples[r.start] = sample
+	r.start = (r.start + 1) % r.capacity
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricMemoryHistoryListRepository struct {
+	buffer *MetricHistoryBuffer
+}
+
+func NewMetricMemoryHistoryListRepository(
+	buffer *MetricHistoryBuffer,
+) *MetricMemoryHistoryListRepository {
+	return &MetricMemoryHistoryListRepository{buffer: buffer}
+}
+
+func (r *MetricMemoryHistoryListRepository) List(
+	ctx context.Context,
+	id types.MetricID,
+	from time.Time,
+	to time.Time,
+) ([]types.MetricSample, error) {
+	return r.buffer.between(id, from, to), nil
+}
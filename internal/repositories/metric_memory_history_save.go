@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricMemoryHistorySaveRepository struct {
+	buffer *MetricHistoryBuffer
+}
+
+func NewMetricMemoryHistorySaveRepository(
+	buffer *MetricHistoryBuffer,
+) *MetricMemoryHistorySaveRepository {
+	return &MetricMemoryHistorySaveRepository{buffer: buffer}
+}
+
+func (r *MetricMemoryHistorySaveRepository) Save(
+	ctx context.Context,
+	id types.MetricID,
+	sample types.MetricSample,
+) error {
+	r.buffer.push(id, sample)
+	return nil
+}
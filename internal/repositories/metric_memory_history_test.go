@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricMemoryHistoryRepository_SaveAndList(t *testing.T) {
+	ctx := context.Background()
+	buffer := NewMetricHistoryBuffer(3)
+
+	saver := NewMetricMemoryHistorySaveRepository(buffer)
+	lister := NewMetricMemoryHistoryListRepository(buffer)
+
+	id := types.MetricID{ID: "HeapAlloc", MType: types.Gauge}
+	other := types.MetricID{ID: "PollCount", MType: types.Counter}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		v := float64(i)
+		require.NoError(t, saver.Save(ctx, id, types.MetricSample{Timestamp: base.Add(time.Duration(i) * time.Second), Value: &v}))
+	}
+	d := int64(1)
+	require.NoError(t, saver.Save(ctx, other, types.MetricSample{Timestamp: base, Delta: &d}))
+
+	t.Run("ring keeps last samples in order", func(t *testing.T) {
+		samples, err := lister.List(ctx, id, time.Time{}, base.Add(time.Hour))
+		require.NoError(t, err)
+		require.Len(t, samples, 3)
+		assert.Equal(t, 2.0, *samples[0].Value)
+		assert.Equal(t, 3.0, *samples[1].Value)
+		assert.Equal(t, 4.0, *samples[2].Value)
+	})
+
+	t.Run("time range filter", func(t *testing.T) {
+		samples, err := lister.List(ctx, id, base.Add(3*time.Second), base.Add(3*time.Second))
+		require.NoError(t, err)
+		require.Len(t, samples, 1)
+		assert.Equal(t, 3.0, *samples[0].Value)
+	})
+
+	t.Run("unknown metric", func(t *testing.T) {
+		samples, err := lister.List(ctx, types.MetricID{ID: "missing", MType: types.Gauge}, time.Time{}, base.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, samples)
+	})
+
+	t.Run("zero capacity disables history", func(t *testing.T) {
+		disabled := NewMetricHistoryBuffer(0)
+		require.NoError(t, NewMetricMemoryHistorySaveRepository(disabled).Save(ctx, id, types.MetricSample{Timestamp: base}))
+
+		samples, err := NewMetricMemoryHistoryListRepository(disabled).List(ctx, id, time.Time{}, base.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Empty(t, samples)
+	})
+}
@@ -31,7 +31,32 @@ func (r *MetricMemoryListRepository) List(
 	}
 
 	sort.Slice(metrics, func(i, j int) bool {
-		return metrics[i].ID < metrics[j].ID
+		if metrics[i].ID != metrics[j].ID {
+			return metrics[i].ID < metrics[j].ID
+		}
+		return metrics[i].Labels.String() < metrics[j].Labels.String()
+	})
+
+	return metrics, nil
+}
+
+func (r *MetricMemoryListRepository) ListSeries(
+	ctx context.Context,
+	id string,
+	mtype string,
+) ([]types.Metrics, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	metrics := make([]types.Metrics, 0)
+	for key, metric := range r.data {
+		if key.ID == id && key.MType == mtype {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Labels.String() < metrics[j].Labels.String()
 	})
 
 	return metrics, nil
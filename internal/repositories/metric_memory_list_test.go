@@ -46,3 +46,21 @@ func TestMetricMemoryListRepository_List(t *testing.T) {
 	assert.NotNil(t, metrics[1].Delta)
 	assert.Equal(t, counterValue, *metrics[1].Delta)
 }
+
+func TestMetricMemoryListRepository_ListSeries(t *testing.T) {
+	one, two, other := 1.0, 2.0, 3.0
+
+	data := map[types.MetricID]types.Metrics{
+		{ID: "Alloc", MType: types.Gauge, Labels: "instance=web02"}: {ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web02"}, Value: &two},
+		{ID: "Alloc", MType: types.Gauge, Labels: "instance=web01"}: {ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web01"}, Value: &one},
+		{ID: "Alloc", MType: types.Counter}:                         {ID: "Alloc", MType: types.Counter},
+		{ID: "Sys", MType: types.Gauge}:                             {ID: "Sys", MType: types.Gauge, Value: &other},
+	}
+
+	metrics, err := repositories.NewMetricMemoryListRepository(data).ListSeries(context.Background(), "Alloc", types.Gauge)
+
+	assert.NoError(t, err)
+	assert.Len(t, metrics, 2)
+	assert.Equal(t, 1.0, *metrics[0].Value)
+	assert.Equal(t, 2.0, *metrics[1].Value)
+}
@@ -24,6 +24,6 @@ func (r *MetricMemorySaveRepository) Save(
 ) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	r.data[types.MetricID{ID: metrics.ID, MType: metrics.MType}] = metrics
+	r.data[metrics.MetricID()] = metrics
 	return nil
 }
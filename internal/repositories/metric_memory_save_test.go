@@ -71,6 +71,22 @@ func TestMetricMemorySaveRepository_Save(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "same metric with different labels",
+			metrics: []types.Metrics{
+				{ID: "Alloc", MType: "gauge", Labels: types.Labels{"host": "web01"}, Value: ptrFloat64(1)},
+				{ID: "Alloc", MType: "gauge", Labels: types.Labels{"host": "web02"}, Value: ptrFloat64(2)},
+				{ID: "Alloc", MType: "gauge", Labels: types.Labels{"host": "web01"}, Value: ptrFloat64(3)},
+			},
+			expected: map[types.MetricID]types.Metrics{
+				{ID: "Alloc", MType: "gauge", Labels: "host=web01"}: {
+					ID: "Alloc", MType: "gauge", Labels: types.Labels{"host": "web01"}, Value: ptrFloat64(3),
+				},
+				{ID: "Alloc", MType: "gauge", Labels: "host=web02"}: {
+					ID: "Alloc", MType: "gauge", Labels: types.Labels{"host": "web02"}, Value: ptrFloat64(2),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
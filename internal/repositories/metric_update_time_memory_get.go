@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricUpdateTimeMemoryGetRepository struct {
+	registry *MetricUpdateTimeRegistry
+}
+
+func NewMetricUpdateTimeMemoryGetRepository(
+	registry *MetricUpdateTimeRegistry,
+) *MetricUpdateTimeMemoryGetRepository {
+	return &MetricUpdateTimeMemoryGetRepository{registry: registry}
+}
+
+func (r *MetricUpdateTimeMemoryGetRepository) Get(
+	ctx context.Context,
+	id types.MetricID,
+) (time.Time, error) {
+	return r.registry.get(id), nil
+}
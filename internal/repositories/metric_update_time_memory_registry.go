@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricUpdateTimeRegistry struct {
+	mu        sync.RWMutex
+	updatedAt map[types.MetricID]time.Time
+}
+
+func NewMetricUpdateTimeRegistry() *MetricUpdateTimeRegistry {
+	return &MetricUpdateTimeRegistry{updatedAt: make(map[types.MetricID]time.Time)}
+}
+
+func (r *MetricUpdateTimeRegistry) touch(id types.MetricID, ts time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ts.After(r.updatedAt[id]) {
+		r.updatedAt[id] = ts
+	}
+}
+
+func (r *MetricUpdateTimeRegistry) get(id types.MetricID) time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.updatedAt[id]
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricUpdateTimeMemorySaveRepository struct {
+	registry *MetricUpdateTimeRegistry
+}
+
+func NewMetricUpdateTimeMemorySaveRepository(
+	registry *MetricUpdateTimeRegistry,
+) *MetricUpdateTimeMemorySaveRepository {
+	return &MetricUpdateTimeMemorySaveRepository{registry: registry}
+}
+
+func (r *MetricUpdateTimeMemorySaveRepository) Save(
+	ctx context.Context,
+	id types.MetricID,
+	updatedAt time.Time,
+) error {
+	r.registry.touch(id, updatedAt)
+	return nil
+}
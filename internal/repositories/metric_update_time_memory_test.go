@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricUpdateTimeMemoryRepository_SaveAndGet(t *testing.T) {
+	ctx := context.Background()
+	registry := NewMetricUpdateTimeRegistry()
+
+	saver := NewMetricUpdateTimeMemorySaveRepository(registry)
+	getter := NewMetricUpdateTimeMemoryGetRepository(registry)
+
+	alloc := types.MetricID{ID: "Alloc", MType: types.Gauge}
+	updatedAt, err := getter.Get(ctx, alloc)
+	require.NoError(t, err)
+	assert.True(t, updatedAt.IsZero())
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, saver.Save(ctx, alloc, base.Add(time.Minute)))
+	// более старая отметка не откатывает время последнего обновления
+	require.NoError(t, saver.Save(ctx, alloc, base))
+
+	updatedAt, err = getter.Get(ctx, alloc)
+	require.NoError(t, err)
+	assert.Equal(t, base.Add(time.Minute), updatedAt)
+
+	updatedAt, err = getter.Get(ctx, types.MetricID{ID: "Alloc", MType: types.Counter})
+	require.NoError(t, err)
+	assert.True(t, updatedAt.IsZero())
+}
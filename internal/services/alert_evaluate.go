@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type AlertMetricGetter interface {
+	Get(ctx context.Context, id types.MetricID) (*types.Metrics, error)
+}
+
+type AlertUpdateTimeGetter interface {
+	Get(ctx context.Context, id types.MetricID) (time.Time, error)
+}
+
+type alertSample struct {
+	value float64
+	at    time.Time
+}
+
+type AlertEvaluateService struct {
+	getter      AlertMetricGetter
+	updateTimes AlertUpdateTimeGetter
+	rules       []types.AlertRule
+	now         func() time.Time
+	evalMu      sync.Mutex
+	mu          sync.RWMutex
+	alerts      map[string]types.Alert
+	samples     map[string]alertSample
+}
+
+func NewAlertEvaluateService(
+	getter AlertMetricGetter,
+	updateTimes AlertUpdateTimeGetter,
+	rules []types.AlertRule,
+) *AlertEvaluateService {
+	return &AlertEvaluateService{
+		getter:      getter,
+		updateTimes: updateTimes,
+		rules:       rules,
+		now:         time.Now,
+		alerts:      make(map[string]types.Alert),
+		samples:     make(map[string]alertSample),
+	}
+}
+
+func (svc *AlertEvaluateService) Evaluate(ctx context.Context) error {
+	svc.evalMu.Lock()
+	defer svc.evalMu.Unlock()
+
+	now := svc.now()
+
+	var failed bool
+	metrics := make([]*types.Metrics, len(svc.rules))
+	updatedAt := make([]time.Time, len(svc.rules))
+	fetched := make([]bool, len(svc.rules))
+	for i, rule := range svc.rules {
+		metric, err := svc.getter.Get(ctx, rule.Metric)
+		if err != nil {
+			logger.Log.Errorw("Failed to get metric for alert rule", "rule", rule.Name, "id", rule.Metric.ID, "type", rule.Metric.MType, "error", err)
+			failed = true
+			continue
+		}
+		if rule.Kind == types.AlertAbsence {
+			updatedAt[i], err = svc.updateTimes.Get(ctx, updateTimeID(rule.Metric))
+			if err != nil {
+				logger.Log.Errorw("Failed to get metric update time for alert rule", "rule", rule.Name, "id", rule.Metric.ID, "type", rule.Metric.MType, "error", err)
+				failed = true
+				continue
+			}
+		}
+		metrics[i] = metric
+		fetched[i] = true
+	}
+
+	svc.mu.Lock()
+	for i, rule := range svc.rules {
+		if !fetched[i] {
+			continue
+		}
+		active, value := svc.check(rule, metrics[i], updatedAt[i], now)
+		svc.transition(rule, active, value, now)
+	}
+	svc.mu.Unlock()
+
+	if failed {
+		return types.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (svc *AlertEvaluateService) List(ctx context.Context) ([]types.Alert, error) {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+
+	alerts := make([]types.Alert, 0, len(svc.alerts))
+	for _, alert := range svc.alerts {
+		alerts = append(alerts, alert)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Rule.Name < alerts[j].Rule.Name
+	})
+
+	return alerts, nil
+}
+
+func (svc *AlertEvaluateService) check(
+	rule types.AlertRule,
+	metric *types.Metrics,
+	updatedAt time.Time,
+	now time.Time,
+) (bool, *float64) {
+	var (
+		value   float64
+		present bool
+	)
+	if metric != nil {
+		if v, err := types.GetMetricFloatValue(*metric); err == nil {
+			value, present = v, true
+		}
+	}
+
+	prev, hasPrev := svc.samples[rule.Name]
+	if present {
+		svc.samples[rule.Name] = alertSample{value: value, at: now}
+	} else {
+		delete(svc.samples, rule.Name)
+	}
+
+	switch rule.Kind {
+	case types.AlertThreshold:
+		if !present {
+			return false, nil
+		}
+		active, _ := types.CompareAlertThreshold(rule.Operator, value, rule.Threshold)
+		return active, &value
+
+	case types.AlertRate:
+		if !present || !hasPrev {
+			return false, nil
+		}
+		elapsed := now.Sub(prev.at).Seconds()
+		if elapsed <= 0 {
+			return false, nil
+		}
+		rate := (value - prev.value) / elapsed
+		active, _ := types.CompareAlertThreshold(rule.Operator, rate, rule.Threshold)
+		return active, &rate
+
+	case types.AlertAbsence:
+		if !present {
+			return true, nil
+		}
+		return hasPrev && !updatedAt.After(prev.at), &value
+	}
+
+	return false, nil
+}
+
+func updateTimeID(id types.MetricID) types.MetricID {
+	if labels, err := types.ParseLabels(id.Labels); err == nil {
+		id.Labels = labels.String()
+	}
+	return id
+}
+
+func (svc *AlertEvaluateService) transition(
+	rule types.AlertRule,
+	active bool,
+	value *float64,
+	now time.Time,
+) {
+	alert, exists := svc.alerts[rule.Name]
+
+	if active {
+		if !exists || alert.State == types.AlertResolved {
+			alert = types.Alert{Rule: rule, State: types.AlertPending, ActiveAt: now}
+		}
+		alert.Value = value
+
+		if alert.State == types.AlertPending && now.Sub(alert.ActiveAt) >= time.Duration(rule.For)*time.Second {
+			firedAt := now
+			alert.State = types.AlertFiring
+			alert.FiredAt = &firedAt
+			logger.Log.Warnw("Alert firing", "rule", rule.Name, "id", rule.Metric.ID, "type", rule.Metric.MType)
+		}
+
+		svc.alerts[rule.Name] = alert
+		return
+	}
+
+	if !exists {
+		return
+	}
+
+	switch alert.State {
+	case types.AlertPending:
+		delete(svc.alerts, rule.Name)
+	case types.AlertFiring:
+		endsAt := now
+		alert.State = types.AlertResolved
+		alert.Value = value
+		alert.EndsAt = &endsAt
+		svc.alerts[rule.Name] = alert
+		logger.Log.Infow("Alert resolved", "rule", rule.Name, "id", rule.Metric.ID, "type", rule.Metric.MType)
+	}
+}
@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/services/alert_evaluate.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockAlertMetricGetter is a mock of AlertMetricGetter interface.
+type MockAlertMetricGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertMetricGetterMockRecorder
+}
+
+// MockAlertMetricGetterMockRecorder is the mock recorder for MockAlertMetricGetter.
+type MockAlertMetricGetterMockRecorder struct {
+	mock *MockAlertMetricGetter
+}
+
+// NewMockAlertMetricGetter creates a new mock instance.
+func NewMockAlertMetricGetter(ctrl *gomock.Controller) *MockAlertMetricGetter {
+	mock := &MockAlertMetricGetter{ctrl: ctrl}
+	mock.recorder = &MockAlertMetricGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertMetricGetter) EXPECT() *MockAlertMetricGetterMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAlertMetricGetter) Get(ctx context.Context, id types.MetricID) (*types.Metrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*types.Metrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAlertMetricGetterMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAlertMetricGetter)(nil).Get), ctx, id)
+}
+
+// MockAlertUpdateTimeGetter is a mock of AlertUpdateTimeGetter interface.
+type MockAlertUpdateTimeGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertUpdateTimeGetterMockRecorder
+}
+
+// MockAlertUpdateTimeGetterMockRecorder is the mock recorder for MockAlertUpdateTimeGetter.
+type MockAlertUpdateTimeGetterMockRecorder struct {
+	mock *MockAlertUpdateTimeGetter
+}
+
+// NewMockAlertUpdateTimeGetter creates a new mock instance.
+func NewMockAlertUpdateTimeGetter(ctrl *gomock.Controller) *MockAlertUpdateTimeGetter {
+	mock := &MockAlertUpdateTimeGetter{ctrl: ctrl}
+	mock.recorder = &MockAlertUpdateTimeGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertUpdateTimeGetter) EXPECT() *MockAlertUpdateTimeGetterMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockAlertUpdateTimeGetter) Get(ctx context.Context, id types.MetricID) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockAlertUpdateTimeGetterMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockAlertUpdateTimeGetter)(nil).Get), ctx, id)
+}
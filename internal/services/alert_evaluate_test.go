@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertEvaluateService_Threshold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "HeapAlloc", MType: types.Gauge}
+	rule := types.AlertRule{Name: "high_heap", Metric: id, Kind: types.AlertThreshold, Operator: ">", Threshold: 100, For: 10}
+
+	mockGetter := NewMockAlertMetricGetter(ctrl)
+	svc := NewAlertEvaluateService(mockGetter, NewMockAlertUpdateTimeGetter(ctrl), []types.AlertRule{rule})
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	svc.now = func() time.Time { return now }
+
+	gauge := func(v float64) *types.Metrics {
+		return &types.Metrics{ID: id.ID, MType: id.MType, Value: &v}
+	}
+	ctx := context.Background()
+
+	mockGetter.EXPECT().Get(ctx, id).Return(gauge(150), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ := svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertPending, alerts[0].State)
+	assert.Equal(t, 150.0, *alerts[0].Value)
+
+	now = start.Add(10 * time.Second)
+	mockGetter.EXPECT().Get(ctx, id).Return(gauge(200), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertFiring, alerts[0].State)
+	assert.Equal(t, start, alerts[0].ActiveAt)
+	assert.Equal(t, now, *alerts[0].FiredAt)
+
+	now = start.Add(20 * time.Second)
+	mockGetter.EXPECT().Get(ctx, id).Return(gauge(50), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertResolved, alerts[0].State)
+	assert.Equal(t, now, *alerts[0].EndsAt)
+}
+
+func TestAlertEvaluateService_PendingDroppedWhenInactive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "PollCount", MType: types.Counter}
+	rule := types.AlertRule{Name: "many_polls", Metric: id, Kind: types.AlertThreshold, Operator: ">=", Threshold: 10, For: 60}
+
+	mockGetter := NewMockAlertMetricGetter(ctrl)
+	svc := NewAlertEvaluateService(mockGetter, NewMockAlertUpdateTimeGetter(ctrl), []types.AlertRule{rule})
+	ctx := context.Background()
+
+	counter := func(v int64) *types.Metrics {
+		return &types.Metrics{ID: id.ID, MType: id.MType, Delta: &v}
+	}
+
+	mockGetter.EXPECT().Get(ctx, id).Return(counter(10), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ := svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertPending, alerts[0].State)
+
+	mockGetter.EXPECT().Get(ctx, id).Return(nil, nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	assert.Empty(t, alerts)
+}
+
+func TestAlertEvaluateService_Rate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "PollCount", MType: types.Counter}
+	rule := types.AlertRule{Name: "fast_polls", Metric: id, Kind: types.AlertRate, Operator: ">", Threshold: 1}
+
+	mockGetter := NewMockAlertMetricGetter(ctrl)
+	svc := NewAlertEvaluateService(mockGetter, NewMockAlertUpdateTimeGetter(ctrl), []types.AlertRule{rule})
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	counter := func(v int64) *types.Metrics {
+		return &types.Metrics{ID: id.ID, MType: id.MType, Delta: &v}
+	}
+
+	mockGetter.EXPECT().Get(ctx, id).Return(counter(0), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ := svc.List(ctx)
+	assert.Empty(t, alerts)
+
+	now = start.Add(10 * time.Second)
+	mockGetter.EXPECT().Get(ctx, id).Return(counter(50), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertFiring, alerts[0].State)
+	assert.Equal(t, 5.0, *alerts[0].Value)
+
+	now = start.Add(20 * time.Second)
+	mockGetter.EXPECT().Get(ctx, id).Return(counter(55), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertResolved, alerts[0].State)
+}
+
+func TestAlertEvaluateService_Absence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "Alloc", MType: types.Gauge}
+	rule := types.AlertRule{Name: "no_alloc", Metric: id, Kind: types.AlertAbsence, For: 30}
+
+	mockGetter := NewMockAlertMetricGetter(ctrl)
+	mockUpdateTimes := NewMockAlertUpdateTimeGetter(ctrl)
+	svc := NewAlertEvaluateService(mockGetter, mockUpdateTimes, []types.AlertRule{rule})
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	mockGetter.EXPECT().Get(ctx, id).Return(nil, nil)
+	mockUpdateTimes.EXPECT().Get(ctx, id).Return(time.Time{}, nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ := svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertPending, alerts[0].State)
+	assert.Nil(t, alerts[0].Value)
+
+	now = start.Add(30 * time.Second)
+	mockGetter.EXPECT().Get(ctx, id).Return(nil, nil)
+	mockUpdateTimes.EXPECT().Get(ctx, id).Return(time.Time{}, nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertFiring, alerts[0].State)
+
+	v := 1.0
+	now = start.Add(40 * time.Second)
+	mockGetter.EXPECT().Get(ctx, id).Return(&types.Metrics{ID: id.ID, MType: id.MType, Value: &v}, nil)
+	mockUpdateTimes.EXPECT().Get(ctx, id).Return(start.Add(35*time.Second), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertResolved, alerts[0].State)
+
+	// постоянное значение, которое продолжает обновляться, не считается отсутствием
+	now = start.Add(50 * time.Second)
+	mockGetter.EXPECT().Get(ctx, id).Return(&types.Metrics{ID: id.ID, MType: id.MType, Value: &v}, nil)
+	mockUpdateTimes.EXPECT().Get(ctx, id).Return(start.Add(45*time.Second), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertResolved, alerts[0].State)
+
+	// обновлений с прошлой проверки не было
+	now = start.Add(60 * time.Second)
+	mockGetter.EXPECT().Get(ctx, id).Return(&types.Metrics{ID: id.ID, MType: id.MType, Value: &v}, nil)
+	mockUpdateTimes.EXPECT().Get(ctx, id).Return(start.Add(45*time.Second), nil)
+	require.NoError(t, svc.Evaluate(ctx))
+	alerts, _ = svc.List(ctx)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertPending, alerts[0].State)
+}
+
+func TestAlertEvaluateService_AbsenceLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "region=eu,host=web01"}
+	rule := types.AlertRule{Name: "no_alloc", Metric: id, Kind: types.AlertAbsence, For: 30}
+
+	mockGetter := NewMockAlertMetricGetter(ctrl)
+	mockUpdateTimes := NewMockAlertUpdateTimeGetter(ctrl)
+	svc := NewAlertEvaluateService(mockGetter, mockUpdateTimes, []types.AlertRule{rule})
+	ctx := context.Background()
+
+	// время обновления ищется по каноническому виду меток
+	mockGetter.EXPECT().Get(ctx, id).Return(nil, nil)
+	mockUpdateTimes.EXPECT().Get(ctx, types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "host=web01,region=eu"}).Return(time.Time{}, errors.New("fail"))
+
+	assert.Equal(t, types.ErrInternalServerError, svc.Evaluate(ctx))
+}
+
+func TestAlertEvaluateService_GetterError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "Alloc", MType: types.Gauge}
+	rule := types.AlertRule{Name: "high_alloc", Metric: id, Kind: types.AlertThreshold, Operator: ">", Threshold: 1}
+
+	mockGetter := NewMockAlertMetricGetter(ctrl)
+	svc := NewAlertEvaluateService(mockGetter, NewMockAlertUpdateTimeGetter(ctrl), []types.AlertRule{rule})
+	ctx := context.Background()
+
+	mockGetter.EXPECT().Get(ctx, id).Return(nil, errors.New("db error"))
+
+	err := svc.Evaluate(ctx)
+	assert.Equal(t, types.ErrInternalServerError, err)
+
+	alerts, err := svc.List(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestAlertEvaluateService_ListNotBlockedBySlowGetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "Alloc", MType: types.Gauge}
+	rule := types.AlertRule{Name: "high_alloc", Metric: id, Kind: types.AlertThreshold, Operator: ">", Threshold: 1}
+
+	mockGetter := NewMockAlertMetricGetter(ctrl)
+	svc := NewAlertEvaluateService(mockGetter, NewMockAlertUpdateTimeGetter(ctrl), []types.AlertRule{rule})
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	v := 5.0
+	mockGetter.EXPECT().Get(ctx, id).DoAndReturn(func(ctx context.Context, id types.MetricID) (*types.Metrics, error) {
+		close(started)
+		<-release
+		return &types.Metrics{ID: id.ID, MType: id.MType, Value: &v}, nil
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- svc.Evaluate(ctx) }()
+	<-started
+
+	// пока метрика загружается, список алертов доступен
+	listed := make(chan struct{})
+	go func() {
+		alerts, err := svc.List(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, alerts)
+		close(listed)
+	}()
+
+	select {
+	case <-listed:
+	case <-time.After(time.Second):
+		t.Fatal("List blocked by Evaluate")
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+
+	alerts, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, types.AlertFiring, alerts[0].State)
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type InstanceLister interface {
+	List(ctx context.Context) ([]types.Instance, error)
+}
+
+type InstanceListService struct {
+	lister InstanceLister
+}
+
+func NewInstanceListService(
+	lister InstanceLister,
+) *InstanceListService {
+	return &InstanceListService{lister: lister}
+}
+
+func (svc *InstanceListService) List(
+	ctx context.Context,
+) ([]types.Instance, error) {
+	return svc.lister.List(ctx)
+}
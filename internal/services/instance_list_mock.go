@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/services/instance_list.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockInstanceLister is a mock of InstanceLister interface.
+type MockInstanceLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockInstanceListerMockRecorder
+}
+
+// MockInstanceListerMockRecorder is the mock recorder for MockInstanceLister.
+type MockInstanceListerMockRecorder struct {
+	mock *MockInstanceLister
+}
+
+// NewMockInstanceLister creates a new mock instance.
+func NewMockInstanceLister(ctrl *gomock.Controller) *MockInstanceLister {
+	mock := &MockInstanceLister{ctrl: ctrl}
+	mock.recorder = &MockInstanceListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInstanceLister) EXPECT() *MockInstanceListerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockInstanceLister) List(ctx context.Context) ([]types.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]types.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockInstanceListerMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInstanceLister)(nil).List), ctx)
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceListService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := NewMockInstanceLister(ctrl)
+	svc := NewInstanceListService(mockLister)
+
+	ctx := context.Background()
+	expected := []types.Instance{{ID: "web01", LastSeen: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}}
+
+	mockLister.EXPECT().List(ctx).Return(expected, nil)
+
+	result, err := svc.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	mockLister.EXPECT().List(ctx).Return(nil, errors.New("list error"))
+
+	result, err = svc.List(ctx)
+	assert.EqualError(t, err, "list error")
+	assert.Nil(t, result)
+}
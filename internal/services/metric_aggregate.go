@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricAggregateGetter interface {
+	Get(ctx context.Context, id types.MetricID) (*types.Metrics, error)
+}
+
+type MetricAggregateLister interface {
+	ListSeries(ctx context.Context, id string, mtype string) ([]types.Metrics, error)
+}
+
+type MetricAggregateService struct {
+	getter    MetricAggregateGetter
+	lister    MetricAggregateLister
+	setWindow time.Duration
+}
+
+func NewMetricAggregateService(
+	getter MetricAggregateGetter,
+	lister MetricAggregateLister,
+	setWindow time.Duration,
+) *MetricAggregateService {
+	return &MetricAggregateService{getter: getter, lister: lister, setWindow: setWindow}
+}
+
+func (svc *MetricAggregateService) Get(
+	ctx context.Context,
+	id types.MetricID,
+) (*types.Metrics, error) {
+	labels, err := types.ParseLabels(id.Labels)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := labels[types.InstanceLabel]; ok {
+		return svc.getter.Get(ctx, id)
+	}
+
+	metrics, err := svc.lister.ListSeries(ctx, id.ID, id.MType)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		aggregated *types.Metrics
+		now        = time.Now()
+		want       = labels.String()
+	)
+	for _, metric := range metrics {
+		metric.Labels = metric.Labels.Without(types.InstanceLabel)
+		if metric.Labels.String() != want {
+			continue
+		}
+		resetExpiredSet(&metric, svc.setWindow, now)
+
+		if aggregated == nil {
+			aggregated = &metric
+			continue
+		}
+		merged, err := types.MergeMetrics(*aggregated, metric)
+		if err != nil {
+			return nil, err
+		}
+		aggregated = &merged
+	}
+
+	return aggregated, nil
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/services/metric_aggregate.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockMetricAggregateGetter is a mock of MetricAggregateGetter interface.
+type MockMetricAggregateGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricAggregateGetterMockRecorder
+}
+
+// MockMetricAggregateGetterMockRecorder is the mock recorder for MockMetricAggregateGetter.
+type MockMetricAggregateGetterMockRecorder struct {
+	mock *MockMetricAggregateGetter
+}
+
+// NewMockMetricAggregateGetter creates a new mock instance.
+func NewMockMetricAggregateGetter(ctrl *gomock.Controller) *MockMetricAggregateGetter {
+	mock := &MockMetricAggregateGetter{ctrl: ctrl}
+	mock.recorder = &MockMetricAggregateGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricAggregateGetter) EXPECT() *MockMetricAggregateGetterMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockMetricAggregateGetter) Get(ctx context.Context, id types.MetricID) (*types.Metrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*types.Metrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockMetricAggregateGetterMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMetricAggregateGetter)(nil).Get), ctx, id)
+}
+
+// MockMetricAggregateLister is a mock of MetricAggregateLister interface.
+type MockMetricAggregateLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricAggregateListerMockRecorder
+}
+
+// MockMetricAggregateListerMockRecorder is the mock recorder for MockMetricAggregateLister.
+type MockMetricAggregateListerMockRecorder struct {
+	mock *MockMetricAggregateLister
+}
+
+// NewMockMetricAggregateLister creates a new mock instance.
+func NewMockMetricAggregateLister(ctrl *gomock.Controller) *MockMetricAggregateLister {
+	mock := &MockMetricAggregateLister{ctrl: ctrl}
+	mock.recorder = &MockMetricAggregateListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricAggregateLister) EXPECT() *MockMetricAggregateListerMockRecorder {
+	return m.recorder
+}
+
+// ListSeries mocks base method.
+func (m *MockMetricAggregateLister) ListSeries(ctx context.Context, id, mtype string) ([]types.Metrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSeries", ctx, id, mtype)
+	ret0, _ := ret[0].([]types.Metrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSeries indicates an expected call of ListSeries.
+func (mr *MockMetricAggregateListerMockRecorder) ListSeries(ctx, id, mtype interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSeries", reflect.TypeOf((*MockMetricAggregateLister)(nil).ListSeries), ctx, id, mtype)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricAggregateService_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := NewMockMetricAggregateGetter(ctrl)
+	mockLister := NewMockMetricAggregateLister(ctrl)
+	svc := NewMetricAggregateService(mockGetter, mockLister, 0)
+
+	ctx := context.Background()
+	value := func(v float64) *float64 { return &v }
+	delta := func(v int64) *int64 { return &v }
+
+	metrics := []types.Metrics{
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web01"}, Value: value(1.5)},
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web02"}, Value: value(2)},
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"instance": "web03", "region": "eu"}, Value: value(4)},
+		{ID: "PollCount", MType: types.Counter, Labels: types.Labels{"instance": "web01"}, Delta: delta(3)},
+		{ID: "PollCount", MType: types.Counter, Delta: delta(2)},
+	}
+	series := func(id string, mtype string) []types.Metrics {
+		var result []types.Metrics
+		for _, m := range metrics {
+			if m.ID == id && m.MType == mtype {
+				result = append(result, m)
+			}
+		}
+		return result
+	}
+
+	t.Run("aggregates over instance", func(t *testing.T) {
+		mockLister.EXPECT().ListSeries(ctx, "Alloc", types.Gauge).Return(series("Alloc", types.Gauge), nil)
+
+		// для gauge берётся максимум по экземплярам
+		metric, err := svc.Get(ctx, types.MetricID{ID: "Alloc", MType: types.Gauge})
+		require.NoError(t, err)
+		require.NotNil(t, metric)
+		assert.Equal(t, 2.0, *metric.Value)
+		assert.Nil(t, metric.Labels)
+	})
+
+	t.Run("aggregates with other labels", func(t *testing.T) {
+		mockLister.EXPECT().ListSeries(ctx, "Alloc", types.Gauge).Return(series("Alloc", types.Gauge), nil)
+
+		metric, err := svc.Get(ctx, types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "region=eu"})
+		require.NoError(t, err)
+		require.NotNil(t, metric)
+		assert.Equal(t, 4.0, *metric.Value)
+		assert.Equal(t, types.Labels{"region": "eu"}, metric.Labels)
+	})
+
+	t.Run("includes series without instance", func(t *testing.T) {
+		mockLister.EXPECT().ListSeries(ctx, "PollCount", types.Counter).Return(series("PollCount", types.Counter), nil)
+
+		metric, err := svc.Get(ctx, types.MetricID{ID: "PollCount", MType: types.Counter})
+		require.NoError(t, err)
+		require.NotNil(t, metric)
+		assert.Equal(t, int64(5), *metric.Delta)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockLister.EXPECT().ListSeries(ctx, "Missing", types.Gauge).Return(nil, nil)
+
+		metric, err := svc.Get(ctx, types.MetricID{ID: "Missing", MType: types.Gauge})
+		require.NoError(t, err)
+		assert.Nil(t, metric)
+	})
+
+	t.Run("instance lookup is exact", func(t *testing.T) {
+		id := types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "instance=web01"}
+		mockGetter.EXPECT().Get(ctx, id).Return(&metrics[0], nil)
+
+		metric, err := svc.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, &metrics[0], metric)
+	})
+
+	t.Run("lister error", func(t *testing.T) {
+		mockLister.EXPECT().ListSeries(ctx, "Alloc", types.Gauge).Return(nil, errors.New("fail"))
+
+		_, err := svc.Get(ctx, types.MetricID{ID: "Alloc", MType: types.Gauge})
+		assert.Error(t, err)
+	})
+
+	// исходные метрики не меняются при агрегации
+	assert.Equal(t, 1.5, *metrics[0].Value)
+	assert.Equal(t, types.Labels{"instance": "web01"}, metrics[0].Labels)
+}
+
+func TestMetricAggregateService_Get_ExpiredSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := NewMockMetricAggregateLister(ctrl)
+	svc := NewMetricAggregateService(NewMockMetricAggregateGetter(ctrl), mockLister, time.Hour)
+
+	stale := types.NewSetValue([]string{"alice", "bob"}, time.Now().Add(-2*time.Hour))
+	fresh := types.NewSetValue([]string{"carol"}, time.Now())
+	mockLister.EXPECT().ListSeries(gomock.Any(), "users", types.Set).Return([]types.Metrics{
+		{ID: "users", MType: types.Set, Labels: types.Labels{"instance": "web01"}, Set: &stale},
+		{ID: "users", MType: types.Set, Labels: types.Labels{"instance": "web02"}, Set: &fresh},
+	}, nil)
+
+	// множество из прошедшего окна не попадает в агрегат
+	metric, err := svc.Get(context.Background(), types.MetricID{ID: "users", MType: types.Set})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), metric.Set.Cardinality())
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/sbilibin2017/yp-metrics/internal/logger"
 	"github.com/sbilibin2017/yp-metrics/internal/types"
@@ -12,13 +13,15 @@ type MetricGetter interface {
 }
 
 type MetricGetService struct {
-	getter MetricGetter
+	getter    MetricGetter
+	setWindow time.Duration
 }
 
 func NewMetricGetService(
 	getter MetricGetter,
+	setWindow time.Duration,
 ) *MetricGetService {
-	return &MetricGetService{getter: getter}
+	return &MetricGetService{getter: getter, setWindow: setWindow}
 }
 
 func (svc *MetricGetService) Get(
@@ -33,5 +36,6 @@ func (svc *MetricGetService) Get(
 	if metric == nil {
 		return nil, types.ErrMetricNotFound
 	}
+	resetExpiredSet(metric, svc.setWindow, time.Now())
 	return metric, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -16,7 +17,7 @@ func TestMetricGetService_Get_TableDriven(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockGetter := NewMockMetricGetter(ctrl)
-	svc := NewMetricGetService(mockGetter)
+	svc := NewMetricGetService(mockGetter, 0)
 
 	ctx := context.Background()
 
@@ -76,3 +77,21 @@ func TestMetricGetService_Get_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricGetService_Get_ExpiredSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockGetter := NewMockMetricGetter(ctrl)
+	svc := NewMetricGetService(mockGetter, time.Hour)
+
+	id := types.MetricID{ID: "users", MType: types.Set}
+	stale := types.NewSetValue([]string{"alice", "bob"}, time.Now().Add(-2*time.Hour))
+	mockGetter.EXPECT().Get(gomock.Any(), id).Return(&types.Metrics{ID: "users", MType: types.Set, Set: &stale}, nil)
+
+	// множество из прошедшего окна читается как пустое
+	metric, err := svc.Get(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), metric.Set.Cardinality())
+	assert.Equal(t, uint64(2), stale.Cardinality())
+}
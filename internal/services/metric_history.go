@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+type MetricHistoryLister interface {
+	List(ctx context.Context, id types.MetricID, from time.Time, to time.Time) ([]types.MetricSample, error)
+}
+
+type MetricHistorySeriesLister interface {
+	ListSeries(ctx context.Context, id string, mtype string) ([]types.Metrics, error)
+}
+
+type MetricHistoryService struct {
+	lister       MetricHistoryLister
+	seriesLister MetricHistorySeriesLister
+}
+
+func NewMetricHistoryService(
+	lister MetricHistoryLister,
+	seriesLister MetricHistorySeriesLister,
+) *MetricHistoryService {
+	return &MetricHistoryService{lister: lister, seriesLister: seriesLister}
+}
+
+func (svc *MetricHistoryService) History(
+	ctx context.Context,
+	id types.MetricID,
+	from time.Time,
+	to time.Time,
+) (*types.MetricHistory, error) {
+	labels, err := types.ParseLabels(id.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []types.MetricID{id}
+	if _, ok := labels[types.InstanceLabel]; !ok {
+		ids, err = svc.seriesIDs(ctx, id, labels)
+		if err != nil {
+			logger.Log.Errorw("Failed to list metric series", "id", id.ID, "type", id.MType, "error", err)
+			return nil, types.ErrInternalServerError
+		}
+	}
+
+	var samples []types.MetricSample
+	for _, seriesID := range ids {
+		seriesSamples, err := svc.lister.List(ctx, seriesID, from, to)
+		if err != nil {
+			logger.Log.Errorw("Failed to list metric history", "id", id.ID, "type", id.MType, "error", err)
+			return nil, types.ErrInternalServerError
+		}
+		samples = append(samples, seriesSamples...)
+	}
+	if len(ids) > 1 {
+		sort.SliceStable(samples, func(i, j int) bool {
+			return samples[i].Timestamp.Before(samples[j].Timestamp)
+		})
+	}
+
+	return &types.MetricHistory{
+		ID:      id.ID,
+		MType:   id.MType,
+		Labels:  labels,
+		Samples: samples,
+	}, nil
+}
+
+func (svc *MetricHistoryService) seriesIDs(
+	ctx context.Context,
+	id types.MetricID,
+	labels types.Labels,
+) ([]types.MetricID, error) {
+	series, err := svc.seriesLister.ListSeries(ctx, id.ID, id.MType)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ids  []types.MetricID
+		want = labels.String()
+	)
+	for _, metric := range series {
+		if metric.Labels.Without(types.InstanceLabel).String() != want {
+			continue
+		}
+		ids = append(ids, metric.MetricID())
+	}
+	if len(ids) == 0 {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
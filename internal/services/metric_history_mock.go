@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: /home/sergey/Go/yp-metrics/internal/services/metric_history.go
+
+// Package services is a generated GoMock package.
+package services
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	types "github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+// MockMetricHistoryLister is a mock of MetricHistoryLister interface.
+type MockMetricHistoryLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricHistoryListerMockRecorder
+}
+
+// MockMetricHistoryListerMockRecorder is the mock recorder for MockMetricHistoryLister.
+type MockMetricHistoryListerMockRecorder struct {
+	mock *MockMetricHistoryLister
+}
+
+// NewMockMetricHistoryLister creates a new mock instance.
+func NewMockMetricHistoryLister(ctrl *gomock.Controller) *MockMetricHistoryLister {
+	mock := &MockMetricHistoryLister{ctrl: ctrl}
+	mock.recorder = &MockMetricHistoryListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricHistoryLister) EXPECT() *MockMetricHistoryListerMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockMetricHistoryLister) List(ctx context.Context, id types.MetricID, from, to time.Time) ([]types.MetricSample, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, id, from, to)
+	ret0, _ := ret[0].([]types.MetricSample)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockMetricHistoryListerMockRecorder) List(ctx, id, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockMetricHistoryLister)(nil).List), ctx, id, from, to)
+}
+
+// MockMetricHistorySeriesLister is a mock of MetricHistorySeriesLister interface.
+type MockMetricHistorySeriesLister struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricHistorySeriesListerMockRecorder
+}
+
+// MockMetricHistorySeriesListerMockRecorder is the mock recorder for MockMetricHistorySeriesLister.
+type MockMetricHistorySeriesListerMockRecorder struct {
+	mock *MockMetricHistorySeriesLister
+}
+
+// NewMockMetricHistorySeriesLister creates a new mock instance.
+func NewMockMetricHistorySeriesLister(ctrl *gomock.Controller) *MockMetricHistorySeriesLister {
+	mock := &MockMetricHistorySeriesLister{ctrl: ctrl}
+	mock.recorder = &MockMetricHistorySeriesListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricHistorySeriesLister) EXPECT() *MockMetricHistorySeriesListerMockRecorder {
+	return m.recorder
+}
+
+// ListSeries mocks base method.
+func (m *MockMetricHistorySeriesLister) ListSeries(ctx context.Context, id, mtype string) ([]types.Metrics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSeries", ctx, id, mtype)
+	ret0, _ := ret[0].([]types.Metrics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSeries indicates an expected call of ListSeries.
+func (mr *MockMetricHistorySeriesListerMockRecorder) ListSeries(ctx, id, mtype interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSeries", reflect.TypeOf((*MockMetricHistorySeriesLister)(nil).ListSeries), ctx, id, mtype)
+}
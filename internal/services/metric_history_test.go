@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricHistoryService_History(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := NewMockMetricHistoryLister(ctrl)
+	mockSeriesLister := NewMockMetricHistorySeriesLister(ctrl)
+	svc := NewMetricHistoryService(mockLister, mockSeriesLister)
+	ctx := context.Background()
+
+	id := types.MetricID{ID: "HeapAlloc", MType: types.Gauge}
+	from := time.Unix(0, 0)
+	to := time.Unix(1000, 0)
+	sample := func(ts int64, v float64) types.MetricSample {
+		return types.MetricSample{Timestamp: time.Unix(ts, 0), Value: &v}
+	}
+	samples := []types.MetricSample{sample(10, 1)}
+
+	t.Run("success", func(t *testing.T) {
+		mockSeriesLister.EXPECT().ListSeries(ctx, id.ID, id.MType).Return(nil, nil)
+		mockLister.EXPECT().List(ctx, id, from, to).Return(samples, nil)
+
+		history, err := svc.History(ctx, id, from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.MetricHistory{ID: id.ID, MType: id.MType, Samples: samples}, history)
+	})
+
+	t.Run("instance series merged", func(t *testing.T) {
+		web01 := types.MetricID{ID: id.ID, MType: id.MType, Labels: "instance=web01"}
+		web02 := types.MetricID{ID: id.ID, MType: id.MType, Labels: "instance=web02"}
+		mockSeriesLister.EXPECT().ListSeries(ctx, id.ID, id.MType).Return([]types.Metrics{
+			{ID: id.ID, MType: id.MType, Labels: types.Labels{"instance": "web01"}},
+			{ID: id.ID, MType: id.MType, Labels: types.Labels{"instance": "web02"}},
+			{ID: id.ID, MType: id.MType, Labels: types.Labels{"instance": "web03", "env": "prod"}},
+		}, nil)
+		mockLister.EXPECT().List(ctx, web01, from, to).Return([]types.MetricSample{sample(10, 1), sample(30, 3)}, nil)
+		mockLister.EXPECT().List(ctx, web02, from, to).Return([]types.MetricSample{sample(20, 2)}, nil)
+
+		history, err := svc.History(ctx, id, from, to)
+		assert.NoError(t, err)
+		// отсчёты всех инстансов упорядочены по времени
+		assert.Equal(t, []types.MetricSample{sample(10, 1), sample(20, 2), sample(30, 3)}, history.Samples)
+	})
+
+	t.Run("instance label", func(t *testing.T) {
+		web01 := types.MetricID{ID: id.ID, MType: id.MType, Labels: "instance=web01"}
+		mockLister.EXPECT().List(ctx, web01, from, to).Return(samples, nil)
+
+		history, err := svc.History(ctx, web01, from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, samples, history.Samples)
+	})
+
+	t.Run("series lister error", func(t *testing.T) {
+		mockSeriesLister.EXPECT().ListSeries(ctx, id.ID, id.MType).Return(nil, errors.New("db error"))
+
+		history, err := svc.History(ctx, id, from, to)
+		assert.Equal(t, types.ErrInternalServerError, err)
+		assert.Nil(t, history)
+	})
+
+	t.Run("lister error", func(t *testing.T) {
+		mockSeriesLister.EXPECT().ListSeries(ctx, id.ID, id.MType).Return(nil, nil)
+		mockLister.EXPECT().List(ctx, id, from, to).Return(nil, errors.New("db error"))
+
+		history, err := svc.History(ctx, id, from, to)
+		assert.Equal(t, types.ErrInternalServerError, err)
+		assert.Nil(t, history)
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 )
@@ -11,17 +12,35 @@ type MetricLister interface {
 }
 
 type MetricListService struct {
-	lister MetricLister
+	lister    MetricLister
+	setWindow time.Duration
 }
 
 func NewMetricListService(
 	lister MetricLister,
+	setWindow time.Duration,
 ) *MetricListService {
-	return &MetricListService{lister: lister}
+	return &MetricListService{lister: lister, setWindow: setWindow}
 }
 
 func (svc *MetricListService) List(
 	ctx context.Context,
 ) ([]types.Metrics, error) {
-	return svc.lister.List(ctx)
+	metrics, err := svc.lister.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range metrics {
+		resetExpiredSet(&metrics[i], svc.setWindow, now)
+	}
+	return metrics, nil
+}
+
+func resetExpiredSet(metric *types.Metrics, window time.Duration, now time.Time) {
+	if metric.Set != nil && metric.Set.Expired(window, now) {
+		set := types.NewSetValue(nil, now.Truncate(window))
+		metric.Set = &set
+	}
 }
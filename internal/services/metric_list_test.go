@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
@@ -16,7 +17,7 @@ func TestMetricListService_List(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockLister := NewMockMetricLister(ctrl)
-	svc := NewMetricListService(mockLister)
+	svc := NewMetricListService(mockLister, 0)
 
 	ctx := context.Background()
 	expectedMetrics := []types.Metrics{
@@ -54,3 +55,23 @@ func TestMetricListService_List(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Equal(t, expectedErr, err)
 }
+
+func TestMetricListService_List_ExpiredSet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLister := NewMockMetricLister(ctrl)
+	svc := NewMetricListService(mockLister, time.Hour)
+
+	stale := types.NewSetValue([]string{"alice"}, time.Now().Add(-2*time.Hour))
+	fresh := types.NewSetValue([]string{"alice", "bob"}, time.Now())
+	mockLister.EXPECT().List(gomock.Any()).Return([]types.Metrics{
+		{ID: "stale", MType: types.Set, Set: &stale},
+		{ID: "fresh", MType: types.Set, Set: &fresh},
+	}, nil)
+
+	result, err := svc.List(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), result[0].Set.Cardinality())
+	assert.Equal(t, uint64(2), result[1].Set.Cardinality())
+}
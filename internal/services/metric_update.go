@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/sbilibin2017/yp-metrics/internal/logger"
 	"github.com/sbilibin2017/yp-metrics/internal/types"
@@ -15,24 +16,68 @@ type MetricUpdateGetter interface {
 	Get(ctx context.Context, id types.MetricID) (*types.Metrics, error)
 }
 
+type MetricUpdateHistorySaver interface {
+	Save(ctx context.Context, id types.MetricID, sample types.MetricSample) error
+}
+
+type MetricUpdateInstanceSaver interface {
+	Save(ctx context.Context, instance string, lastSeen time.Time) error
+}
+
+type MetricUpdateTimeSaver interface {
+	Save(ctx context.Context, id types.MetricID, updatedAt time.Time) error
+}
+
 type MetricUpdateService struct {
-	saver  MetricUpdateSaver
-	getter MetricUpdateGetter
+	saver           MetricUpdateSaver
+	getter          MetricUpdateGetter
+	historySaver    MetricUpdateHistorySaver
+	instanceSaver   MetricUpdateInstanceSaver
+	updateTimeSaver MetricUpdateTimeSaver
+	instanceGetter  func(ctx context.Context) string
+	setWindow       time.Duration
 }
 
 func NewMetricUpdateService(
 	saver MetricUpdateSaver,
 	getter MetricUpdateGetter,
+	historySaver MetricUpdateHistorySaver,
+	instanceSaver MetricUpdateInstanceSaver,
+	updateTimeSaver MetricUpdateTimeSaver,
+	instanceGetter func(ctx context.Context) string,
+	setWindow time.Duration,
 ) *MetricUpdateService {
-	return &MetricUpdateService{saver: saver, getter: getter}
+	return &MetricUpdateService{
+		saver:           saver,
+		getter:          getter,
+		historySaver:    historySaver,
+		instanceSaver:   instanceSaver,
+		updateTimeSaver: updateTimeSaver,
+		instanceGetter:  instanceGetter,
+		setWindow:       setWindow,
+	}
 }
 
 func (svc *MetricUpdateService) Update(
 	ctx context.Context,
 	metrics types.Metrics,
 ) error {
+	if instance := svc.instanceGetter(ctx); instance != "" {
+		labels := make(types.Labels, len(metrics.Labels)+1)
+		for k, v := range metrics.Labels {
+			labels[k] = v
+		}
+		labels[types.InstanceLabel] = instance
+		metrics.Labels = labels
+
+		if err := svc.instanceSaver.Save(ctx, instance, time.Now()); err != nil {
+			logger.Log.Errorw("Failed to save instance last-seen time", "instance", instance, "error", err)
+			return err
+		}
+	}
+
 	if metrics.MType == types.Counter {
-		currentMetric, err := svc.getter.Get(ctx, types.MetricID{ID: metrics.ID, MType: metrics.MType})
+		currentMetric, err := svc.getter.Get(ctx, metrics.MetricID())
 		if err != nil {
 			logger.Log.Errorw("Failed to retrieve current metric", "id", metrics.ID, "error", err)
 			return types.ErrInternalServerError
@@ -45,10 +90,82 @@ func (svc *MetricUpdateService) Update(
 		}
 	}
 
+	if metrics.MType == types.Histogram {
+		currentMetric, err := svc.getter.Get(ctx, metrics.MetricID())
+		if err != nil {
+			logger.Log.Errorw("Failed to retrieve current metric", "id", metrics.ID, "error", err)
+			return types.ErrInternalServerError
+		}
+
+		if currentMetric != nil && currentMetric.Histogram != nil {
+			merged, err := currentMetric.Histogram.Merge(*metrics.Histogram)
+			if err != nil {
+				return err
+			}
+			*metrics.Histogram = merged
+		}
+	}
+
+	if metrics.MType == types.Summary {
+		currentMetric, err := svc.getter.Get(ctx, metrics.MetricID())
+		if err != nil {
+			logger.Log.Errorw("Failed to retrieve current metric", "id", metrics.ID, "error", err)
+			return types.ErrInternalServerError
+		}
+
+		summary := types.NewSummaryValue(*metrics.Value)
+		if currentMetric != nil && currentMetric.Summary != nil {
+			summary, err = currentMetric.Summary.Merge(summary)
+			if err != nil {
+				return err
+			}
+		}
+		metrics.Value = nil
+		metrics.Summary = &summary
+	}
+
+	if metrics.MType == types.Set {
+		currentMetric, err := svc.getter.Get(ctx, metrics.MetricID())
+		if err != nil {
+			logger.Log.Errorw("Failed to retrieve current metric", "id", metrics.ID, "error", err)
+			return types.ErrInternalServerError
+		}
+
+		now := time.Now()
+		set := types.NewSetValue(metrics.Members, now)
+		if currentMetric != nil && currentMetric.Set != nil && !currentMetric.Set.Expired(svc.setWindow, now) {
+			set, err = currentMetric.Set.Merge(set)
+			if err != nil {
+				return err
+			}
+		}
+		metrics.Members = nil
+		metrics.Set = &set
+	}
+
 	if err := svc.saver.Save(ctx, metrics); err != nil {
 		logger.Log.Errorw("Failed to save metric", "id", metrics.ID, "type", metrics.MType, "error", err)
 		return err
 	}
 
+	id := metrics.MetricID()
+	now := time.Now()
+	if err := svc.historySaver.Save(ctx, id, types.NewMetricSample(metrics, now)); err != nil {
+		logger.Log.Errorw("Failed to save metric history", "id", metrics.ID, "type", metrics.MType, "error", err)
+		return err
+	}
+
+	ids := []types.MetricID{id}
+	if _, ok := metrics.Labels[types.InstanceLabel]; ok {
+		aggregated := types.Metrics{ID: metrics.ID, MType: metrics.MType, Labels: metrics.Labels.Without(types.InstanceLabel)}
+		ids = append(ids, aggregated.MetricID())
+	}
+	for _, updatedID := range ids {
+		if err := svc.updateTimeSaver.Save(ctx, updatedID, now); err != nil {
+			logger.Log.Errorw("Failed to save metric update time", "id", metrics.ID, "type", metrics.MType, "error", err)
+			return err
+		}
+	}
+
 	return nil
 }
@@ -7,6 +7,7 @@ package services
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	types "github.com/sbilibin2017/yp-metrics/internal/types"
@@ -86,3 +87,114 @@ func (mr *MockMetricUpdateGetterMockRecorder) Get(ctx, id interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockMetricUpdateGetter)(nil).Get), ctx, id)
 }
+
+// MockMetricUpdateHistorySaver is a mock of MetricUpdateHistorySaver interface.
+type MockMetricUpdateHistorySaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricUpdateHistorySaverMockRecorder
+}
+
+// MockMetricUpdateHistorySaverMockRecorder is the mock recorder for MockMetricUpdateHistorySaver.
+type MockMetricUpdateHistorySaverMockRecorder struct {
+	mock *MockMetricUpdateHistorySaver
+}
+
+// NewMockMetricUpdateHistorySaver creates a new mock instance.
+func NewMockMetricUpdateHistorySaver(ctrl *gomock.Controller) *MockMetricUpdateHistorySaver {
+	mock := &MockMetricUpdateHistorySaver{ctrl: ctrl}
+	mock.recorder = &MockMetricUpdateHistorySaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricUpdateHistorySaver) EXPECT() *MockMetricUpdateHistorySaverMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockMetricUpdateHistorySaver) Save(ctx context.Context, id types.MetricID, sample types.MetricSample) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, id, sample)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockMetricUpdateHistorySaverMockRecorder) Save(ctx, id, sample interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMetricUpdateHistorySaver)(nil).Save), ctx, id, sample)
+}
+
+// MockMetricUpdateInstanceSaver is a mock of MetricUpdateInstanceSaver interface.
+type MockMetricUpdateInstanceSaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricUpdateInstanceSaverMockRecorder
+}
+
+// MockMetricUpdateInstanceSaverMockRecorder is the mock recorder for MockMetricUpdateInstanceSaver.
+type MockMetricUpdateInstanceSaverMockRecorder struct {
+	mock *MockMetricUpdateInstanceSaver
+}
+
+// NewMockMetricUpdateInstanceSaver creates a new mock instance.
+func NewMockMetricUpdateInstanceSaver(ctrl *gomock.Controller) *MockMetricUpdateInstanceSaver {
+	mock := &MockMetricUpdateInstanceSaver{ctrl: ctrl}
+	mock.recorder = &MockMetricUpdateInstanceSaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricUpdateInstanceSaver) EXPECT() *MockMetricUpdateInstanceSaverMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockMetricUpdateInstanceSaver) Save(ctx context.Context, instance string, lastSeen time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, instance, lastSeen)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockMetricUpdateInstanceSaverMockRecorder) Save(ctx, instance, lastSeen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMetricUpdateInstanceSaver)(nil).Save), ctx, instance, lastSeen)
+}
+
+// MockMetricUpdateTimeSaver is a mock of MetricUpdateTimeSaver interface.
+type MockMetricUpdateTimeSaver struct {
+	ctrl     *gomock.Controller
+	recorder *MockMetricUpdateTimeSaverMockRecorder
+}
+
+// MockMetricUpdateTimeSaverMockRecorder is the mock recorder for MockMetricUpdateTimeSaver.
+type MockMetricUpdateTimeSaverMockRecorder struct {
+	mock *MockMetricUpdateTimeSaver
+}
+
+// NewMockMetricUpdateTimeSaver creates a new mock instance.
+func NewMockMetricUpdateTimeSaver(ctrl *gomock.Controller) *MockMetricUpdateTimeSaver {
+	mock := &MockMetricUpdateTimeSaver{ctrl: ctrl}
+	mock.recorder = &MockMetricUpdateTimeSaverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMetricUpdateTimeSaver) EXPECT() *MockMetricUpdateTimeSaverMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockMetricUpdateTimeSaver) Save(ctx context.Context, id types.MetricID, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, id, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockMetricUpdateTimeSaverMockRecorder) Save(ctx, id, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMetricUpdateTimeSaver)(nil).Save), ctx, id, updatedAt)
+}
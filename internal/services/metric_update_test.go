@@ -4,16 +4,19 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/contexts"
 	"github.com/sbilibin2017/yp-metrics/internal/services"
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMetricUpdateService_Update(t *testing.T) {
 	type fields struct {
-		setupMocks func(*services.MockMetricUpdateSaver, *services.MockMetricUpdateGetter)
+		setupMocks func(*services.MockMetricUpdateSaver, *services.MockMetricUpdateGetter, *services.MockMetricUpdateHistorySaver)
 	}
 	type args struct {
 		metrics types.Metrics
@@ -31,11 +34,13 @@ func TestMetricUpdateService_Update(t *testing.T) {
 		{
 			name: "counter metric - existing value added",
 			fields: fields{
-				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter) {
+				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter, history *services.MockMetricUpdateHistorySaver) {
 					getter.EXPECT().Get(gomock.Any(), types.MetricID{ID: "requests", MType: types.Counter}).
 						Return(&types.Metrics{ID: "requests", MType: types.Counter, Delta: int64Ptr(5)}, nil)
 					saver.EXPECT().Save(gomock.Any(), types.Metrics{ID: "requests", MType: types.Counter, Delta: int64Ptr(15)}).
 						Return(nil)
+					history.EXPECT().Save(gomock.Any(), types.MetricID{ID: "requests", MType: types.Counter}, gomock.Any()).
+						Return(nil)
 				},
 			},
 			args: args{
@@ -47,7 +52,7 @@ func TestMetricUpdateService_Update(t *testing.T) {
 		{
 			name: "getter fails",
 			fields: fields{
-				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter) {
+				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter, history *services.MockMetricUpdateHistorySaver) {
 					getter.EXPECT().Get(gomock.Any(), types.MetricID{ID: "fail_metric", MType: types.Counter}).
 						Return(nil, errors.New("db error"))
 				},
@@ -60,9 +65,11 @@ func TestMetricUpdateService_Update(t *testing.T) {
 		{
 			name: "gauge metric - saved directly",
 			fields: fields{
-				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter) {
+				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter, history *services.MockMetricUpdateHistorySaver) {
 					saver.EXPECT().Save(gomock.Any(), types.Metrics{ID: "temp", MType: types.Gauge, Value: float64Ptr(42.42)}).
 						Return(nil)
+					history.EXPECT().Save(gomock.Any(), types.MetricID{ID: "temp", MType: types.Gauge}, gomock.Any()).
+						Return(nil)
 				},
 			},
 			args: args{
@@ -74,7 +81,7 @@ func TestMetricUpdateService_Update(t *testing.T) {
 		{
 			name: "save fails",
 			fields: fields{
-				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter) {
+				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter, history *services.MockMetricUpdateHistorySaver) {
 					saver.EXPECT().Save(gomock.Any(), gomock.Any()).
 						Return(errors.New("save error"))
 				},
@@ -84,15 +91,31 @@ func TestMetricUpdateService_Update(t *testing.T) {
 			},
 			wantErr: errors.New("save error"),
 		},
+		{
+			name: "history save fails",
+			fields: fields{
+				setupMocks: func(saver *services.MockMetricUpdateSaver, getter *services.MockMetricUpdateGetter, history *services.MockMetricUpdateHistorySaver) {
+					saver.EXPECT().Save(gomock.Any(), gomock.Any()).
+						Return(nil)
+					history.EXPECT().Save(gomock.Any(), types.MetricID{ID: "some", MType: types.Gauge}, gomock.Any()).
+						Return(errors.New("history error"))
+				},
+			},
+			args: args{
+				metrics: types.Metrics{ID: "some", MType: types.Gauge, Value: float64Ptr(100)},
+			},
+			wantErr: errors.New("history error"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockSaver := services.NewMockMetricUpdateSaver(ctrl)
 			mockGetter := services.NewMockMetricUpdateGetter(ctrl)
-			tt.fields.setupMocks(mockSaver, mockGetter)
+			mockHistorySaver := services.NewMockMetricUpdateHistorySaver(ctrl)
+			tt.fields.setupMocks(mockSaver, mockGetter, mockHistorySaver)
 
-			svc := services.NewMetricUpdateService(mockSaver, mockGetter)
+			svc := services.NewMetricUpdateService(mockSaver, mockGetter, mockHistorySaver, services.NewMockMetricUpdateInstanceSaver(ctrl), anyUpdateTimeSaver(ctrl), contexts.GetInstanceFromContext, 0)
 			err := svc.Update(context.Background(), tt.args.metrics)
 
 			assert.Equal(t, tt.wantErr, err)
@@ -103,7 +126,160 @@ func TestMetricUpdateService_Update(t *testing.T) {
 	}
 }
 
+func TestMetricUpdateService_Update_Instance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSaver := services.NewMockMetricUpdateSaver(ctrl)
+	mockGetter := services.NewMockMetricUpdateGetter(ctrl)
+	mockHistorySaver := services.NewMockMetricUpdateHistorySaver(ctrl)
+	mockInstanceSaver := services.NewMockMetricUpdateInstanceSaver(ctrl)
+	mockUpdateTimeSaver := services.NewMockMetricUpdateTimeSaver(ctrl)
+
+	svc := services.NewMetricUpdateService(mockSaver, mockGetter, mockHistorySaver, mockInstanceSaver, mockUpdateTimeSaver, contexts.GetInstanceFromContext, 0)
+	ctx := contexts.SetInstanceToContext(context.Background(), "web01")
+
+	labels := types.Labels{"region": "eu"}
+	expected := types.Metrics{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"region": "eu", types.InstanceLabel: "web01"}, Value: float64Ptr(1)}
+
+	gomock.InOrder(
+		mockInstanceSaver.EXPECT().Save(gomock.Any(), "web01", gomock.Any()).Return(nil),
+		mockSaver.EXPECT().Save(gomock.Any(), expected).Return(nil),
+		mockHistorySaver.EXPECT().Save(gomock.Any(), expected.MetricID(), gomock.Any()).Return(nil),
+		// время обновления запоминается и для серии инстанса, и для агрегата без метки instance
+		mockUpdateTimeSaver.EXPECT().Save(gomock.Any(), expected.MetricID(), gomock.Any()).Return(nil),
+		mockUpdateTimeSaver.EXPECT().Save(gomock.Any(), types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "region=eu"}, gomock.Any()).Return(nil),
+	)
+
+	err := svc.Update(ctx, types.Metrics{ID: "Alloc", MType: types.Gauge, Labels: labels, Value: float64Ptr(1)})
+	assert.NoError(t, err)
+	// исходные метки запроса не изменяются
+	assert.Equal(t, types.Labels{"region": "eu"}, labels)
+
+	t.Run("instance save fails", func(t *testing.T) {
+		mockInstanceSaver.EXPECT().Save(gomock.Any(), "web01", gomock.Any()).Return(errors.New("registry error"))
+
+		err := svc.Update(ctx, types.Metrics{ID: "Alloc", MType: types.Gauge, Value: float64Ptr(1)})
+		assert.EqualError(t, err, "registry error")
+	})
+}
+
 // helpers
+func TestMetricUpdateService_Update_Histogram(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "latency", MType: types.Histogram}
+	stored := &types.Metrics{
+		ID:        "latency",
+		MType:     types.Histogram,
+		Histogram: &types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{1, 2, 0}, Sum: 1.5},
+	}
+
+	t.Run("merged with stored buckets", func(t *testing.T) {
+		saver := services.NewMockMetricUpdateSaver(ctrl)
+		getter := services.NewMockMetricUpdateGetter(ctrl)
+		history := services.NewMockMetricUpdateHistorySaver(ctrl)
+
+		expected := types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{2, 2, 1}, Sum: 4.55}
+		getter.EXPECT().Get(gomock.Any(), id).Return(stored, nil)
+		saver.EXPECT().Save(gomock.Any(), types.Metrics{ID: "latency", MType: types.Histogram, Histogram: &expected}).Return(nil)
+		history.EXPECT().Save(gomock.Any(), id, gomock.Any()).Return(nil)
+
+		svc := services.NewMetricUpdateService(saver, getter, history, services.NewMockMetricUpdateInstanceSaver(ctrl), anyUpdateTimeSaver(ctrl), contexts.GetInstanceFromContext, 0)
+		metric := types.Metrics{
+			ID:        "latency",
+			MType:     types.Histogram,
+			Histogram: &types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{1, 0, 1}, Sum: 3.05},
+		}
+		require.NoError(t, svc.Update(context.Background(), metric))
+		assert.Equal(t, expected, *metric.Histogram)
+		// сохранённая гистограмма не изменяется на месте
+		assert.Equal(t, []int64{1, 2, 0}, stored.Histogram.Counts)
+	})
+
+	t.Run("bounds mismatch", func(t *testing.T) {
+		saver := services.NewMockMetricUpdateSaver(ctrl)
+		getter := services.NewMockMetricUpdateGetter(ctrl)
+		history := services.NewMockMetricUpdateHistorySaver(ctrl)
+
+		getter.EXPECT().Get(gomock.Any(), id).Return(stored, nil)
+
+		svc := services.NewMetricUpdateService(saver, getter, history, services.NewMockMetricUpdateInstanceSaver(ctrl), anyUpdateTimeSaver(ctrl), contexts.GetInstanceFromContext, 0)
+		err := svc.Update(context.Background(), types.Metrics{
+			ID:        "latency",
+			MType:     types.Histogram,
+			Histogram: &types.HistogramValue{Bounds: []float64{0.5}, Counts: []int64{1, 0}},
+		})
+		assert.ErrorIs(t, err, types.ErrHistogramBoundsMismatch)
+	})
+}
+
+func TestMetricUpdateService_Update_Summary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "rt", MType: types.Summary}
+	current := types.NewSummaryValue(0.1)
+	expected, err := current.Merge(types.NewSummaryValue(0.3))
+	require.NoError(t, err)
+
+	saver := services.NewMockMetricUpdateSaver(ctrl)
+	getter := services.NewMockMetricUpdateGetter(ctrl)
+	history := services.NewMockMetricUpdateHistorySaver(ctrl)
+
+	getter.EXPECT().Get(gomock.Any(), id).Return(&types.Metrics{ID: "rt", MType: types.Summary, Summary: &current}, nil)
+	// в хранилище уходит скетч, а не последнее наблюдение
+	saver.EXPECT().Save(gomock.Any(), types.Metrics{ID: "rt", MType: types.Summary, Summary: &expected}).Return(nil)
+	history.EXPECT().Save(gomock.Any(), id, gomock.Any()).Return(nil)
+
+	svc := services.NewMetricUpdateService(saver, getter, history, services.NewMockMetricUpdateInstanceSaver(ctrl), anyUpdateTimeSaver(ctrl), contexts.GetInstanceFromContext, 0)
+	require.NoError(t, svc.Update(context.Background(), types.Metrics{ID: "rt", MType: types.Summary, Value: float64Ptr(0.3)}))
+}
+
+func TestMetricUpdateService_Update_Set(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	id := types.MetricID{ID: "users", MType: types.Set}
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		expected uint64
+	}{
+		{"merged within window", time.Now(), 3},
+		{"reset after window", time.Now().Add(-2 * time.Hour), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saver := services.NewMockMetricUpdateSaver(ctrl)
+			getter := services.NewMockMetricUpdateGetter(ctrl)
+			history := services.NewMockMetricUpdateHistorySaver(ctrl)
+
+			current := types.NewSetValue([]string{"alice", "bob"}, tt.start)
+			getter.EXPECT().Get(gomock.Any(), id).Return(&types.Metrics{ID: "users", MType: types.Set, Set: &current}, nil)
+			saver.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, m types.Metrics) error {
+				assert.Nil(t, m.Members)
+				require.NotNil(t, m.Set)
+				assert.Equal(t, tt.expected, m.Set.Cardinality())
+				return nil
+			})
+			history.EXPECT().Save(gomock.Any(), id, gomock.Any()).Return(nil)
+
+			svc := services.NewMetricUpdateService(saver, getter, history, services.NewMockMetricUpdateInstanceSaver(ctrl), anyUpdateTimeSaver(ctrl), contexts.GetInstanceFromContext, time.Hour)
+			require.NoError(t, svc.Update(context.Background(), types.Metrics{ID: "users", MType: types.Set, Members: []string{"bob", "carol"}}))
+		})
+	}
+}
+
+func anyUpdateTimeSaver(ctrl *gomock.Controller) *services.MockMetricUpdateTimeSaver {
+	saver := services.NewMockMetricUpdateTimeSaver(ctrl)
+	saver.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return saver
+}
+
 func int64Ptr(v int64) *int64 {
 	return &v
 }
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	AlertThreshold = "threshold"
+	AlertRate      = "rate"
+	AlertAbsence   = "absence"
+)
+
+const (
+	AlertPending  = "pending"
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
+
+type AlertRule struct {
+	Name      string   `json:"name"`
+	Metric    MetricID `json:"metric"`
+	Kind      string   `json:"kind"`
+	Operator  string   `json:"operator,omitempty"`
+	Threshold float64  `json:"threshold,omitempty"`
+	For       int      `json:"for,omitempty"`
+}
+
+type Alert struct {
+	Rule     AlertRule  `json:"rule"`
+	State    string     `json:"state"`
+	Value    *float64   `json:"value,omitempty"`
+	ActiveAt time.Time  `json:"active_at"`
+	FiredAt  *time.Time `json:"fired_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+var (
+	ErrUnknownAlertOperator = errors.New("unknown alert operator")
+)
+
+func CompareAlertThreshold(operator string, value float64, threshold float64) (bool, error) {
+	switch operator {
+	case ">":
+		return value > threshold, nil
+	case ">=":
+		return value >= threshold, nil
+	case "<":
+		return value < threshold, nil
+	case "<=":
+		return value <= threshold, nil
+	case "==":
+		return value == threshold, nil
+	case "!=":
+		return value != threshold, nil
+	default:
+		return false, ErrUnknownAlertOperator
+	}
+}
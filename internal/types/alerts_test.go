@@ -0,0 +1,33 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareAlertThreshold(t *testing.T) {
+	tests := []struct {
+		operator  string
+		value     float64
+		threshold float64
+		want      bool
+		wantErr   error
+	}{
+		{">", 2, 1, true, nil},
+		{">", 1, 1, false, nil},
+		{">=", 1, 1, true, nil},
+		{"<", 0, 1, true, nil},
+		{"<=", 2, 1, false, nil},
+		{"==", 1, 1, true, nil},
+		{"!=", 1, 1, false, nil},
+		{"~", 1, 1, false, types.ErrUnknownAlertOperator},
+	}
+
+	for _, tt := range tests {
+		got, err := types.CompareAlertThreshold(tt.operator, tt.value, tt.threshold)
+		assert.Equal(t, tt.wantErr, err)
+		assert.Equal(t, tt.want, got)
+	}
+}
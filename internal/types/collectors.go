@@ -0,0 +1,9 @@
+package types
+
+type CustomCollector struct {
+	Name     string   `json:"name"`
+	Exec     []string `json:"exec,omitempty"`
+	File     string   `json:"file,omitempty"`
+	Interval int      `json:"interval,omitempty"`
+	Timeout  int      `json:"timeout,omitempty"`
+}
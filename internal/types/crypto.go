@@ -0,0 +1,146 @@
+package types
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+var (
+	ErrInvalidPEM        = errors.New("invalid PEM data")
+	ErrInvalidPublicKey  = errors.New("invalid RSA public key")
+	ErrInvalidPrivateKey = errors.New("invalid RSA private key")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+func ParseRSAPublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+	}
+
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		if rsaKey, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+	}
+
+	return nil, ErrInvalidPublicKey
+}
+
+func ParseRSAPrivateKeyPEM(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+	}
+
+	return nil, ErrInvalidPrivateKey
+}
+
+func EncryptRSA(key *rsa.PublicKey, data []byte) ([]byte, error) {
+	if len(data) <= maxRSAOAEPPlaintext(key) {
+		return rsa.EncryptOAEP(sha256.New(), rand.Reader, key, data, nil)
+	}
+
+	aesKey := make([]byte, 32)
+	if _, err := rand.Read(aesKey); err != nil {
+		return nil, err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, aesKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(aesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(encryptedKey)+len(nonce)+len(data)+gcm.Overhead())
+	out = append(out, encryptedKey...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, data, nil)
+
+	return out, nil
+}
+
+func DecryptRSA(key *rsa.PrivateKey, data []byte) ([]byte, error) {
+	keySize := key.Size()
+
+	if len(data) == keySize {
+		plain, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, data, nil)
+		if err != nil {
+			return nil, ErrInvalidCiphertext
+		}
+		return plain, nil
+	}
+
+	if len(data) < keySize {
+		return nil, ErrInvalidCiphertext
+	}
+
+	aesKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, data[:keySize], nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	gcm, err := newGCM(aesKey)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	rest := data[keySize:]
+	if len(rest) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	plain, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	return plain, nil
+}
+
+func maxRSAOAEPPlaintext(key *rsa.PublicKey) int {
+	return key.Size() - 2*sha256.Size - 2
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
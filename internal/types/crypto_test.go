@@ -0,0 +1,108 @@
+package types_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecryptRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "small payload", data: []byte(`[{"id":"Alloc","type":"gauge","value":1}]`)},
+		{name: "large payload", data: bytes.Repeat([]byte("metric"), 10000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := types.EncryptRSA(&key.PublicKey, tt.data)
+			require.NoError(t, err)
+			assert.NotEqual(t, tt.data, encrypted)
+
+			decrypted, err := types.DecryptRSA(key, encrypted)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.data), len(decrypted))
+			assert.True(t, bytes.Equal(tt.data, decrypted))
+		})
+	}
+}
+
+func TestDecryptRSA_InvalidCiphertext(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	large, err := types.EncryptRSA(&key.PublicKey, bytes.Repeat([]byte("x"), 1000))
+	require.NoError(t, err)
+
+	tampered := append([]byte{}, large...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := []struct {
+		name string
+		key  *rsa.PrivateKey
+		data []byte
+	}{
+		{name: "too short", key: key, data: []byte("plain")},
+		{name: "wrong key", key: otherKey, data: large},
+		{name: "tampered", key: key, data: tampered},
+		{name: "truncated", key: key, data: large[:key.Size()+4]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := types.DecryptRSA(tt.key, tt.data)
+			assert.ErrorIs(t, err, types.ErrInvalidCiphertext)
+		})
+	}
+}
+
+func TestParseRSAKeysPEM(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkcs1Priv := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pkcs8Priv := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	pkcs1Pub := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)})
+	pkixBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	pkixPub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkixBytes})
+
+	for _, data := range [][]byte{pkcs1Priv, pkcs8Priv} {
+		parsed, err := types.ParseRSAPrivateKeyPEM(data)
+		require.NoError(t, err)
+		assert.True(t, key.Equal(parsed))
+	}
+
+	for _, data := range [][]byte{pkcs1Pub, pkixPub} {
+		parsed, err := types.ParseRSAPublicKeyPEM(data)
+		require.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(parsed))
+	}
+
+	_, err = types.ParseRSAPrivateKeyPEM([]byte("not a pem"))
+	assert.ErrorIs(t, err, types.ErrInvalidPEM)
+
+	_, err = types.ParseRSAPublicKeyPEM(pkcs1Priv)
+	assert.ErrorIs(t, err, types.ErrInvalidPublicKey)
+
+	_, err = types.ParseRSAPrivateKeyPEM(pkixPub)
+	assert.ErrorIs(t, err, types.ErrInvalidPrivateKey)
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidGraphiteLine = errors.New("invalid graphite line")
+)
+
+func ParseGraphiteLine(line string) (Metrics, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return Metrics{}, ErrInvalidGraphiteLine
+	}
+
+	value, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return Metrics{}, ErrInvalidGraphiteLine
+	}
+
+	if _, err := strconv.ParseFloat(fields[2], 64); err != nil {
+		return Metrics{}, ErrInvalidGraphiteLine
+	}
+
+	return Metrics{ID: fields[0], MType: Gauge, Value: &value}, nil
+}
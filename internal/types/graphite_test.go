@@ -0,0 +1,40 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGraphiteLine(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		line     string
+		expected types.Metrics
+		wantErr  bool
+	}{
+		{"valid", "servers.web01.load 1.5 1700000000", types.Metrics{ID: "servers.web01.load", MType: types.Gauge, Value: value(1.5)}, false},
+		{"float timestamp and extra spaces", "  disk.used   42   1700000000.5 ", types.Metrics{ID: "disk.used", MType: types.Gauge, Value: value(42)}, false},
+		{"missing timestamp", "disk.used 42", types.Metrics{}, true},
+		{"too many fields", "disk.used 42 1700000000 extra", types.Metrics{}, true},
+		{"bad value", "disk.used abc 1700000000", types.Metrics{}, true},
+		{"nan value", "disk.used NaN 1700000000", types.Metrics{}, true},
+		{"bad timestamp", "disk.used 42 now", types.Metrics{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := types.ParseGraphiteLine(tt.line)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, types.ErrInvalidGraphiteLine)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
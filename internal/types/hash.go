@@ -0,0 +1,48 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+const (
+	HashSHA256Header   = "HashSHA256"
+	HashSHA256Metadata = "hashsha256"
+)
+
+var (
+	ErrInvalidHash = errors.New("invalid hash")
+)
+
+func GetHashSHA256(data []byte, key string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func InstanceHashData(instance string, data []byte) []byte {
+	if instance == "" {
+		return data
+	}
+	signed := make([]byte, 0, len(instance)+1+len(data))
+	signed = append(signed, instance...)
+	signed = append(signed, '\n')
+	return append(signed, data...)
+}
+
+func CheckHashSHA256(data []byte, key string, hash string) error {
+	expected, err := hex.DecodeString(hash)
+	if err != nil {
+		return ErrInvalidHash
+	}
+
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write(data)
+	if !hmac.Equal(h.Sum(nil), expected) {
+		return ErrInvalidHash
+	}
+
+	return nil
+}
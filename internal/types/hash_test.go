@@ -0,0 +1,47 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHashSHA256(t *testing.T) {
+	// Эталонное значение HMAC-SHA256 из RFC 4231 (test case 2)
+	hash := types.GetHashSHA256([]byte("what do ya want for nothing?"), "Jefe")
+	assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", hash)
+}
+
+func TestInstanceHashData(t *testing.T) {
+	data := []byte(`[]`)
+
+	assert.Equal(t, data, types.InstanceHashData("", data))
+	assert.Equal(t, []byte("web01\n[]"), types.InstanceHashData("web01", data))
+}
+
+func TestCheckHashSHA256(t *testing.T) {
+	data := []byte(`[{"id":"Alloc","type":"gauge","value":1}]`)
+	hash := types.GetHashSHA256(data, "secret")
+
+	tests := []struct {
+		name    string
+		data    []byte
+		key     string
+		hash    string
+		wantErr error
+	}{
+		{name: "valid", data: data, key: "secret", hash: hash},
+		{name: "wrong key", data: data, key: "other", hash: hash, wantErr: types.ErrInvalidHash},
+		{name: "modified body", data: []byte(`[]`), key: "secret", hash: hash, wantErr: types.ErrInvalidHash},
+		{name: "not hex", data: data, key: "secret", hash: "zz", wantErr: types.ErrInvalidHash},
+		{name: "empty hash", data: data, key: "secret", hash: "", wantErr: types.ErrInvalidHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := types.CheckHashSHA256(tt.data, tt.key, tt.hash)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const MaxHistogramBuckets = 1000
+
+var (
+	ErrInvalidHistogram        = errors.New("invalid histogram")
+	ErrHistogramBoundsMismatch = errors.New("histogram bucket boundaries do not match")
+	ErrInvalidQuantile         = errors.New("quantile must be a number between 0 and 1")
+)
+
+type HistogramValue struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []int64   `json:"counts"`
+	Sum    float64   `json:"sum"`
+}
+
+func (h HistogramValue) Validate() error {
+	if len(h.Bounds) == 0 {
+		return fmt.Errorf("%w: at least one bucket boundary is required", ErrInvalidHistogram)
+	}
+	if len(h.Bounds) > MaxHistogramBuckets {
+		return fmt.Errorf("%w: at most %d bucket boundaries are allowed", ErrInvalidHistogram, MaxHistogramBuckets)
+	}
+	for i, b := range h.Bounds {
+		if math.IsNaN(b) || math.IsInf(b, 0) {
+			return fmt.Errorf("%w: bucket boundary must be finite", ErrInvalidHistogram)
+		}
+		if i > 0 && b <= h.Bounds[i-1] {
+			return fmt.Errorf("%w: bucket boundaries must be strictly increasing", ErrInvalidHistogram)
+		}
+	}
+	if len(h.Counts) != len(h.Bounds)+1 {
+		return fmt.Errorf("%w: expected %d bucket counts, got %d", ErrInvalidHistogram, len(h.Bounds)+1, len(h.Counts))
+	}
+	for _, c := range h.Counts {
+		if c < 0 {
+			return fmt.Errorf("%w: bucket count must not be negative", ErrInvalidHistogram)
+		}
+	}
+	if math.IsNaN(h.Sum) || math.IsInf(h.Sum, 0) {
+		return fmt.Errorf("%w: sum must be finite", ErrInvalidHistogram)
+	}
+	return nil
+}
+
+func (h HistogramValue) Count() int64 {
+	var count int64
+	for _, c := range h.Counts {
+		count += c
+	}
+	return count
+}
+
+func (h HistogramValue) Clone() HistogramValue {
+	return HistogramValue{
+		Bounds: append([]float64(nil), h.Bounds...),
+		Counts: append([]int64(nil), h.Counts...),
+		Sum:    h.Sum,
+	}
+}
+
+func (h HistogramValue) Merge(other HistogramValue) (HistogramValue, error) {
+	if len(h.Bounds) != len(other.Bounds) || len(h.Counts) != len(other.Counts) {
+		return HistogramValue{}, ErrHistogramBoundsMismatch
+	}
+	for i := range h.Bounds {
+		if h.Bounds[i] != other.Bounds[i] {
+			return HistogramValue{}, ErrHistogramBoundsMismatch
+		}
+	}
+
+	merged := h.Clone()
+	merged.Sum += other.Sum
+	for i := range merged.Counts {
+		merged.Counts[i] += other.Counts[i]
+	}
+	return merged, nil
+}
+
+func (h HistogramValue) Quantile(q float64) (float64, error) {
+	if math.IsNaN(q) || q < 0 || q > 1 {
+		return 0, ErrInvalidQuantile
+	}
+
+	count := h.Count()
+	if count == 0 || len(h.Bounds) == 0 {
+		return math.NaN(), nil
+	}
+
+	rank := q * float64(count)
+	var cumulative int64
+	for i, c := range h.Counts {
+		if c == 0 || float64(cumulative+c) < rank {
+			cumulative += c
+			continue
+		}
+		if i == len(h.Bounds) {
+			return h.Bounds[len(h.Bounds)-1], nil
+		}
+
+		upper := h.Bounds[i]
+		lower := 0.0
+		if i > 0 {
+			lower = h.Bounds[i-1]
+		} else if upper <= 0 {
+			return upper, nil
+		}
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(c), nil
+	}
+
+	return h.Bounds[len(h.Bounds)-1], nil
+}
+
+func (h HistogramValue) String() string {
+	return "count=" + strconv.FormatInt(h.Count(), 10) + " sum=" + strconv.FormatFloat(h.Sum, 'f', -1, 64)
+}
+
+func (h HistogramValue) Value() (driver.Value, error) {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (h *HistogramValue) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidHistogram, src)
+	}
+	return json.Unmarshal(data, h)
+}
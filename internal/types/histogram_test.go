@@ -0,0 +1,126 @@
+package types_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramValue_Validate(t *testing.T) {
+	tooManyBounds := make([]float64, types.MaxHistogramBuckets+1)
+	for i := range tooManyBounds {
+		tooManyBounds[i] = float64(i)
+	}
+
+	tests := []struct {
+		name      string
+		histogram types.HistogramValue
+		wantErr   bool
+	}{
+		{"valid", types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{1, 2, 3}, Sum: 4}, false},
+		{"no bounds", types.HistogramValue{Counts: []int64{1}}, true},
+		{"unsorted bounds", types.HistogramValue{Bounds: []float64{1, 0.1}, Counts: []int64{0, 0, 0}}, true},
+		{"duplicate bounds", types.HistogramValue{Bounds: []float64{1, 1}, Counts: []int64{0, 0, 0}}, true},
+		{"infinite bound", types.HistogramValue{Bounds: []float64{math.Inf(1)}, Counts: []int64{0, 0}}, true},
+		{"counts length", types.HistogramValue{Bounds: []float64{1}, Counts: []int64{1}}, true},
+		{"negative count", types.HistogramValue{Bounds: []float64{1}, Counts: []int64{-1, 0}}, true},
+		{"too many buckets", types.HistogramValue{Bounds: tooManyBounds, Counts: make([]int64, len(tooManyBounds)+1)}, true},
+		{"NaN sum", types.HistogramValue{Bounds: []float64{1}, Counts: []int64{0, 0}, Sum: math.NaN()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.histogram.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, types.ErrInvalidHistogram)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHistogramValue_Merge(t *testing.T) {
+	a := types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{1, 2, 3}, Sum: 4}
+	b := types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{4, 0, 1}, Sum: 2.5}
+
+	merged, err := a.Merge(b)
+	require.NoError(t, err)
+	assert.Equal(t, types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{5, 2, 4}, Sum: 6.5}, merged)
+	assert.Equal(t, int64(11), merged.Count())
+
+	// исходные гистограммы не меняются
+	assert.Equal(t, []int64{1, 2, 3}, a.Counts)
+
+	_, err = a.Merge(types.HistogramValue{Bounds: []float64{0.2, 1}, Counts: []int64{0, 0, 0}})
+	assert.ErrorIs(t, err, types.ErrHistogramBoundsMismatch)
+
+	_, err = a.Merge(types.HistogramValue{Bounds: []float64{0.1}, Counts: []int64{0, 0}})
+	assert.ErrorIs(t, err, types.ErrHistogramBoundsMismatch)
+}
+
+func TestHistogramValue_Quantile(t *testing.T) {
+	h := types.HistogramValue{Bounds: []float64{1, 2, 4}, Counts: []int64{2, 4, 2, 2}}
+
+	tests := []struct {
+		q        float64
+		expected float64
+	}{
+		{0, 0},
+		{0.1, 0.5},
+		{0.2, 1},
+		{0.4, 1.5},
+		{0.7, 3},
+		{0.99, 4},
+		{1, 4},
+	}
+
+	for _, tt := range tests {
+		got, err := h.Quantile(tt.q)
+		require.NoError(t, err)
+		assert.InDelta(t, tt.expected, got, 1e-9, "q=%v", tt.q)
+	}
+
+	_, err := h.Quantile(1.5)
+	assert.ErrorIs(t, err, types.ErrInvalidQuantile)
+	_, err = h.Quantile(-0.1)
+	assert.ErrorIs(t, err, types.ErrInvalidQuantile)
+
+	empty, err := types.HistogramValue{Bounds: []float64{1}, Counts: []int64{0, 0}}.Quantile(0.5)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(empty))
+
+	negative, err := types.HistogramValue{Bounds: []float64{-1, 1}, Counts: []int64{1, 0, 0}}.Quantile(0.5)
+	require.NoError(t, err)
+	assert.Equal(t, -1.0, negative)
+}
+
+func TestHistogramValue_ValueScan(t *testing.T) {
+	h := types.HistogramValue{Bounds: []float64{0.5}, Counts: []int64{1, 2}, Sum: 3.5}
+
+	value, err := h.Value()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"bounds":[0.5],"counts":[1,2],"sum":3.5}`, value.(string))
+
+	var scanned types.HistogramValue
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, h, scanned)
+
+	assert.ErrorIs(t, scanned.Scan(42), types.ErrInvalidHistogram)
+}
+
+func TestMetrics_HistogramJSON(t *testing.T) {
+	var m types.Metrics
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"latency","type":"histogram","histogram":{"bounds":[0.1],"counts":[1,0],"sum":0.05}}`), &m))
+	require.NotNil(t, m.Histogram)
+	assert.Equal(t, types.Histogram, m.MType)
+	assert.Equal(t, int64(1), m.Histogram.Count())
+
+	value, err := types.GetMetricValueString(m)
+	require.NoError(t, err)
+	assert.Equal(t, "count=1 sum=0.05", value)
+}
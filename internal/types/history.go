@@ -0,0 +1,43 @@
+package types
+
+import "time"
+
+type MetricSample struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Delta     *int64          `json:"delta,omitempty"`
+	Value     *float64        `json:"value,omitempty"`
+	Histogram *HistogramValue `json:"histogram,omitempty"`
+	Summary   *SummaryValue   `json:"summary,omitempty"`
+}
+
+type MetricHistory struct {
+	ID      string         `json:"id"`
+	MType   string         `json:"type"`
+	Labels  Labels         `json:"labels,omitempty"`
+	Samples []MetricSample `json:"samples"`
+}
+
+func NewMetricSample(metric Metrics, timestamp time.Time) MetricSample {
+	sample := MetricSample{Timestamp: timestamp}
+	if metric.Delta != nil {
+		delta := *metric.Delta
+		sample.Delta = &delta
+	}
+	if metric.Value != nil {
+		value := *metric.Value
+		sample.Value = &value
+	}
+	if metric.Histogram != nil {
+		histogram := metric.Histogram.Clone()
+		sample.Histogram = &histogram
+	}
+	if metric.Summary != nil {
+		summary := metric.Summary.Clone()
+		sample.Summary = &summary
+	}
+	if metric.Set != nil {
+		cardinality := float64(metric.Set.Cardinality())
+		sample.Value = &cardinality
+	}
+	return sample
+}
@@ -0,0 +1,21 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetricSample(t *testing.T) {
+	value := 1.5
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sample := types.NewMetricSample(types.Metrics{ID: "Alloc", MType: types.Gauge, Value: &value}, ts)
+
+	assert.Equal(t, ts, sample.Timestamp)
+	assert.Equal(t, value, *sample.Value)
+	assert.NotSame(t, &value, sample.Value)
+	assert.Nil(t, sample.Delta)
+}
@@ -0,0 +1,130 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidInfluxLine  = errors.New("invalid line protocol")
+	ErrInvalidInfluxField = errors.New("invalid line protocol field")
+)
+
+type InfluxLineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+func ParseInfluxLine(line string) ([]Metrics, error) {
+	parts := splitInfluxUnescaped(line, ' ')
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("%w: expected measurement, fields and optional timestamp", ErrInvalidInfluxLine)
+	}
+
+	series := splitInfluxUnescaped(parts[0], ',')
+	measurement := unescapeInflux(series[0])
+	if measurement == "" {
+		return nil, fmt.Errorf("%w: missing measurement", ErrInvalidInfluxLine)
+	}
+	var labels Labels
+	for _, tag := range series[1:] {
+		k, v, ok := strings.Cut(tag, "=")
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("%w: invalid tag %q", ErrInvalidInfluxLine, tag)
+		}
+		if labels == nil {
+			labels = make(Labels)
+		}
+		labels[unescapeInflux(k)] = unescapeInflux(v)
+	}
+
+	if len(parts) == 3 {
+		if _, err := strconv.ParseInt(parts[2], 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid timestamp %q", ErrInvalidInfluxLine, parts[2])
+		}
+	}
+
+	var metrics []Metrics
+	for _, field := range splitInfluxUnescaped(parts[1], ',') {
+		key, raw, ok := strings.Cut(field, "=")
+		if !ok || key == "" || raw == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidInfluxField, field)
+		}
+
+		m, err := parseInfluxField(measurement+"."+unescapeInflux(key), raw)
+		if err != nil {
+			return nil, err
+		}
+		m.Labels = labels
+		metrics = append(metrics, m)
+	}
+
+	return metrics, nil
+}
+
+func parseInfluxField(id string, raw string) (Metrics, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		return Metrics{}, fmt.Errorf("%w: string field %s is not supported", ErrInvalidInfluxField, id)
+	case strings.HasSuffix(raw, "i"):
+		n, err := strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return Metrics{}, fmt.Errorf("%w: %s=%s", ErrInvalidInfluxField, id, raw)
+		}
+		value := float64(n)
+		return Metrics{ID: id, MType: Gauge, Value: &value}, nil
+	case strings.HasSuffix(raw, "u"):
+		n, err := strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return Metrics{}, fmt.Errorf("%w: %s=%s", ErrInvalidInfluxField, id, raw)
+		}
+		value := float64(n)
+		return Metrics{ID: id, MType: Gauge, Value: &value}, nil
+	}
+
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		value := 1.0
+		return Metrics{ID: id, MType: Gauge, Value: &value}, nil
+	case "f", "F", "false", "False", "FALSE":
+		value := 0.0
+		return Metrics{ID: id, MType: Gauge, Value: &value}, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Metrics{}, fmt.Errorf("%w: %s=%s", ErrInvalidInfluxField, id, raw)
+	}
+	return Metrics{ID: id, MType: Gauge, Value: &value}, nil
+}
+
+func splitInfluxUnescaped(s string, sep byte) []string {
+	var (
+		parts   []string
+		start   int
+		escaped bool
+		quoted  bool
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+func unescapeInflux(s string) string {
+	return strings.NewReplacer(`\,`, ",", `\ `, " ", `\=`, "=", `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,65 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInfluxLine(t *testing.T) {
+	value := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		line     string
+		expected []types.Metrics
+		wantErr  error
+	}{
+		{
+			name: "fields with tags and timestamp",
+			line: "cpu,host=web01,region=eu usage_idle=92.5,processes=12i 1700000000000000000",
+			expected: []types.Metrics{
+				{ID: "cpu.usage_idle", MType: types.Gauge, Labels: types.Labels{"host": "web01", "region": "eu"}, Value: value(92.5)},
+				{ID: "cpu.processes", MType: types.Gauge, Labels: types.Labels{"host": "web01", "region": "eu"}, Value: value(12)},
+			},
+		},
+		{
+			name:     "unsigned and boolean",
+			line:     "disk free=10u,ok=true,failed=F",
+			expected: []types.Metrics{{ID: "disk.free", MType: types.Gauge, Value: value(10)}, {ID: "disk.ok", MType: types.Gauge, Value: value(1)}, {ID: "disk.failed", MType: types.Gauge, Value: value(0)}},
+		},
+		{
+			name:     "escaped measurement and field",
+			line:     `my\ app,env=prod req\,count=3i`,
+			expected: []types.Metrics{{ID: "my app.req,count", MType: types.Gauge, Labels: types.Labels{"env": "prod"}, Value: value(3)}},
+		},
+		{name: "missing fields", line: "cpu", wantErr: types.ErrInvalidInfluxLine},
+		{name: "empty measurement", line: ",host=a value=1", wantErr: types.ErrInvalidInfluxLine},
+		{name: "bad tag", line: "cpu,host value=1", wantErr: types.ErrInvalidInfluxLine},
+		{name: "bad timestamp", line: "cpu value=1 yesterday", wantErr: types.ErrInvalidInfluxLine},
+		{name: "bad field", line: "cpu value", wantErr: types.ErrInvalidInfluxField},
+		{name: "bad float", line: "cpu value=abc", wantErr: types.ErrInvalidInfluxField},
+		{
+			name:     "large unsigned",
+			line:     "disk total=18446744073709551615u",
+			expected: []types.Metrics{{ID: "disk.total", MType: types.Gauge, Value: value(18446744073709551615)}},
+		},
+		{name: "bad integer", line: "cpu value=1.5i", wantErr: types.ErrInvalidInfluxField},
+		{name: "negative unsigned", line: "cpu value=-1u", wantErr: types.ErrInvalidInfluxField},
+		{name: "string field", line: `cpu status="ok, fine"`, wantErr: types.ErrInvalidInfluxField},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics, err := types.ParseInfluxLine(tt.line)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, metrics)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+const (
+	InstanceIDHeader = "X-Instance-ID"
+	InstanceLabel    = "instance"
+)
+
+type Instance struct {
+	ID       string    `json:"id"`
+	LastSeen time.Time `json:"last_seen"`
+}
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidLabels = errors.New("invalid labels")
+)
+
+type Labels map[string]string
+
+func (l Labels) String() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(escapeLabel(k))
+		builder.WriteByte('=')
+		builder.WriteString(escapeLabel(l[k]))
+	}
+	return builder.String()
+}
+
+func (l Labels) Without(name string) Labels {
+	if _, ok := l[name]; !ok {
+		return l
+	}
+	if len(l) == 1 {
+		return nil
+	}
+
+	labels := make(Labels, len(l)-1)
+	for k, v := range l {
+		if k != name {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+func ParseLabels(s string) (Labels, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	labels := make(Labels)
+	for _, pair := range splitLabelsUnescaped(s, ',') {
+		kv := splitLabelsUnescaped(pair, '=')
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLabels, pair)
+		}
+
+		key, value := unescapeLabel(kv[0]), unescapeLabel(kv[1])
+		if key == "" || value == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLabels, pair)
+		}
+		if _, ok := labels[key]; ok {
+			return nil, fmt.Errorf("%w: duplicate label %q", ErrInvalidLabels, key)
+		}
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+func (l Labels) Value() (driver.Value, error) {
+	return l.String(), nil
+}
+
+func (l *Labels) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidLabels, src)
+	}
+
+	labels, err := ParseLabels(s)
+	if err != nil {
+		return err
+	}
+	*l = labels
+	return nil
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `,`, `\,`, `=`, `\=`).Replace(s)
+}
+
+func unescapeLabel(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\,`, `,`, `\=`, `=`).Replace(s)
+}
+
+func splitLabelsUnescaped(s string, sep byte) []string {
+	var (
+		parts   []string
+		start   int
+		escaped bool
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+type metricIDJSON struct {
+	ID     string `json:"id"`
+	MType  string `json:"type"`
+	Labels Labels `json:"labels,omitempty"`
+}
+
+func (id MetricID) MarshalJSON() ([]byte, error) {
+	labels, err := ParseLabels(id.Labels)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(metricIDJSON{ID: id.ID, MType: id.MType, Labels: labels})
+}
+
+func (id *MetricID) UnmarshalJSON(data []byte) error {
+	var aux metricIDJSON
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&aux); err != nil {
+		return err
+	}
+
+	*id = MetricID{ID: aux.ID, MType: aux.MType, Labels: aux.Labels.String()}
+	return nil
+}
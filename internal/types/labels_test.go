@@ -0,0 +1,110 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels_String(t *testing.T) {
+	assert.Equal(t, "", types.Labels(nil).String())
+	assert.Equal(t, "host=web01,region=eu", types.Labels{"region": "eu", "host": "web01"}.String())
+	assert.Equal(t, `path=a\,b\=c\\d`, types.Labels{"path": `a,b=c\d`}.String())
+}
+
+func TestLabels_Without(t *testing.T) {
+	labels := types.Labels{"instance": "web01", "region": "eu"}
+
+	assert.Equal(t, types.Labels{"region": "eu"}, labels.Without("instance"))
+	assert.Nil(t, types.Labels{"instance": "web01"}.Without("instance"))
+	assert.Equal(t, labels, labels.Without("host"))
+	// исходные метки не меняются
+	assert.Len(t, labels, 2)
+}
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected types.Labels
+		wantErr  bool
+	}{
+		{"empty", "", nil, false},
+		{"single", "host=web01", types.Labels{"host": "web01"}, false},
+		{"unsorted", "region=eu,host=web01", types.Labels{"host": "web01", "region": "eu"}, false},
+		{"escaped", `path=a\,b\=c\\d`, types.Labels{"path": `a,b=c\d`}, false},
+		{"missing value", "host=", nil, true},
+		{"missing separator", "host", nil, true},
+		{"empty key", "=web01", nil, true},
+		{"duplicate key", "host=a,host=b", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, err := types.ParseLabels(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, types.ErrInvalidLabels)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, labels)
+		})
+	}
+}
+
+func TestLabels_RoundTrip(t *testing.T) {
+	labels := types.Labels{"path": `a,b=c\d`, "host": "web 01"}
+
+	parsed, err := types.ParseLabels(labels.String())
+	require.NoError(t, err)
+	assert.Equal(t, labels, parsed)
+}
+
+func TestLabels_ValueScan(t *testing.T) {
+	value, err := types.Labels{"host": "web01"}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "host=web01", value)
+
+	var labels types.Labels
+	require.NoError(t, labels.Scan("host=web01"))
+	assert.Equal(t, types.Labels{"host": "web01"}, labels)
+
+	require.NoError(t, labels.Scan([]byte("")))
+	assert.Nil(t, labels)
+
+	require.NoError(t, labels.Scan(nil))
+	assert.Nil(t, labels)
+
+	assert.Error(t, labels.Scan(42))
+}
+
+func TestMetrics_MetricID(t *testing.T) {
+	a := types.Metrics{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"host": "web01"}}
+	b := types.Metrics{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"host": "web02"}}
+	c := types.Metrics{ID: "Alloc", MType: types.Gauge}
+
+	assert.Equal(t, types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "host=web01"}, a.MetricID())
+	assert.NotEqual(t, a.MetricID(), b.MetricID())
+	assert.Equal(t, types.MetricID{ID: "Alloc", MType: types.Gauge}, c.MetricID())
+}
+
+func TestMetricID_JSON(t *testing.T) {
+	id := types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "host=web01"}
+
+	data, err := json.Marshal(id)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"Alloc","type":"gauge","labels":{"host":"web01"}}`, string(data))
+
+	data, err = json.Marshal(types.MetricID{ID: "Alloc", MType: types.Gauge})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"Alloc","type":"gauge"}`, string(data))
+
+	var decoded types.MetricID
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"Alloc","type":"gauge","labels":{"region":"eu","host":"web01"}}`), &decoded))
+	assert.Equal(t, types.MetricID{ID: "Alloc", MType: types.Gauge, Labels: "host=web01,region=eu"}, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"id":"Alloc","type":"gauge","extra":1}`), &decoded))
+}
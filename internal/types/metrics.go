@@ -3,20 +3,35 @@ package types
 import (
 	"errors"
 	"fmt"
+	"html"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 const (
-	Counter = "counter"
-	Gauge   = "gauge"
+	Counter   = "counter"
+	Gauge     = "gauge"
+	Histogram = "histogram"
+	Summary   = "summary"
+	Set       = "set"
 )
 
 type Metrics struct {
-	ID    string   `json:"id"`
-	MType string   `json:"type"`
-	Delta *int64   `json:"delta,omitempty"`
-	Value *float64 `json:"value,omitempty"`
+	ID        string          `json:"id"`
+	MType     string          `json:"type"`
+	Labels    Labels          `json:"labels,omitempty"`
+	Delta     *int64          `json:"delta,omitempty"`
+	Value     *float64        `json:"value,omitempty"`
+	Histogram *HistogramValue `json:"histogram,omitempty"`
+	Summary   *SummaryValue   `json:"summary,omitempty"`
+	Members   []string        `json:"members,omitempty"`
+	Set       *SetValue       `json:"set,omitempty"`
+}
+
+func (m Metrics) MetricID() MetricID {
+	return MetricID{ID: m.ID, MType: m.MType, Labels: m.Labels.String()}
 }
 
 func NewMetrics(metricType string, metricName string, metricValue string) *Metrics {
@@ -41,14 +56,26 @@ func NewMetrics(metricType string, metricName string, metricValue string) *Metri
 		} else {
 			metric.Delta = &val
 		}
+
+	case Summary:
+		val, err := strconv.ParseFloat(metricValue, 64)
+		if err != nil {
+			metric.Value = nil
+		} else {
+			metric.Value = &val
+		}
+
+	case Set:
+		metric.Members = []string{metricValue}
 	}
 
 	return metric
 }
 
 type MetricID struct {
-	ID    string `json:"id"`
-	MType string `json:"type"`
+	ID     string
+	MType  string
+	Labels string
 }
 
 var (
@@ -76,31 +103,167 @@ func GetMetricValueString(metric Metrics) (string, error) {
 			return "", ErrNilMetricValue
 		}
 		return strconv.FormatFloat(*metric.Value, 'f', -1, 64), nil
+	case Histogram:
+		if metric.Histogram == nil {
+			return "", ErrNilMetricValue
+		}
+		return metric.Histogram.String(), nil
+	case Summary:
+		if metric.Summary == nil {
+			return "", ErrNilMetricValue
+		}
+		return metric.Summary.String(), nil
+	case Set:
+		if metric.Set == nil {
+			return "", ErrNilMetricValue
+		}
+		return metric.Set.String(), nil
 	default:
 		return "", ErrUnknownMType
 	}
 }
 
+func GetMetricFloatValue(metric Metrics) (float64, error) {
+	switch metric.MType {
+	case Counter:
+		if metric.Delta == nil {
+			return 0, ErrNilMetricValue
+		}
+		return float64(*metric.Delta), nil
+	case Gauge:
+		if metric.Value == nil {
+			return 0, ErrNilMetricValue
+		}
+		return *metric.Value, nil
+	default:
+		return 0, ErrUnknownMType
+	}
+}
+
+func GetMetricQuantile(metric Metrics, q float64) (float64, error) {
+	switch metric.MType {
+	case Histogram:
+		if metric.Histogram == nil {
+			return 0, ErrNilMetricValue
+		}
+		return metric.Histogram.Quantile(q)
+	case Summary:
+		if metric.Summary == nil {
+			return 0, ErrNilMetricValue
+		}
+		return metric.Summary.Quantile(q)
+	default:
+		return 0, ErrUnknownMType
+	}
+}
+
+func MergeMetrics(a, b Metrics) (Metrics, error) {
+	merged := a
+	switch a.MType {
+	case Counter:
+		if a.Delta == nil || b.Delta == nil {
+			return Metrics{}, ErrNilMetricValue
+		}
+		delta := *a.Delta + *b.Delta
+		merged.Delta = &delta
+	case Gauge:
+		if a.Value == nil || b.Value == nil {
+			return Metrics{}, ErrNilMetricValue
+		}
+		value := math.Max(*a.Value, *b.Value)
+		merged.Value = &value
+	case Histogram:
+		if a.Histogram == nil || b.Histogram == nil {
+			return Metrics{}, ErrNilMetricValue
+		}
+		histogram, err := a.Histogram.Merge(*b.Histogram)
+		if err != nil {
+			return Metrics{}, err
+		}
+		merged.Histogram = &histogram
+	case Summary:
+		if a.Summary == nil || b.Summary == nil {
+			return Metrics{}, ErrNilMetricValue
+		}
+		summary, err := a.Summary.Merge(*b.Summary)
+		if err != nil {
+			return Metrics{}, err
+		}
+		merged.Summary = &summary
+	case Set:
+		if a.Set == nil || b.Set == nil {
+			return Metrics{}, ErrNilMetricValue
+		}
+		set, err := a.Set.Merge(*b.Set)
+		if err != nil {
+			return Metrics{}, err
+		}
+		merged.Set = &set
+	default:
+		return Metrics{}, ErrUnknownMType
+	}
+	return merged, nil
+}
+
 var (
 	ErrInternalServerError = errors.New("internal server error")
 )
 
 func GetMetricsHTML(metricsList []Metrics) (string, error) {
-	var builder strings.Builder
+	var (
+		builder   strings.Builder
+		common    []Metrics
+		instances []string
+	)
+	grouped := make(map[string][]Metrics)
+
+	for _, metric := range metricsList {
+		instance, ok := metric.Labels[InstanceLabel]
+		if !ok {
+			common = append(common, metric)
+			continue
+		}
+		if _, seen := grouped[instance]; !seen {
+			instances = append(instances, instance)
+		}
+		labels := make(Labels, len(metric.Labels))
+		for k, v := range metric.Labels {
+			if k != InstanceLabel {
+				labels[k] = v
+			}
+		}
+		metric.Labels = labels
+		grouped[instance] = append(grouped[instance], metric)
+	}
+	sort.Strings(instances)
 
 	builder.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
 	builder.WriteString("<meta charset=\"UTF-8\">\n<title>Metrics</title>\n")
-	builder.WriteString("</head>\n<body>\n<h1>Metrics</h1>\n<ul>\n")
+	builder.WriteString("</head>\n<body>\n<h1>Metrics</h1>\n")
 
+	writeMetricsHTMLList(&builder, common)
+	for _, instance := range instances {
+		builder.WriteString("<h2>" + html.EscapeString(instance) + "</h2>\n")
+		writeMetricsHTMLList(&builder, grouped[instance])
+	}
+
+	builder.WriteString("</body>\n</html>")
+	return builder.String(), nil
+}
+
+func writeMetricsHTMLList(builder *strings.Builder, metricsList []Metrics) {
+	builder.WriteString("<ul>\n")
 	for _, metric := range metricsList {
 		valueStr, err := GetMetricValueString(metric)
 		if err != nil {
 			continue
 		}
-		line := fmt.Sprintf("<li>%s (%s): %s</li>\n", metric.ID, metric.MType, valueStr)
+		name := metric.ID
+		if labels := metric.Labels.String(); labels != "" {
+			name += "{" + labels + "}"
+		}
+		line := fmt.Sprintf("<li>%s (%s): %s</li>\n", html.EscapeString(name), metric.MType, valueStr)
 		builder.WriteString(line)
 	}
-
-	builder.WriteString("</ul>\n</body>\n</html>")
-	return builder.String(), nil
+	builder.WriteString("</ul>\n")
 }
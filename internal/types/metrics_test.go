@@ -1,10 +1,13 @@
 package types_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sbilibin2017/yp-metrics/internal/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewMetrics(t *testing.T) {
@@ -136,3 +139,114 @@ func TestGetMetricsHTML(t *testing.T) {
 	assert.Contains(t, html, "<li>metric2 (counter): 5</li>")
 	assert.NotContains(t, html, "metric3") // nil value skipped
 }
+
+func TestGetMetricsHTML_GroupedByInstance(t *testing.T) {
+	v1, v2, v3 := 1.0, 2.0, 3.0
+
+	metrics := []types.Metrics{
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{types.InstanceLabel: "web02"}, Value: &v2},
+		{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{types.InstanceLabel: "web01", "region": "eu"}, Value: &v1},
+		{ID: "Shared", MType: types.Gauge, Value: &v3},
+	}
+
+	html, err := types.GetMetricsHTML(metrics)
+	assert.NoError(t, err)
+	assert.Contains(t, html, "<ul>\n<li>Shared (gauge): 3</li>\n</ul>")
+	assert.Contains(t, html, "<h2>web01</h2>\n<ul>\n<li>Alloc{region=eu} (gauge): 1</li>\n</ul>")
+	assert.Contains(t, html, "<h2>web02</h2>\n<ul>\n<li>Alloc (gauge): 2</li>\n</ul>")
+	// группы инстансов идут после общих метрик в алфавитном порядке
+	assert.Less(t, strings.Index(html, "Shared"), strings.Index(html, "<h2>web01</h2>"))
+	assert.Less(t, strings.Index(html, "<h2>web01</h2>"), strings.Index(html, "<h2>web02</h2>"))
+	assert.NotContains(t, html, types.InstanceLabel+"=")
+}
+
+func TestGetMetricFloatValue(t *testing.T) {
+	gaugeVal := 3.14
+	counterVal := int64(10)
+
+	tests := []struct {
+		name    string
+		metric  types.Metrics
+		want    float64
+		wantErr error
+	}{
+		{
+			name:   "valid gauge",
+			metric: types.Metrics{MType: types.Gauge, Value: &gaugeVal},
+			want:   3.14,
+		},
+		{
+			name:   "valid counter",
+			metric: types.Metrics{MType: types.Counter, Delta: &counterVal},
+			want:   10,
+		},
+		{
+			name:    "nil gauge value",
+			metric:  types.Metrics{MType: types.Gauge},
+			wantErr: types.ErrNilMetricValue,
+		},
+		{
+			name:    "nil counter value",
+			metric:  types.Metrics{MType: types.Counter},
+			wantErr: types.ErrNilMetricValue,
+		},
+		{
+			name:    "unknown type",
+			metric:  types.Metrics{MType: "unknown"},
+			wantErr: types.ErrUnknownMType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := types.GetMetricFloatValue(tt.metric)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMergeMetrics(t *testing.T) {
+	delta := func(v int64) *int64 { return &v }
+	value := func(v float64) *float64 { return &v }
+
+	counter, err := types.MergeMetrics(
+		types.Metrics{ID: "PollCount", MType: types.Counter, Delta: delta(2)},
+		types.Metrics{ID: "PollCount", MType: types.Counter, Delta: delta(3)},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), *counter.Delta)
+
+	a := types.Metrics{ID: "Alloc", MType: types.Gauge, Value: value(1.5)}
+	gauge, err := types.MergeMetrics(a, types.Metrics{ID: "Alloc", MType: types.Gauge, Value: value(2)})
+	require.NoError(t, err)
+	// для gauge берётся максимум, а не сумма
+	assert.Equal(t, 2.0, *gauge.Value)
+	// исходная метрика не меняется
+	assert.Equal(t, 1.5, *a.Value)
+
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	setA := types.NewSetValue([]string{"alice", "bob"}, start)
+	setB := types.NewSetValue([]string{"bob", "carol"}, start)
+	set, err := types.MergeMetrics(
+		types.Metrics{ID: "users", MType: types.Set, Set: &setA},
+		types.Metrics{ID: "users", MType: types.Set, Set: &setB},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), set.Set.Cardinality())
+
+	summaryA := types.NewSummaryValue(1)
+	summaryB := types.NewSummaryValue(3)
+	summary, err := types.MergeMetrics(
+		types.Metrics{ID: "latency", MType: types.Summary, Summary: &summaryA},
+		types.Metrics{ID: "latency", MType: types.Summary, Summary: &summaryB},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), summary.Summary.Count)
+
+	_, err = types.MergeMetrics(types.Metrics{MType: types.Gauge, Value: value(1)}, types.Metrics{MType: types.Gauge})
+	assert.ErrorIs(t, err, types.ErrNilMetricValue)
+
+	_, err = types.MergeMetrics(types.Metrics{MType: "unknown"}, types.Metrics{MType: "unknown"})
+	assert.ErrorIs(t, err, types.ErrUnknownMType)
+}
@@ -0,0 +1,284 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+type OTLPPoint struct {
+	Metrics    Metrics
+	Series     string
+	StartTime  uint64
+	Cumulative bool
+	Total      float64
+}
+
+type OTLPRejected struct {
+	Count   int64
+	Reasons []string
+}
+
+func (r *OTLPRejected) add(count int, reason string) {
+	r.Count += int64(count)
+	r.Reasons = append(r.Reasons, reason)
+}
+
+func ParseOTLPRequest(req *colmetricspb.ExportMetricsServiceRequest) ([]OTLPPoint, OTLPRejected) {
+	var (
+		points   []OTLPPoint
+		rejected OTLPRejected
+	)
+
+	for _, rm := range req.GetResourceMetrics() {
+		resourceAttrs := rm.GetResource().GetAttributes()
+		resource := otlpAttributesKey(resourceAttrs)
+
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				name := m.GetName()
+				if name == "" {
+					rejected.add(otlpDataPointCount(m), "metric name is required")
+					continue
+				}
+
+				switch data := m.GetData().(type) {
+				case *metricspb.Metric_Gauge:
+					for _, dp := range data.Gauge.GetDataPoints() {
+						value, ok := otlpNumberValue(dp)
+						if !ok {
+							rejected.add(1, fmt.Sprintf("%s: invalid data point value", name))
+							continue
+						}
+						points = append(points, OTLPPoint{
+							Metrics: Metrics{ID: name, MType: Gauge, Labels: otlpLabels(resourceAttrs, dp.GetAttributes()), Value: &value},
+							Series:  otlpSeriesKey(name, resource, dp.GetAttributes()),
+						})
+					}
+				case *metricspb.Metric_Sum:
+					points = append(points, parseOTLPSum(name, resourceAttrs, resource, data.Sum, &rejected)...)
+				default:
+					rejected.add(otlpDataPointCount(m), fmt.Sprintf("%s: unsupported metric type", name))
+				}
+			}
+		}
+	}
+
+	return points, rejected
+}
+
+func parseOTLPSum(name string, resourceAttrs []*commonpb.KeyValue, resource string, sum *metricspb.Sum, rejected *OTLPRejected) []OTLPPoint {
+	var points []OTLPPoint
+
+	for _, dp := range sum.GetDataPoints() {
+		value, ok := otlpNumberValue(dp)
+		if !ok {
+			rejected.add(1, fmt.Sprintf("%s: invalid data point value", name))
+			continue
+		}
+
+		series := otlpSeriesKey(name, resource, dp.GetAttributes())
+		labels := otlpLabels(resourceAttrs, dp.GetAttributes())
+
+		if !sum.GetIsMonotonic() {
+			points = append(points, OTLPPoint{
+				Metrics: Metrics{ID: name, MType: Gauge, Labels: labels, Value: &value},
+				Series:  series,
+			})
+			continue
+		}
+
+		if value < 0 {
+			rejected.add(1, fmt.Sprintf("%s: negative monotonic sum", name))
+			continue
+		}
+
+		switch sum.GetAggregationTemporality() {
+		case metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA:
+			delta := int64(math.Round(value))
+			points = append(points, OTLPPoint{
+				Metrics: Metrics{ID: name, MType: Counter, Labels: labels, Delta: &delta},
+				Series:  series,
+			})
+		case metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE:
+			points = append(points, OTLPPoint{
+				Metrics:    Metrics{ID: name, MType: Counter, Labels: labels},
+				Series:     series,
+				StartTime:  dp.GetStartTimeUnixNano(),
+				Cumulative: true,
+				Total:      value,
+			})
+		default:
+			rejected.add(1, fmt.Sprintf("%s: unspecified aggregation temporality", name))
+		}
+	}
+
+	return points
+}
+
+func otlpNumberValue(dp *metricspb.NumberDataPoint) (float64, bool) {
+	switch v := dp.GetValue().(type) {
+	case *metricspb.NumberDataPoint_AsInt:
+		return float64(v.AsInt), true
+	case *metricspb.NumberDataPoint_AsDouble:
+		if math.IsNaN(v.AsDouble) || math.IsInf(v.AsDouble, 0) {
+			return 0, false
+		}
+		return v.AsDouble, true
+	default:
+		return 0, false
+	}
+}
+
+func otlpDataPointCount(m *metricspb.Metric) int {
+	switch data := m.GetData().(type) {
+	case *metricspb.Metric_Gauge:
+		return len(data.Gauge.GetDataPoints())
+	case *metricspb.Metric_Sum:
+		return len(data.Sum.GetDataPoints())
+	case *metricspb.Metric_Histogram:
+		return len(data.Histogram.GetDataPoints())
+	case *metricspb.Metric_ExponentialHistogram:
+		return len(data.ExponentialHistogram.GetDataPoints())
+	case *metricspb.Metric_Summary:
+		return len(data.Summary.GetDataPoints())
+	default:
+		return 0
+	}
+}
+
+func otlpLabels(resourceAttrs []*commonpb.KeyValue, pointAttrs []*commonpb.KeyValue) Labels {
+	labels := make(Labels, len(resourceAttrs)+len(pointAttrs))
+	for _, attrs := range [][]*commonpb.KeyValue{resourceAttrs, pointAttrs} {
+		for _, kv := range attrs {
+			if value := otlpAnyValueString(kv.GetValue()); kv.GetKey() != "" && value != "" {
+				labels[kv.GetKey()] = value
+			}
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+func otlpSeriesKey(name string, resource string, attrs []*commonpb.KeyValue) string {
+	return name + "|" + resource + "|" + otlpAttributesKey(attrs)
+}
+
+func otlpAttributesKey(attrs []*commonpb.KeyValue) string {
+	pairs := make([]string, 0, len(attrs))
+	for _, kv := range attrs {
+		pairs = append(pairs, kv.GetKey()+"="+otlpAnyValueString(kv.GetValue()))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func otlpAnyValueString(v *commonpb.AnyValue) string {
+	switch val := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return val.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return fmt.Sprint(val.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return fmt.Sprint(val.IntValue)
+	case *commonpb.AnyValue_DoubleValue:
+		return fmt.Sprint(val.DoubleValue)
+	default:
+		return v.String()
+	}
+}
+
+const OTLPCumulativeSeriesTTL = 30 * time.Minute
+
+type otlpCumulativeState struct {
+	startTime uint64
+	total     int64
+	seen      time.Time
+}
+
+type otlpCumulativeSeries struct {
+	mu      sync.Mutex
+	state   otlpCumulativeState
+	removed bool
+}
+
+type OTLPCumulativeTracker struct {
+	mu     sync.Mutex
+	series map[string]*otlpCumulativeSeries
+	ttl    time.Duration
+	swept  time.Time
+}
+
+func NewOTLPCumulativeTracker(ttl time.Duration) *OTLPCumulativeTracker {
+	return &OTLPCumulativeTracker{series: make(map[string]*otlpCumulativeSeries), ttl: ttl}
+}
+
+func (t *OTLPCumulativeTracker) Apply(p OTLPPoint, update func(delta int64) error) error {
+	series := t.lock(p.Series)
+	defer series.mu.Unlock()
+
+	now := time.Now()
+	total := int64(math.Round(p.Total))
+	prev := series.state
+
+	var delta int64
+	switch {
+	case prev.seen.IsZero() || now.Sub(prev.seen) >= t.ttl:
+	case prev.startTime != p.StartTime || total < prev.total:
+		delta = total
+	default:
+		delta = total - prev.total
+	}
+
+	if err := update(delta); err != nil {
+		return err
+	}
+
+	series.state = otlpCumulativeState{startTime: p.StartTime, total: total, seen: now}
+	return nil
+}
+
+func (t *OTLPCumulativeTracker) lock(name string) *otlpCumulativeSeries {
+	for {
+		t.mu.Lock()
+		t.expire(time.Now())
+		series, ok := t.series[name]
+		if !ok {
+			series = &otlpCumulativeSeries{}
+			t.series[name] = series
+		}
+		t.mu.Unlock()
+
+		series.mu.Lock()
+		if !series.removed {
+			return series
+		}
+		series.mu.Unlock()
+	}
+}
+
+func (t *OTLPCumulativeTracker) expire(now time.Time) {
+	if now.Sub(t.swept) < t.ttl {
+		return
+	}
+	for name, series := range t.series {
+		if !series.mu.TryLock() {
+			continue
+		}
+		if now.Sub(series.state.seen) >= t.ttl {
+			series.removed = true
+			delete(t.series, name)
+		}
+		series.mu.Unlock()
+	}
+	t.swept = now
+}
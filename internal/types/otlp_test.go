@@ -0,0 +1,202 @@
+package types_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+func otlpRequest(host string, metrics ...*metricspb.Metric) *colmetricspb.ExportMetricsServiceRequest {
+	return &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{{
+				Key:   "host.name",
+				Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: host}},
+			}}},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{Metrics: metrics}},
+		}},
+	}
+}
+
+func otlpSum(name string, monotonic bool, temporality metricspb.AggregationTemporality, points ...*metricspb.NumberDataPoint) *metricspb.Metric {
+	return &metricspb.Metric{Name: name, Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+		IsMonotonic:            monotonic,
+		AggregationTemporality: temporality,
+		DataPoints:             points,
+	}}}
+}
+
+func intPoint(v int64) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{Value: &metricspb.NumberDataPoint_AsInt{AsInt: v}}
+}
+
+func doublePoint(v float64) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{Value: &metricspb.NumberDataPoint_AsDouble{AsDouble: v}}
+}
+
+func TestParseOTLPRequest(t *testing.T) {
+	req := otlpRequest("web01",
+		&metricspb.Metric{Name: "cpu.utilization", Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{
+			DataPoints: []*metricspb.NumberDataPoint{doublePoint(0.42)},
+		}}},
+		otlpSum("http.requests", true, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, intPoint(5)),
+		otlpSum("jobs.total", true, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, intPoint(10)),
+		otlpSum("queue.size", false, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, intPoint(-3)),
+		&metricspb.Metric{Name: "latency", Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+			DataPoints: []*metricspb.HistogramDataPoint{{}, {}},
+		}}},
+		otlpSum("", true, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, intPoint(1)),
+		otlpSum("bad", true, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_DELTA, intPoint(-1), &metricspb.NumberDataPoint{}),
+	)
+
+	points, rejected := types.ParseOTLPRequest(req)
+
+	require.Len(t, points, 4)
+
+	assert.Equal(t, "cpu.utilization", points[0].Metrics.ID)
+	assert.Equal(t, types.Gauge, points[0].Metrics.MType)
+	assert.Equal(t, 0.42, *points[0].Metrics.Value)
+	assert.Equal(t, types.Labels{"host.name": "web01"}, points[0].Metrics.Labels)
+
+	assert.Equal(t, types.Counter, points[1].Metrics.MType)
+	assert.Equal(t, int64(5), *points[1].Metrics.Delta)
+	assert.False(t, points[1].Cumulative)
+
+	assert.True(t, points[2].Cumulative)
+	assert.Equal(t, 10.0, points[2].Total)
+	assert.Nil(t, points[2].Metrics.Delta)
+
+	assert.Equal(t, types.Gauge, points[3].Metrics.MType)
+	assert.Equal(t, -3.0, *points[3].Metrics.Value)
+
+	// гистограмма (2 точки), метрика без имени и две некорректные точки
+	assert.Equal(t, int64(5), rejected.Count)
+	assert.Len(t, rejected.Reasons, 4)
+}
+
+func TestParseOTLPRequest_SeriesIncludesResource(t *testing.T) {
+	sum := func() *metricspb.Metric {
+		return otlpSum("jobs.total", true, metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, intPoint(1))
+	}
+
+	a, _ := types.ParseOTLPRequest(otlpRequest("web01", sum()))
+	b, _ := types.ParseOTLPRequest(otlpRequest("web02", sum()))
+
+	require.Len(t, a, 1)
+	require.Len(t, b, 1)
+	assert.NotEqual(t, a[0].Series, b[0].Series)
+}
+
+func TestOTLPCumulativeTracker_Apply(t *testing.T) {
+	tracker := types.NewOTLPCumulativeTracker(time.Hour)
+	point := func(series string, start uint64, total float64) types.OTLPPoint {
+		return types.OTLPPoint{Series: series, StartTime: start, Cumulative: true, Total: total}
+	}
+	apply := func(p types.OTLPPoint) int64 {
+		var got int64
+		require.NoError(t, tracker.Apply(p, func(delta int64) error {
+			got = delta
+			return nil
+		}))
+		return got
+	}
+
+	// первая точка ряда только задаёт базу
+	assert.Equal(t, int64(0), apply(point("a", 1, 10)))
+	assert.Equal(t, int64(5), apply(point("a", 1, 15)))
+	assert.Equal(t, int64(0), apply(point("a", 1, 15)))
+	// сброс счётчика: значение уменьшилось
+	assert.Equal(t, int64(3), apply(point("a", 1, 3)))
+	// перезапуск источника: новое время начала
+	assert.Equal(t, int64(4), apply(point("a", 2, 4)))
+	// другие ряды считаются независимо
+	assert.Equal(t, int64(0), apply(point("b", 1, 7)))
+
+	// неудачная запись не сдвигает базу
+	err := tracker.Apply(point("a", 2, 10), func(delta int64) error {
+		assert.Equal(t, int64(6), delta)
+		return errors.New("fail")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, int64(6), apply(point("a", 2, 10)))
+}
+
+func TestOTLPCumulativeTracker_Expire(t *testing.T) {
+	tracker := types.NewOTLPCumulativeTracker(50 * time.Millisecond)
+	point := types.OTLPPoint{Series: "a", StartTime: 1, Cumulative: true, Total: 10}
+
+	require.NoError(t, tracker.Apply(point, func(int64) error { return nil }))
+
+	// ряд без точек дольше TTL забывается и снова начинается с базы
+	time.Sleep(100 * time.Millisecond)
+	point.Total = 25
+	require.NoError(t, tracker.Apply(point, func(delta int64) error {
+		assert.Equal(t, int64(0), delta)
+		return nil
+	}))
+}
+
+func TestOTLPCumulativeTracker_Concurrent(t *testing.T) {
+	tracker := types.NewOTLPCumulativeTracker(time.Hour)
+	point := func(series string, total float64) types.OTLPPoint {
+		return types.OTLPPoint{Series: series, StartTime: 1, Cumulative: true, Total: total}
+	}
+	require.NoError(t, tracker.Apply(point("a", 0), func(int64) error { return nil }))
+
+	// медленная запись одного ряда не блокирует другие ряды
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- tracker.Apply(point("slow", 1), func(int64) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	applied := make(chan error, 1)
+	go func() {
+		applied <- tracker.Apply(point("b", 1), func(int64) error { return nil })
+	}()
+	select {
+	case err := <-applied:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("apply blocked by another series")
+	}
+	close(release)
+	require.NoError(t, <-done)
+
+	// одна и та же точка, пришедшая одновременно, учитывается один раз
+	var (
+		mu  sync.Mutex
+		sum int64
+		wg  sync.WaitGroup
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, tracker.Apply(point("a", 10), func(delta int64) error {
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				sum += delta
+				mu.Unlock()
+				return nil
+			}))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(10), sum)
+}
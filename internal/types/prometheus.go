@@ -0,0 +1,207 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	PrometheusContentType  = "text/plain; version=0.0.4; charset=utf-8"
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+var prometheusSummaryQuantiles = []float64{0.5, 0.9, 0.99}
+
+func SanitizePrometheusName(name string) string {
+	var builder strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			builder.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	if builder.Len() == 0 {
+		return "_"
+	}
+	return builder.String()
+}
+
+func GetMetricsPrometheus(metricsList []Metrics, openMetrics bool) (string, error) {
+	sorted := make([]Metrics, len(metricsList))
+	copy(sorted, metricsList)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].ID != sorted[j].ID {
+			return sorted[i].ID < sorted[j].ID
+		}
+		if sorted[i].MType != sorted[j].MType {
+			return sorted[i].MType < sorted[j].MType
+		}
+		return sorted[i].Labels.String() < sorted[j].Labels.String()
+	})
+
+	var builder strings.Builder
+	families := make(map[string]struct{}, len(sorted))
+
+	var (
+		name   string
+		sample string
+		prev   *Metrics
+	)
+
+	for i, metric := range sorted {
+		if err := checkPrometheusValue(metric); err != nil {
+			continue
+		}
+
+		if prev == nil || prev.ID != metric.ID || prev.MType != metric.MType {
+			name = SanitizePrometheusName(metric.ID)
+			if openMetrics && metric.MType == Counter {
+				name = strings.TrimSuffix(name, "_total")
+			}
+			if _, ok := families[name]; ok {
+				name += "_" + metric.MType
+			}
+			families[name] = struct{}{}
+
+			sample = name
+			if openMetrics && metric.MType == Counter {
+				sample += "_total"
+			}
+
+			builder.WriteString(fmt.Sprintf("# HELP %s %s\n", name, escapePrometheusHelp(fmt.Sprintf("Metric %s of type %s.", metric.ID, metric.MType))))
+			builder.WriteString(fmt.Sprintf("# TYPE %s %s\n", name, getPrometheusType(metric.MType)))
+		}
+		prev = &sorted[i]
+
+		writePrometheusSamples(&builder, sample, metric)
+	}
+
+	if openMetrics {
+		builder.WriteString("# EOF\n")
+	}
+
+	return builder.String(), nil
+}
+
+func getPrometheusLabelsString(labels Labels, extra ...string) string {
+	if len(extra) > 0 {
+		merged := make(Labels, len(labels)+len(extra)/2)
+		for k, v := range labels {
+			merged[k] = v
+		}
+		for i := 0; i+1 < len(extra); i += 2 {
+			merged[extra[i]] = extra[i+1]
+		}
+		labels = merged
+	}
+
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		name := strings.ReplaceAll(SanitizePrometheusName(k), ":", "_")
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", name, escapePrometheusLabelValue(labels[k])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func getPrometheusType(mtype string) string {
+	if mtype == Set {
+		return Gauge
+	}
+	return mtype
+}
+
+func checkPrometheusValue(metric Metrics) error {
+	var present bool
+	switch metric.MType {
+	case Counter:
+		present = metric.Delta != nil
+	case Gauge:
+		present = metric.Value != nil
+	case Histogram:
+		present = metric.Histogram != nil && len(metric.Histogram.Counts) == len(metric.Histogram.Bounds)+1
+	case Summary:
+		present = metric.Summary != nil
+	case Set:
+		present = metric.Set != nil
+	default:
+		return ErrUnknownMType
+	}
+	if !present {
+		return ErrNilMetricValue
+	}
+	return nil
+}
+
+func writePrometheusSamples(builder *strings.Builder, sample string, metric Metrics) {
+	writeSample := func(name string, labels string, value string) {
+		builder.WriteString(fmt.Sprintf("%s%s %s\n", name, labels, value))
+	}
+	labels := getPrometheusLabelsString(metric.Labels)
+
+	switch metric.MType {
+	case Counter:
+		writeSample(sample, labels, strconv.FormatInt(*metric.Delta, 10))
+	case Gauge:
+		writeSample(sample, labels, formatPrometheusFloat(*metric.Value))
+	case Set:
+		writeSample(sample, labels, strconv.FormatUint(metric.Set.Cardinality(), 10))
+	case Histogram:
+		h := metric.Histogram
+		var cumulative int64
+		for i, bound := range h.Bounds {
+			cumulative += h.Counts[i]
+			le := getPrometheusLabelsString(metric.Labels, "le", formatPrometheusFloat(bound))
+			writeSample(sample+"_bucket", le, strconv.FormatInt(cumulative, 10))
+		}
+		cumulative += h.Counts[len(h.Bounds)]
+		writeSample(sample+"_bucket", getPrometheusLabelsString(metric.Labels, "le", "+Inf"), strconv.FormatInt(cumulative, 10))
+		writeSample(sample+"_sum", labels, formatPrometheusFloat(h.Sum))
+		writeSample(sample+"_count", labels, strconv.FormatInt(cumulative, 10))
+	case Summary:
+		s := metric.Summary
+		for _, q := range prometheusSummaryQuantiles {
+			value, err := s.Quantile(q)
+			if err != nil {
+				continue
+			}
+			quantile := getPrometheusLabelsString(metric.Labels, "quantile", formatPrometheusFloat(q))
+			writeSample(sample, quantile, formatPrometheusFloat(value))
+		}
+		writeSample(sample+"_sum", labels, formatPrometheusFloat(s.Sum))
+		writeSample(sample+"_count", labels, strconv.FormatInt(s.Count, 10))
+	}
+}
+
+func formatPrometheusFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapePrometheusHelp(help string) string {
+	help = strings.ReplaceAll(help, `\`, `\\`)
+	return strings.ReplaceAll(help, "\n", `\n`)
+}
+
+func escapePrometheusLabelValue(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
@@ -0,0 +1,124 @@
+package types_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizePrometheusName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"HeapAlloc", "HeapAlloc"},
+		{"http.requests-total", "http_requests_total"},
+		{"1st", "_1st"},
+		{"ns:metric_1", "ns:metric_1"},
+		{"метрика", "_______"},
+		{"", "_"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, types.SanitizePrometheusName(tt.in))
+	}
+}
+
+func TestGetMetricsPrometheus(t *testing.T) {
+	gaugeVal := 1.5
+	nan := math.NaN()
+	counterVal := int64(7)
+
+	metrics := []types.Metrics{
+		{ID: "PollCount", MType: types.Counter, Delta: &counterVal},
+		{ID: "heap.alloc", MType: types.Gauge, Value: &gaugeVal},
+		{ID: "PollCount", MType: types.Gauge, Value: &nan},
+		{ID: "broken", MType: types.Gauge},
+	}
+
+	t.Run("text format", func(t *testing.T) {
+		out, err := types.GetMetricsPrometheus(metrics, false)
+		assert.NoError(t, err)
+		assert.Equal(t, ""+
+			"# HELP PollCount Metric PollCount of type counter.\n"+
+			"# TYPE PollCount counter\n"+
+			"PollCount 7\n"+
+			"# HELP PollCount_gauge Metric PollCount of type gauge.\n"+
+			"# TYPE PollCount_gauge gauge\n"+
+			"PollCount_gauge NaN\n"+
+			"# HELP heap_alloc Metric heap.alloc of type gauge.\n"+
+			"# TYPE heap_alloc gauge\n"+
+			"heap_alloc 1.5\n", out)
+	})
+
+	t.Run("openmetrics format", func(t *testing.T) {
+		out, err := types.GetMetricsPrometheus(metrics[:2], true)
+		assert.NoError(t, err)
+		assert.Equal(t, ""+
+			"# HELP PollCount Metric PollCount of type counter.\n"+
+			"# TYPE PollCount counter\n"+
+			"PollCount_total 7\n"+
+			"# HELP heap_alloc Metric heap.alloc of type gauge.\n"+
+			"# TYPE heap_alloc gauge\n"+
+			"heap_alloc 1.5\n"+
+			"# EOF\n", out)
+	})
+
+	t.Run("openmetrics counter already suffixed", func(t *testing.T) {
+		total := int64(3)
+		out, err := types.GetMetricsPrometheus([]types.Metrics{{ID: "requests_total", MType: types.Counter, Delta: &total}}, true)
+		assert.NoError(t, err)
+		assert.Contains(t, out, "# TYPE requests counter\n")
+		assert.Contains(t, out, "requests_total 3\n")
+	})
+
+	t.Run("labeled series share a family", func(t *testing.T) {
+		a, b, c := 1.0, 2.0, 3.0
+		out, err := types.GetMetricsPrometheus([]types.Metrics{
+			{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"host": "web02"}, Value: &b},
+			{ID: "Alloc", MType: types.Gauge, Value: &c},
+			{ID: "Alloc", MType: types.Gauge, Labels: types.Labels{"host": "web01", "service.name": `say "hi"`}, Value: &a},
+		}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, ""+
+			"# HELP Alloc Metric Alloc of type gauge.\n"+
+			"# TYPE Alloc gauge\n"+
+			"Alloc 3\n"+
+			"Alloc{host=\"web01\",service_name=\"say \\\"hi\\\"\"} 1\n"+
+			"Alloc{host=\"web02\"} 2\n", out)
+	})
+
+	t.Run("histogram summary and set", func(t *testing.T) {
+		summary := types.NewSummaryValue(2)
+		set := types.NewSetValue([]string{"alice", "bob"}, time.Unix(0, 0))
+		out, err := types.GetMetricsPrometheus([]types.Metrics{
+			{ID: "latency", MType: types.Histogram, Labels: types.Labels{"host": "web01"}, Histogram: &types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{1, 2, 3}, Sum: 7.5}},
+			{ID: "rt", MType: types.Summary, Summary: &summary},
+			{ID: "users", MType: types.Set, Set: &set},
+			{ID: "broken", MType: types.Histogram},
+		}, false)
+		assert.NoError(t, err)
+		// бакеты гистограммы накопительные, последний — +Inf
+		assert.Equal(t, ""+
+			"# HELP latency Metric latency of type histogram.\n"+
+			"# TYPE latency histogram\n"+
+			"latency_bucket{host=\"web01\",le=\"0.1\"} 1\n"+
+			"latency_bucket{host=\"web01\",le=\"1\"} 3\n"+
+			"latency_bucket{host=\"web01\",le=\"+Inf\"} 6\n"+
+			"latency_sum{host=\"web01\"} 7.5\n"+
+			"latency_count{host=\"web01\"} 6\n"+
+			"# HELP rt Metric rt of type summary.\n"+
+			"# TYPE rt summary\n"+
+			"rt{quantile=\"0.5\"} 2\n"+
+			"rt{quantile=\"0.9\"} 2\n"+
+			"rt{quantile=\"0.99\"} 2\n"+
+			"rt_sum 2\n"+
+			"rt_count 1\n"+
+			"# HELP users Metric users of type set.\n"+
+			"# TYPE users gauge\n"+
+			"users 2\n", out)
+	})
+}
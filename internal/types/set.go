@@ -0,0 +1,136 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"strconv"
+	"time"
+)
+
+const SetPrecision = 12
+
+var (
+	ErrInvalidSet           = errors.New("invalid set")
+	ErrSetPrecisionMismatch = errors.New("set precision does not match")
+)
+
+type SetValue struct {
+	Precision uint8     `json:"precision"`
+	Registers []byte    `json:"registers"`
+	Start     time.Time `json:"start"`
+}
+
+func NewSetValue(members []string, start time.Time) SetValue {
+	s := SetValue{
+		Precision: SetPrecision,
+		Registers: make([]byte, 1<<SetPrecision),
+		Start:     start,
+	}
+	for _, member := range members {
+		s.add(member)
+	}
+	return s
+}
+
+func (s SetValue) Merge(other SetValue) (SetValue, error) {
+	if s.Precision != other.Precision {
+		return SetValue{}, ErrSetPrecisionMismatch
+	}
+	if len(s.Registers) != 1<<s.Precision || len(other.Registers) != len(s.Registers) {
+		return SetValue{}, fmt.Errorf("%w: unexpected number of registers", ErrInvalidSet)
+	}
+
+	merged := SetValue{
+		Precision: s.Precision,
+		Registers: append([]byte(nil), s.Registers...),
+		Start:     s.Start,
+	}
+	if merged.Start.IsZero() || (!other.Start.IsZero() && other.Start.Before(merged.Start)) {
+		merged.Start = other.Start
+	}
+	for i, r := range other.Registers {
+		if r > merged.Registers[i] {
+			merged.Registers[i] = r
+		}
+	}
+	return merged, nil
+}
+
+func (s SetValue) Expired(window time.Duration, now time.Time) bool {
+	return window > 0 && !now.Truncate(window).Equal(s.Start.Truncate(window))
+}
+
+func (s SetValue) Cardinality() uint64 {
+	m := float64(len(s.Registers))
+	if m == 0 {
+		return 0
+	}
+
+	var (
+		sum   float64
+		zeros int
+	)
+	for _, r := range s.Registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
+func (s SetValue) String() string {
+	return strconv.FormatUint(s.Cardinality(), 10)
+}
+
+func (s SetValue) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (s *SetValue) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidSet, src)
+	}
+	return json.Unmarshal(data, s)
+}
+
+func (s *SetValue) add(member string) {
+	h := fnv.New64a()
+	h.Write([]byte(member))
+	x := mixSetHash(h.Sum64())
+
+	index := x >> (64 - s.Precision)
+	rank := uint8(bits.LeadingZeros64(x<<s.Precision|1<<(s.Precision-1)) + 1)
+	if rank > s.Registers[index] {
+		s.Registers[index] = rank
+	}
+}
+
+func mixSetHash(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,73 @@
+package types_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetValue_Cardinality(t *testing.T) {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, uint64(0), types.NewSetValue(nil, start).Cardinality())
+	assert.Equal(t, uint64(3), types.NewSetValue([]string{"alice", "bob", "carol", "alice"}, start).Cardinality())
+
+	for _, n := range []int{1000, 100000} {
+		members := make([]string, n)
+		for i := range members {
+			members[i] = "user-" + strconv.Itoa(i)
+		}
+		got := types.NewSetValue(members, start).Cardinality()
+		assert.InEpsilon(t, n, got, 0.05, "n=%d", n)
+	}
+}
+
+func TestSetValue_Merge(t *testing.T) {
+	earlier := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+
+	a := types.NewSetValue([]string{"alice", "bob"}, later)
+	b := types.NewSetValue([]string{"bob", "carol"}, earlier)
+
+	merged, err := a.Merge(b)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), merged.Cardinality())
+	assert.Equal(t, earlier, merged.Start)
+	assert.Equal(t, "3", merged.String())
+
+	// исходный скетч не меняется
+	assert.Equal(t, uint64(2), a.Cardinality())
+
+	_, err = a.Merge(types.SetValue{Precision: 10, Registers: make([]byte, 1<<10)})
+	assert.ErrorIs(t, err, types.ErrSetPrecisionMismatch)
+
+	_, err = a.Merge(types.SetValue{Precision: types.SetPrecision, Registers: []byte{1}})
+	assert.ErrorIs(t, err, types.ErrInvalidSet)
+}
+
+func TestSetValue_Expired(t *testing.T) {
+	start := time.Date(2025, 7, 1, 10, 15, 0, 0, time.UTC)
+	set := types.NewSetValue([]string{"alice"}, start)
+
+	assert.False(t, set.Expired(0, start.Add(24*time.Hour)))
+	assert.False(t, set.Expired(time.Hour, start.Add(30*time.Minute)))
+	// окна выровнены по границам, а не по времени первого обновления
+	assert.True(t, set.Expired(time.Hour, start.Add(45*time.Minute)))
+}
+
+func TestSetValue_ValueScan(t *testing.T) {
+	set := types.NewSetValue([]string{"alice", "bob"}, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+
+	value, err := set.Value()
+	require.NoError(t, err)
+
+	var scanned types.SetValue
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, set, scanned)
+
+	assert.ErrorIs(t, scanned.Scan(42), types.ErrInvalidSet)
+}
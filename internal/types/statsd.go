@@ -0,0 +1,57 @@
+package types
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidStatsDLine       = errors.New("invalid statsd line")
+	ErrUnsupportedStatsDType   = errors.New("unsupported statsd metric type")
+	ErrInvalidStatsDSampleRate = errors.New("invalid statsd sample rate")
+)
+
+func ParseStatsDLine(line string) (Metrics, error) {
+	name, rest, ok := strings.Cut(strings.TrimSpace(line), ":")
+	if !ok || name == "" {
+		return Metrics{}, ErrInvalidStatsDLine
+	}
+
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 || parts[0] == "" {
+		return Metrics{}, ErrInvalidStatsDLine
+	}
+
+	value, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return Metrics{}, ErrInvalidStatsDLine
+	}
+
+	sampleRate := 1.0
+	for _, tag := range parts[2:] {
+		if !strings.HasPrefix(tag, "@") {
+			continue
+		}
+		sampleRate, err = strconv.ParseFloat(tag[1:], 64)
+		if err != nil || sampleRate <= 0 || sampleRate > 1 {
+			return Metrics{}, ErrInvalidStatsDSampleRate
+		}
+	}
+
+	switch parts[1] {
+	case "c":
+		delta := int64(math.Round(value / sampleRate))
+		return Metrics{ID: name, MType: Counter, Delta: &delta}, nil
+	case "g":
+		if strings.HasPrefix(parts[0], "+") || strings.HasPrefix(parts[0], "-") {
+			return Metrics{}, ErrUnsupportedStatsDType
+		}
+		return Metrics{ID: name, MType: Gauge, Value: &value}, nil
+	case "ms", "h":
+		return Metrics{ID: name, MType: Summary, Value: &value}, nil
+	default:
+		return Metrics{}, ErrUnsupportedStatsDType
+	}
+}
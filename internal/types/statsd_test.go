@@ -0,0 +1,48 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatsDLine(t *testing.T) {
+	delta := func(v int64) *int64 { return &v }
+	value := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name     string
+		line     string
+		expected types.Metrics
+		wantErr  error
+	}{
+		{"counter", "requests:1|c", types.Metrics{ID: "requests", MType: types.Counter, Delta: delta(1)}, nil},
+		{"counter with sample rate", "requests:2|c|@0.5", types.Metrics{ID: "requests", MType: types.Counter, Delta: delta(4)}, nil},
+		{"counter with tags", "requests:3|c|#env:prod", types.Metrics{ID: "requests", MType: types.Counter, Delta: delta(3)}, nil},
+		{"gauge", "temperature:3.2|g", types.Metrics{ID: "temperature", MType: types.Gauge, Value: value(3.2)}, nil},
+		{"timer", "latency:320|ms", types.Metrics{ID: "latency", MType: types.Summary, Value: value(320)}, nil},
+		{"timer with sample rate", "latency:12.5|ms|@0.1", types.Metrics{ID: "latency", MType: types.Summary, Value: value(12.5)}, nil},
+		{"histogram alias", "size:10|h", types.Metrics{ID: "size", MType: types.Summary, Value: value(10)}, nil},
+		{"missing colon", "requests1|c", types.Metrics{}, types.ErrInvalidStatsDLine},
+		{"missing name", ":1|c", types.Metrics{}, types.ErrInvalidStatsDLine},
+		{"missing type", "requests:1", types.Metrics{}, types.ErrInvalidStatsDLine},
+		{"bad value", "requests:abc|c", types.Metrics{}, types.ErrInvalidStatsDLine},
+		{"bad sample rate", "requests:1|c|@2", types.Metrics{}, types.ErrInvalidStatsDSampleRate},
+		{"relative gauge", "temperature:+1|g", types.Metrics{}, types.ErrUnsupportedStatsDType},
+		{"set", "users:42|s", types.Metrics{}, types.ErrUnsupportedStatsDType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := types.ParseStatsDLine(tt.line)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
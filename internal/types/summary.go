@@ -0,0 +1,191 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+const (
+	SummaryRelativeAccuracy = 0.01
+	summaryMinIndexable     = 1e-9
+)
+
+var (
+	ErrInvalidSummary          = errors.New("invalid summary")
+	ErrSummaryAccuracyMismatch = errors.New("summary relative accuracy does not match")
+)
+
+type SummaryValue struct {
+	Alpha    float64       `json:"alpha"`
+	Count    int64         `json:"count"`
+	Sum      float64       `json:"sum"`
+	Min      float64       `json:"min"`
+	Max      float64       `json:"max"`
+	Zero     int64         `json:"zero,omitempty"`
+	Positive map[int]int64 `json:"positive,omitempty"`
+	Negative map[int]int64 `json:"negative,omitempty"`
+}
+
+func NewSummaryValue(observation float64) SummaryValue {
+	s := SummaryValue{
+		Alpha: SummaryRelativeAccuracy,
+		Count: 1,
+		Sum:   observation,
+		Min:   observation,
+		Max:   observation,
+	}
+
+	switch {
+	case math.Abs(observation) < summaryMinIndexable:
+		s.Zero = 1
+	case observation > 0:
+		s.Positive = map[int]int64{s.key(observation): 1}
+	default:
+		s.Negative = map[int]int64{s.key(-observation): 1}
+	}
+
+	return s
+}
+
+func (s SummaryValue) Clone() SummaryValue {
+	clone := s
+	clone.Positive = mergeSummaryBins(s.Positive, nil)
+	clone.Negative = mergeSummaryBins(s.Negative, nil)
+	return clone
+}
+
+func (s SummaryValue) Merge(other SummaryValue) (SummaryValue, error) {
+	if s.Alpha != other.Alpha {
+		return SummaryValue{}, ErrSummaryAccuracyMismatch
+	}
+
+	merged := SummaryValue{
+		Alpha:    s.Alpha,
+		Count:    s.Count + other.Count,
+		Sum:      s.Sum + other.Sum,
+		Min:      s.Min,
+		Max:      s.Max,
+		Zero:     s.Zero + other.Zero,
+		Positive: mergeSummaryBins(s.Positive, other.Positive),
+		Negative: mergeSummaryBins(s.Negative, other.Negative),
+	}
+	switch {
+	case s.Count == 0:
+		merged.Min, merged.Max = other.Min, other.Max
+	case other.Count > 0:
+		merged.Min = math.Min(s.Min, other.Min)
+		merged.Max = math.Max(s.Max, other.Max)
+	}
+
+	return merged, nil
+}
+
+func (s SummaryValue) Quantile(q float64) (float64, error) {
+	if math.IsNaN(q) || q < 0 || q > 1 {
+		return 0, ErrInvalidQuantile
+	}
+
+	switch {
+	case s.Count == 0:
+		return math.NaN(), nil
+	case q == 0:
+		return s.Min, nil
+	case q == 1:
+		return s.Max, nil
+	}
+
+	rank := q * float64(s.Count-1)
+	var cumulative int64
+
+	negative := sortedSummaryKeys(s.Negative)
+	for i := len(negative) - 1; i >= 0; i-- {
+		cumulative += s.Negative[negative[i]]
+		if float64(cumulative) > rank {
+			return s.clamp(-s.estimate(negative[i])), nil
+		}
+	}
+
+	cumulative += s.Zero
+	if float64(cumulative) > rank {
+		return s.clamp(0), nil
+	}
+
+	for _, k := range sortedSummaryKeys(s.Positive) {
+		cumulative += s.Positive[k]
+		if float64(cumulative) > rank {
+			return s.clamp(s.estimate(k)), nil
+		}
+	}
+
+	return s.Max, nil
+}
+
+func (s SummaryValue) String() string {
+	return "count=" + strconv.FormatInt(s.Count, 10) + " sum=" + strconv.FormatFloat(s.Sum, 'f', -1, 64)
+}
+
+func (s SummaryValue) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (s *SummaryValue) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidSummary, src)
+	}
+	return json.Unmarshal(data, s)
+}
+
+func (s SummaryValue) gamma() float64 {
+	return (1 + s.Alpha) / (1 - s.Alpha)
+}
+
+func (s SummaryValue) key(v float64) int {
+	return int(math.Ceil(math.Log(v) / math.Log(s.gamma())))
+}
+
+func (s SummaryValue) estimate(key int) float64 {
+	gamma := s.gamma()
+	return 2 * math.Pow(gamma, float64(key)) / (gamma + 1)
+}
+
+func (s SummaryValue) clamp(v float64) float64 {
+	return math.Max(s.Min, math.Min(s.Max, v))
+}
+
+func mergeSummaryBins(a, b map[int]int64) map[int]int64 {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	merged := make(map[int]int64, len(a)+len(b))
+	for k, c := range a {
+		merged[k] += c
+	}
+	for k, c := range b {
+		merged[k] += c
+	}
+	return merged
+}
+
+func sortedSummaryKeys(bins map[int]int64) []int {
+	keys := make([]int, 0, len(bins))
+	for k := range bins {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
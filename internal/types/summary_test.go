@@ -0,0 +1,114 @@
+package types_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildSummary(t *testing.T, observations []float64) types.SummaryValue {
+	t.Helper()
+
+	summary := types.NewSummaryValue(observations[0])
+	for _, v := range observations[1:] {
+		var err error
+		summary, err = summary.Merge(types.NewSummaryValue(v))
+		require.NoError(t, err)
+	}
+	return summary
+}
+
+func TestSummaryValue_QuantileAccuracy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	observations := make([]float64, 10000)
+	for i := range observations {
+		observations[i] = rnd.ExpFloat64() * 100
+	}
+
+	summary := buildSummary(t, observations)
+
+	sorted := append([]float64(nil), observations...)
+	sort.Float64s(sorted)
+
+	for _, q := range []float64{0.5, 0.9, 0.95, 0.99} {
+		got, err := summary.Quantile(q)
+		require.NoError(t, err)
+		expected := sorted[int(q*float64(len(sorted)-1))]
+		assert.InEpsilon(t, expected, got, types.SummaryRelativeAccuracy*1.01, "q=%v", q)
+	}
+
+	minimum, err := summary.Quantile(0)
+	require.NoError(t, err)
+	assert.Equal(t, sorted[0], minimum)
+
+	maximum, err := summary.Quantile(1)
+	require.NoError(t, err)
+	assert.Equal(t, sorted[len(sorted)-1], maximum)
+
+	assert.Equal(t, int64(len(observations)), summary.Count)
+}
+
+func TestSummaryValue_NegativeAndZero(t *testing.T) {
+	summary := buildSummary(t, []float64{-10, -1, 0, 1, 10})
+
+	median, err := summary.Quantile(0.5)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, median)
+
+	low, err := summary.Quantile(0.25)
+	require.NoError(t, err)
+	assert.InEpsilon(t, -1, low, types.SummaryRelativeAccuracy)
+
+	high, err := summary.Quantile(0.75)
+	require.NoError(t, err)
+	assert.InEpsilon(t, 1, high, types.SummaryRelativeAccuracy)
+}
+
+func TestSummaryValue_Merge(t *testing.T) {
+	a := buildSummary(t, []float64{1, 2, 3})
+	b := buildSummary(t, []float64{4, 5})
+
+	merged, err := a.Merge(b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), merged.Count)
+	assert.Equal(t, 15.0, merged.Sum)
+	assert.Equal(t, 1.0, merged.Min)
+	assert.Equal(t, 5.0, merged.Max)
+	assert.Equal(t, "count=5 sum=15", merged.String())
+
+	// слияние не меняет исходные скетчи
+	assert.Equal(t, int64(3), a.Count)
+	assert.Len(t, a.Positive, 3)
+
+	_, err = a.Merge(types.SummaryValue{Alpha: 0.05})
+	assert.ErrorIs(t, err, types.ErrSummaryAccuracyMismatch)
+}
+
+func TestSummaryValue_Quantile_Errors(t *testing.T) {
+	summary := types.NewSummaryValue(1)
+
+	_, err := summary.Quantile(1.1)
+	assert.ErrorIs(t, err, types.ErrInvalidQuantile)
+
+	empty, err := types.SummaryValue{Alpha: types.SummaryRelativeAccuracy}.Quantile(0.5)
+	require.NoError(t, err)
+	assert.True(t, math.IsNaN(empty))
+}
+
+func TestSummaryValue_ValueScan(t *testing.T) {
+	summary := buildSummary(t, []float64{0.5, 2})
+
+	value, err := summary.Value()
+	require.NoError(t, err)
+
+	var scanned types.SummaryValue
+	require.NoError(t, scanned.Scan([]byte(value.(string))))
+	assert.Equal(t, summary, scanned)
+
+	assert.ErrorIs(t, scanned.Scan(42), types.ErrInvalidSummary)
+}
@@ -0,0 +1,61 @@
+package validators
+
+import (
+	"errors"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+var (
+	ErrAlertNameIsRequired    = errors.New("alert rule name is required")
+	ErrInvalidAlertKind       = errors.New("invalid alert rule kind")
+	ErrInvalidAlertMetricType = errors.New("alert rule metric must be a counter or gauge")
+	ErrInvalidAlertOperator   = errors.New("invalid alert rule operator")
+	ErrInvalidAlertFor        = errors.New("alert rule duration must not be negative")
+	ErrAlertAbsenceNeedsFor   = errors.New("absence alert rule requires a positive duration")
+	ErrDuplicateAlertRuleName = errors.New("duplicate alert rule name")
+)
+
+func ValidateAlertRule(rule types.AlertRule) error {
+	if rule.Name == "" {
+		return ErrAlertNameIsRequired
+	}
+	if err := ValidateMetricIDPath(rule.Metric.MType, rule.Metric.ID); err != nil {
+		return err
+	}
+	if rule.Metric.MType != types.Counter && rule.Metric.MType != types.Gauge {
+		return ErrInvalidAlertMetricType
+	}
+	if rule.For < 0 {
+		return ErrInvalidAlertFor
+	}
+
+	switch rule.Kind {
+	case types.AlertThreshold, types.AlertRate:
+		if _, err := types.CompareAlertThreshold(rule.Operator, 0, 0); err != nil {
+			return ErrInvalidAlertOperator
+		}
+	case types.AlertAbsence:
+		if rule.For == 0 {
+			return ErrAlertAbsenceNeedsFor
+		}
+	default:
+		return ErrInvalidAlertKind
+	}
+
+	return nil
+}
+
+func ValidateAlertRules(rules []types.AlertRule) error {
+	names := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		if err := ValidateAlertRule(rule); err != nil {
+			return err
+		}
+		if _, ok := names[rule.Name]; ok {
+			return ErrDuplicateAlertRuleName
+		}
+		names[rule.Name] = struct{}{}
+	}
+	return nil
+}
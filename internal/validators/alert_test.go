@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAlertRule(t *testing.T) {
+	heap := types.MetricID{ID: "HeapAlloc", MType: types.Gauge}
+
+	tests := []struct {
+		name    string
+		rule    types.AlertRule
+		wantErr error
+	}{
+		{"valid threshold", types.AlertRule{Name: "a", Metric: heap, Kind: types.AlertThreshold, Operator: ">", Threshold: 1}, nil},
+		{"valid rate", types.AlertRule{Name: "a", Metric: heap, Kind: types.AlertRate, Operator: "<", Threshold: 0}, nil},
+		{"valid absence", types.AlertRule{Name: "a", Metric: heap, Kind: types.AlertAbsence, For: 60}, nil},
+		{"missing name", types.AlertRule{Metric: heap, Kind: types.AlertThreshold, Operator: ">"}, ErrAlertNameIsRequired},
+		{"missing metric name", types.AlertRule{Name: "a", Metric: types.MetricID{MType: types.Gauge}, Kind: types.AlertThreshold, Operator: ">"}, ErrNameIsRequired},
+		{"invalid metric type", types.AlertRule{Name: "a", Metric: types.MetricID{ID: "x", MType: "bad"}, Kind: types.AlertThreshold, Operator: ">"}, ErrInvalidMetricType},
+		{"histogram metric", types.AlertRule{Name: "a", Metric: types.MetricID{ID: "x", MType: types.Histogram}, Kind: types.AlertThreshold, Operator: ">"}, ErrInvalidAlertMetricType},
+		{"summary metric", types.AlertRule{Name: "a", Metric: types.MetricID{ID: "x", MType: types.Summary}, Kind: types.AlertRate, Operator: ">"}, ErrInvalidAlertMetricType},
+		{"set metric", types.AlertRule{Name: "a", Metric: types.MetricID{ID: "x", MType: types.Set}, Kind: types.AlertAbsence, For: 60}, ErrInvalidAlertMetricType},
+		{"invalid kind", types.AlertRule{Name: "a", Metric: heap, Kind: "bad"}, ErrInvalidAlertKind},
+		{"invalid operator", types.AlertRule{Name: "a", Metric: heap, Kind: types.AlertThreshold, Operator: "~"}, ErrInvalidAlertOperator},
+		{"negative for", types.AlertRule{Name: "a", Metric: heap, Kind: types.AlertThreshold, Operator: ">", For: -1}, ErrInvalidAlertFor},
+		{"absence without for", types.AlertRule{Name: "a", Metric: heap, Kind: types.AlertAbsence}, ErrAlertAbsenceNeedsFor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, ValidateAlertRule(tt.rule))
+		})
+	}
+}
+
+func TestValidateAlertRules(t *testing.T) {
+	rule := types.AlertRule{
+		Name:     "high",
+		Metric:   types.MetricID{ID: "HeapAlloc", MType: types.Gauge},
+		Kind:     types.AlertThreshold,
+		Operator: ">",
+	}
+
+	assert.NoError(t, ValidateAlertRules([]types.AlertRule{rule}))
+	assert.Equal(t, ErrDuplicateAlertRuleName, ValidateAlertRules([]types.AlertRule{rule, rule}))
+	assert.Equal(t, ErrAlertNameIsRequired, ValidateAlertRules([]types.AlertRule{{}}))
+}
@@ -0,0 +1,48 @@
+package validators
+
+import (
+	"errors"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+var (
+	ErrCollectorNameIsRequired = errors.New("collector name is required")
+	ErrCollectorSource         = errors.New("collector requires exactly one of exec or file")
+	ErrInvalidCollectorTimeout = errors.New("collector timeout must not be negative")
+	ErrInvalidCollectorPeriod  = errors.New("collector interval must not be negative")
+	ErrDuplicateCollectorName  = errors.New("duplicate collector name")
+)
+
+func ValidateCustomCollector(c types.CustomCollector) error {
+	if c.Name == "" {
+		return ErrCollectorNameIsRequired
+	}
+	if (len(c.Exec) > 0) == (c.File != "") {
+		return ErrCollectorSource
+	}
+	if len(c.Exec) > 0 && c.Exec[0] == "" {
+		return ErrCollectorSource
+	}
+	if c.Interval < 0 {
+		return ErrInvalidCollectorPeriod
+	}
+	if c.Timeout < 0 {
+		return ErrInvalidCollectorTimeout
+	}
+	return nil
+}
+
+func ValidateCustomCollectors(collectors []types.CustomCollector) error {
+	names := make(map[string]struct{}, len(collectors))
+	for _, c := range collectors {
+		if err := ValidateCustomCollector(c); err != nil {
+			return err
+		}
+		if _, ok := names[c.Name]; ok {
+			return ErrDuplicateCollectorName
+		}
+		names[c.Name] = struct{}{}
+	}
+	return nil
+}
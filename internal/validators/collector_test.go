@@ -0,0 +1,42 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCustomCollector(t *testing.T) {
+	exec := []string{"/bin/echo", "gauge x 1"}
+
+	tests := []struct {
+		name      string
+		collector types.CustomCollector
+		wantErr   error
+	}{
+		{"valid exec", types.CustomCollector{Name: "a", Exec: exec, Interval: 5, Timeout: 1}, nil},
+		{"valid file", types.CustomCollector{Name: "a", File: "/tmp/metrics"}, nil},
+		{"missing name", types.CustomCollector{Exec: exec}, ErrCollectorNameIsRequired},
+		{"no source", types.CustomCollector{Name: "a"}, ErrCollectorSource},
+		{"both sources", types.CustomCollector{Name: "a", Exec: exec, File: "/tmp/metrics"}, ErrCollectorSource},
+		{"empty command", types.CustomCollector{Name: "a", Exec: []string{""}}, ErrCollectorSource},
+		{"negative interval", types.CustomCollector{Name: "a", File: "/tmp/metrics", Interval: -1}, ErrInvalidCollectorPeriod},
+		{"negative timeout", types.CustomCollector{Name: "a", Exec: exec, Timeout: -1}, ErrInvalidCollectorTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, ValidateCustomCollector(tt.collector))
+		})
+	}
+}
+
+func TestValidateCustomCollectors(t *testing.T) {
+	c := types.CustomCollector{Name: "queue", File: "/tmp/metrics"}
+
+	assert.NoError(t, ValidateCustomCollectors(nil))
+	assert.NoError(t, ValidateCustomCollectors([]types.CustomCollector{c}))
+	assert.Equal(t, ErrDuplicateCollectorName, ValidateCustomCollectors([]types.CustomCollector{c, c}))
+	assert.Equal(t, ErrCollectorNameIsRequired, ValidateCustomCollectors([]types.CustomCollector{{File: "/tmp/metrics"}}))
+}
@@ -0,0 +1,22 @@
+package validators
+
+import (
+	"errors"
+	"unicode"
+)
+
+const maxInstanceIDLength = 255
+
+var ErrInvalidInstanceID = errors.New("invalid instance id")
+
+func ValidateInstanceID(instance string) error {
+	if len(instance) > maxInstanceIDLength {
+		return ErrInvalidInstanceID
+	}
+	for _, r := range instance {
+		if unicode.IsControl(r) {
+			return ErrInvalidInstanceID
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package validators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateInstanceID(t *testing.T) {
+	assert.NoError(t, ValidateInstanceID("web01"))
+	assert.NoError(t, ValidateInstanceID(strings.Repeat("a", 255)))
+	assert.ErrorIs(t, ValidateInstanceID(strings.Repeat("a", 256)), ErrInvalidInstanceID)
+	assert.ErrorIs(t, ValidateInstanceID("web\n01"), ErrInvalidInstanceID)
+}
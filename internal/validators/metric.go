@@ -2,6 +2,7 @@ package validators
 
 import (
 	"errors"
+	"math"
 	"strconv"
 
 	"github.com/sbilibin2017/yp-metrics/internal/types"
@@ -14,6 +15,10 @@ var (
 	ErrValueIsRequired     = errors.New("metric value is required")
 	ErrInvalidGaugeValue   = errors.New("invalid gauge metric value")
 	ErrInvalidCounterValue = errors.New("invalid counter metric value")
+	ErrInvalidLabel        = errors.New("label name and value must not be empty")
+	ErrInvalidHistogram    = errors.New("invalid histogram metric value")
+	ErrInvalidSummaryValue = errors.New("invalid summary metric value")
+	ErrInvalidSetMember    = errors.New("set member must not be empty")
 )
 
 func ValidateMetricIDPath(metricType, metricName string) error {
@@ -23,12 +28,25 @@ func ValidateMetricIDPath(metricType, metricName string) error {
 	if metricType == "" {
 		return ErrTypeIsRequired
 	}
-	if metricType != types.Gauge && metricType != types.Counter {
+	switch metricType {
+	case types.Gauge, types.Counter, types.Histogram, types.Summary, types.Set:
+	default:
 		return ErrInvalidMetricType
 	}
 	return nil
 }
 
+func ValidateMetricIDBody(id types.MetricID) error {
+	if err := ValidateMetricIDPath(id.MType, id.ID); err != nil {
+		return err
+	}
+	labels, err := types.ParseLabels(id.Labels)
+	if err != nil {
+		return ErrInvalidLabel
+	}
+	return ValidateLabels(labels)
+}
+
 func ValidateMetricPath(metricType, metricName, metricValue string) error {
 	if metricName == "" {
 		return ErrNameIsRequired
@@ -36,7 +54,9 @@ func ValidateMetricPath(metricType, metricName, metricValue string) error {
 	if metricType == "" {
 		return ErrTypeIsRequired
 	}
-	if metricType != types.Gauge && metricType != types.Counter {
+	switch metricType {
+	case types.Gauge, types.Counter, types.Summary, types.Set:
+	default:
 		return ErrInvalidMetricType
 	}
 	if metricValue == "" {
@@ -52,6 +72,11 @@ func ValidateMetricPath(metricType, metricName, metricValue string) error {
 		if _, err := strconv.ParseInt(metricValue, 10, 64); err != nil {
 			return ErrInvalidCounterValue
 		}
+	case types.Summary:
+		v, err := strconv.ParseFloat(metricValue, 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return ErrInvalidSummaryValue
+		}
 	}
 	return nil
 }
@@ -60,9 +85,14 @@ func ValidateMetricBody(m types.Metrics) error {
 	if m.ID == "" {
 		return ErrNameIsRequired
 	}
-	if m.MType != types.Gauge && m.MType != types.Counter {
+	switch m.MType {
+	case types.Gauge, types.Counter, types.Histogram, types.Summary, types.Set:
+	default:
 		return ErrInvalidMetricType
 	}
+	if err := ValidateLabels(m.Labels); err != nil {
+		return err
+	}
 	switch m.MType {
 	case types.Gauge:
 		if m.Value == nil {
@@ -72,6 +102,38 @@ func ValidateMetricBody(m types.Metrics) error {
 		if m.Delta == nil {
 			return ErrValueIsRequired
 		}
+	case types.Histogram:
+		if m.Histogram == nil {
+			return ErrValueIsRequired
+		}
+		if err := m.Histogram.Validate(); err != nil {
+			return ErrInvalidHistogram
+		}
+	case types.Summary:
+		if m.Value == nil {
+			return ErrValueIsRequired
+		}
+		if math.IsNaN(*m.Value) || math.IsInf(*m.Value, 0) {
+			return ErrInvalidSummaryValue
+		}
+	case types.Set:
+		if len(m.Members) == 0 {
+			return ErrValueIsRequired
+		}
+		for _, member := range m.Members {
+			if member == "" {
+				return ErrInvalidSetMember
+			}
+		}
+	}
+	return nil
+}
+
+func ValidateLabels(labels types.Labels) error {
+	for k, v := range labels {
+		if k == "" || v == "" {
+			return ErrInvalidLabel
+		}
 	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package validators
 
 import (
+	"math"
 	"testing"
 
 	"github.com/sbilibin2017/yp-metrics/internal/types"
@@ -15,6 +16,9 @@ func TestValidateMetricIDPath(t *testing.T) {
 	}{
 		{"gauge", "cpu", nil},
 		{"counter", "requests", nil},
+		{"histogram", "latency", nil},
+		{"summary", "rt", nil},
+		{"set", "users", nil},
 		{"", "cpu", ErrTypeIsRequired},
 		{"gauge", "", ErrNameIsRequired},
 		{"invalid", "cpu", ErrInvalidMetricType},
@@ -26,6 +30,27 @@ func TestValidateMetricIDPath(t *testing.T) {
 	}
 }
 
+func TestValidateMetricIDBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      types.MetricID
+		wantErr error
+	}{
+		{"valid", types.MetricID{ID: "cpu", MType: types.Gauge}, nil},
+		{"valid labels", types.MetricID{ID: "cpu", MType: types.Gauge, Labels: "host=a"}, nil},
+		{"missing name", types.MetricID{MType: types.Gauge}, ErrNameIsRequired},
+		{"invalid type", types.MetricID{ID: "cpu", MType: "invalid"}, ErrInvalidMetricType},
+		{"empty label value", types.MetricID{ID: "cpu", MType: types.Gauge, Labels: types.Labels{"host": ""}.String()}, ErrInvalidLabel},
+		{"empty label name", types.MetricID{ID: "cpu", MType: types.Gauge, Labels: types.Labels{"": "a"}.String()}, ErrInvalidLabel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, ValidateMetricIDBody(tt.id))
+		})
+	}
+}
+
 func TestValidateMetricPath(t *testing.T) {
 	tests := []struct {
 		metricType  string
@@ -41,6 +66,11 @@ func TestValidateMetricPath(t *testing.T) {
 		{"", "cpu", "1.23", ErrTypeIsRequired},
 		{"gauge", "cpu", "", ErrValueIsRequired},
 		{"invalid", "cpu", "1.23", ErrInvalidMetricType},
+		{"histogram", "latency", "1.23", ErrInvalidMetricType},
+		{"summary", "rt", "0.25", nil},
+		{"summary", "rt", "fast", ErrInvalidSummaryValue},
+		{"summary", "rt", "NaN", ErrInvalidSummaryValue},
+		{"set", "users", "alice", nil},
 	}
 
 	for _, tt := range tests {
@@ -52,6 +82,9 @@ func TestValidateMetricPath(t *testing.T) {
 func TestValidateMetricBody(t *testing.T) {
 	v := float64(1.23)
 	d := int64(10)
+	h := types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{1, 0, 2}, Sum: 3}
+	bad := types.HistogramValue{Bounds: []float64{0.1, 1}, Counts: []int64{1}}
+	inf := math.Inf(1)
 
 	tests := []struct {
 		m       types.Metrics
@@ -63,6 +96,18 @@ func TestValidateMetricBody(t *testing.T) {
 		{types.Metrics{ID: "cpu", MType: "invalid", Value: &v}, ErrInvalidMetricType},
 		{types.Metrics{ID: "cpu", MType: types.Gauge, Value: nil}, ErrValueIsRequired},
 		{types.Metrics{ID: "req", MType: types.Counter, Delta: nil}, ErrValueIsRequired},
+		{types.Metrics{ID: "cpu", MType: types.Gauge, Labels: types.Labels{"host": "web01"}, Value: &v}, nil},
+		{types.Metrics{ID: "cpu", MType: types.Gauge, Labels: types.Labels{"host": ""}, Value: &v}, ErrInvalidLabel},
+		{types.Metrics{ID: "cpu", MType: types.Gauge, Labels: types.Labels{"": "web01"}, Value: &v}, ErrInvalidLabel},
+		{types.Metrics{ID: "latency", MType: types.Histogram, Histogram: &h}, nil},
+		{types.Metrics{ID: "latency", MType: types.Histogram, Value: &v}, ErrValueIsRequired},
+		{types.Metrics{ID: "latency", MType: types.Histogram, Histogram: &bad}, ErrInvalidHistogram},
+		{types.Metrics{ID: "rt", MType: types.Summary, Value: &v}, nil},
+		{types.Metrics{ID: "rt", MType: types.Summary}, ErrValueIsRequired},
+		{types.Metrics{ID: "rt", MType: types.Summary, Value: &inf}, ErrInvalidSummaryValue},
+		{types.Metrics{ID: "users", MType: types.Set, Members: []string{"alice", "bob"}}, nil},
+		{types.Metrics{ID: "users", MType: types.Set}, ErrValueIsRequired},
+		{types.Metrics{ID: "users", MType: types.Set, Members: []string{"alice", ""}}, ErrInvalidSetMember},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,35 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+)
+
+type AlertEvaluator interface {
+	Evaluate(ctx context.Context) error
+}
+
+func StartAlertServerWorker(
+	ctx context.Context,
+	evaluator AlertEvaluator,
+	evaluateInterval int,
+) {
+	logger.Log.Infof("Starting alert evaluation every %d seconds", evaluateInterval)
+
+	ticker := time.NewTicker(time.Duration(evaluateInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("Alert evaluation stopped due to context cancellation")
+			return
+		case <-ticker.C:
+			if err := evaluator.Evaluate(ctx); err != nil {
+				logger.Log.Errorf("Failed to evaluate alert rules: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestStartAlertServerWorker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEvaluator := NewMockAlertEvaluator(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockEvaluator.EXPECT().Evaluate(gomock.Any()).Return(nil).Times(1)
+	mockEvaluator.EXPECT().Evaluate(gomock.Any()).Return(errors.New("eval error")).AnyTimes()
+
+	done := make(chan struct{})
+	go func() {
+		StartAlertServerWorker(ctx, mockEvaluator, 1)
+		close(done)
+	}()
+
+	time.Sleep(2100 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop after context cancellation")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"math/rand/v2"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/sbilibin2017/yp-metrics/internal/logger"
@@ -22,45 +23,76 @@ type MetricsUpdater interface {
 func StartMetricAgentWorker(
 	ctx context.Context,
 	metricsUpdater MetricsUpdater,
+	collectors []Collector,
 	pollInterval int,
 	reportInterval int,
+	rateLimit int,
+	pollIntervalCh <-chan int,
+	reportIntervalCh <-chan int,
 ) {
-	pollMetricsCh := pollMetrics(
-		ctx,
-		pollInterval,
-		[]func() []types.Metrics{
-			collectRuntimeCounterMetrics,
-			collectRuntimeGaugeMetrics,
-		},
-	)
+	shared := 0
+	for _, c := range collectors {
+		if c.Interval() <= 0 {
+			shared++
+		}
+	}
+	pollIntervalChs := fanOutIntervals(ctx, pollIntervalCh, shared)
+
+	metricsChs := make([]<-chan []types.Metrics, 0, len(collectors))
+	for _, c := range collectors {
+		interval := c.Interval()
+		var intervalCh <-chan int
+		if interval <= 0 {
+			interval = time.Duration(pollInterval) * time.Second
+			intervalCh = pollIntervalChs[0]
+			pollIntervalChs = pollIntervalChs[1:]
+		}
+		logger.Log.Infow("Starting collector", "name", c.Name(), "interval", interval)
+		metricsChs = append(metricsChs, pollMetrics(ctx, c, interval, intervalCh))
+	}
+
 	reportMetricsCh := reportMetrics(
 		ctx,
 		metricsUpdater,
 		reportInterval,
-		pollMetricsCh,
+		reportIntervalCh,
+		rateLimit,
+		mergeMetrics(metricsChs...),
 	)
-	logResults(ctx, reportMetricsCh)
+	logResults(reportMetricsCh)
 }
 
-func pollMetrics(ctx context.Context, pollInterval int, collectors []func() []types.Metrics) <-chan []types.Metrics {
+func pollMetrics(
+	ctx context.Context,
+	collector Collector,
+	interval time.Duration,
+	intervalCh <-chan int,
+) <-chan []types.Metrics {
 	metricsCh := make(chan []types.Metrics, 100)
 
 	go func() {
 		defer close(metricsCh)
-		ticker := time.NewTicker(time.Duration(pollInterval) * time.Second)
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		for {
 			select {
 			case <-ctx.Done():
-				logger.Log.Info("pollMetricsLoop stopped due to context cancellation")
+				logger.Log.Infow("pollMetricsLoop stopped due to context cancellation", "collector", collector.Name())
 				return
+			case interval := <-intervalCh:
+				logger.Log.Infof("Poll interval changed to %d seconds", interval)
+				ticker.Reset(time.Duration(interval) * time.Second)
 			case <-ticker.C:
-				for _, collector := range collectors {
-					metrics := collector()
-					logger.Log.Infof("Collected %d metrics", len(metrics))
-					metricsCh <- metrics
+				metrics, err := collector.Collect(ctx)
+				if err != nil {
+					logger.Log.Errorw("Collector failed", "collector", collector.Name(), "error", err)
 				}
+				if len(metrics) == 0 {
+					continue
+				}
+				logger.Log.Infow("Collected metrics", "collector", collector.Name(), "count", len(metrics))
+				metricsCh <- metrics
 			}
 		}
 	}()
@@ -118,47 +150,90 @@ func collectRuntimeCounterMetrics() []types.Metrics {
 	return metrics
 }
 
+var reportDrainTimeout = 5 * time.Second
+
 func reportMetrics(
 	ctx context.Context,
 	metricsUpdater MetricsUpdater,
 	reportInterval int,
+	reportIntervalCh <-chan int,
+	rateLimit int,
 	in <-chan []types.Metrics,
 ) <-chan metricsUpdateResult {
+	if rateLimit <= 0 {
+		rateLimit = 1
+	}
+
 	out := make(chan metricsUpdateResult, 100)
+	jobs := make(chan []types.Metrics)
+
+	sendCtx, cancelSend := context.WithCancel(context.WithoutCancel(ctx))
+
+	var wg sync.WaitGroup
+	for i := 0; i < rateLimit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				err := metricsUpdater.Updates(sendCtx, batch)
+				out <- metricsUpdateResult{Request: batch, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		cancelSend()
+		close(out)
+	}()
+
 	ticker := time.NewTicker(time.Duration(reportInterval) * time.Second)
 
 	go func() {
 		defer func() {
 			ticker.Stop()
-			close(out)
+			close(jobs)
 		}()
 
 		var buffer []types.Metrics
 
+		flush := func() {
+			if len(buffer) == 0 {
+				return
+			}
+			select {
+			case jobs <- aggregateMetrics(buffer):
+				buffer = nil
+			case <-sendCtx.Done():
+				logger.Log.Warnw("Report drain timed out, dropping batch", "size", len(buffer))
+			}
+		}
+
 		for {
 			select {
 			case <-ctx.Done():
-				if len(buffer) > 0 {
-					err := metricsUpdater.Updates(ctx, buffer)
-					out <- metricsUpdateResult{Request: buffer, Err: err}
-				}
+				time.AfterFunc(reportDrainTimeout, cancelSend)
+				flush()
 				return
-
 			case m, ok := <-in:
 				if !ok {
-					if len(buffer) > 0 {
-						err := metricsUpdater.Updates(ctx, buffer)
-						out <- metricsUpdateResult{Request: buffer, Err: err}
-					}
+					flush()
 					return
 				}
 				buffer = append(buffer, m...)
-
+			case interval := <-reportIntervalCh:
+				logger.Log.Infof("Report interval changed to %d seconds", interval)
+				ticker.Reset(time.Duration(interval) * time.Second)
 			case <-ticker.C:
-				if len(buffer) > 0 {
-					err := metricsUpdater.Updates(ctx, buffer)
-					out <- metricsUpdateResult{Request: buffer, Err: err}
-					buffer = buffer[:0]
+				if len(buffer) == 0 {
+					continue
+				}
+				buffer = aggregateMetrics(buffer)
+				select {
+				case jobs <- buffer:
+					buffer = nil
+				default:
+					logger.Log.Warnw("All report workers are busy, postponing batch", "size", len(buffer))
 				}
 			}
 		}
@@ -167,20 +242,147 @@ func reportMetrics(
 	return out
 }
 
-func logResults(ctx context.Context, results <-chan metricsUpdateResult) {
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case res, ok := <-results:
-			if !ok {
-				return
+func aggregateMetrics(metrics []types.Metrics) []types.Metrics {
+	index := make(map[types.MetricID]int, len(metrics))
+	result := make([]types.Metrics, 0, len(metrics))
+
+	for _, m := range metrics {
+		if m.MType == types.Summary {
+			result = append(result, m)
+			continue
+		}
+
+		key := m.MetricID()
+
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(result)
+			if m.MType == types.Counter && m.Delta != nil {
+				delta := *m.Delta
+				m.Delta = &delta
+			}
+			if m.Histogram != nil {
+				histogram := m.Histogram.Clone()
+				m.Histogram = &histogram
+			}
+			m.Members = unionMembers(nil, m.Members)
+			result = append(result, m)
+			continue
+		}
+
+		switch m.MType {
+		case types.Counter:
+			if m.Delta == nil {
+				continue
 			}
-			if res.Err != nil {
-				logger.Log.Errorf("Error: %v", res.Err)
-			} else {
-				logger.Log.Infof("Success")
+			if result[i].Delta == nil {
+				delta := *m.Delta
+				result[i].Delta = &delta
+				continue
 			}
+			*result[i].Delta += *m.Delta
+		case types.Histogram:
+			if m.Histogram == nil || result[i].Histogram == nil {
+				result[i] = m
+				continue
+			}
+			merged, err := result[i].Histogram.Merge(*m.Histogram)
+			if err != nil {
+				logger.Log.Warnw("Histogram bounds changed, keeping latest value", "id", m.ID, "error", err)
+				result[i] = m
+				continue
+			}
+			result[i].Histogram = &merged
+		case types.Set:
+			result[i].Members = unionMembers(result[i].Members, m.Members)
+		default:
+			result[i] = m
 		}
 	}
+
+	return result
+}
+
+func unionMembers(a, b []string) []string {
+	if len(b) == 0 {
+		return a
+	}
+
+	seen := make(map[string]struct{}, len(a)+len(b))
+	union := make([]string, 0, len(a)+len(b))
+	for _, members := range [][]string{a, b} {
+		for _, member := range members {
+			if _, ok := seen[member]; ok {
+				continue
+			}
+			seen[member] = struct{}{}
+			union = append(union, member)
+		}
+	}
+	return union
+}
+
+func logResults(results <-chan metricsUpdateResult) {
+	for res := range results {
+		if res.Err != nil {
+			logger.Log.Errorf("Error: %v", res.Err)
+		} else {
+			logger.Log.Infof("Success")
+		}
+	}
+}
+
+func mergeMetrics(inputs ...<-chan []types.Metrics) <-chan []types.Metrics {
+	out := make(chan []types.Metrics, 100)
+
+	var wg sync.WaitGroup
+	wg.Add(len(inputs))
+
+	for _, in := range inputs {
+		go func(in <-chan []types.Metrics) {
+			defer wg.Done()
+			for metrics := range in {
+				out <- metrics
+			}
+		}(in)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+func fanOutIntervals(ctx context.Context, in <-chan int, n int) []<-chan int {
+	outs := make([]<-chan int, n)
+	if in == nil {
+		return outs
+	}
+
+	chs := make([]chan int, n)
+	for i := range chs {
+		chs[i] = make(chan int, 1)
+		outs[i] = chs[i]
+	}
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case interval := <-in:
+				for _, ch := range chs {
+					select {
+					case <-ch:
+					default:
+					}
+					ch <- interval
+				}
+			}
+		}
+	}()
+
+	return outs
 }
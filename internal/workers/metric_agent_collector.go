@@ -0,0 +1,150 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+)
+
+var (
+	ErrCollectorExists  = errors.New("collector already registered")
+	ErrUnknownCollector = errors.New("unknown collector")
+)
+
+type Collector interface {
+	Name() string
+	Interval() time.Duration
+	Collect(ctx context.Context) ([]types.Metrics, error)
+}
+
+type FuncCollector struct {
+	name     string
+	interval time.Duration
+	funcs    []func() []types.Metrics
+}
+
+func NewFuncCollector(name string, interval time.Duration, funcs ...func() []types.Metrics) *FuncCollector {
+	return &FuncCollector{
+		name:     name,
+		interval: interval,
+		funcs:    funcs,
+	}
+}
+
+func (c *FuncCollector) Name() string {
+	return c.name
+}
+
+func (c *FuncCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *FuncCollector) Collect(ctx context.Context) ([]types.Metrics, error) {
+	var metrics []types.Metrics
+	for _, fn := range c.funcs {
+		metrics = append(metrics, fn()...)
+	}
+	return metrics, nil
+}
+
+func NewRuntimeCollector() *FuncCollector {
+	return NewFuncCollector("runtime", 0, collectRuntimeCounterMetrics, collectRuntimeGaugeMetrics)
+}
+
+func NewSystemCollector() *FuncCollector {
+	return NewFuncCollector("system", 0, newSystemGaugeCollector(readSystemMemory, readSystemCPU))
+}
+
+type intervalCollector struct {
+	Collector
+	interval time.Duration
+}
+
+func (c *intervalCollector) Interval() time.Duration {
+	return c.interval
+}
+
+type CollectorRegistry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+func NewCollectorRegistry() *CollectorRegistry {
+	return &CollectorRegistry{}
+}
+
+func (r *CollectorRegistry) Register(c Collector) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.collectors {
+		if existing.Name() == c.Name() {
+			return fmt.Errorf("%w: %s", ErrCollectorExists, c.Name())
+		}
+	}
+
+	r.collectors = append(r.collectors, c)
+
+	return nil
+}
+
+func (r *CollectorRegistry) Select(
+	enabled []string,
+	disabled []string,
+	intervals map[string]time.Duration,
+) ([]Collector, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	known := make(map[string]bool, len(r.collectors))
+	for _, c := range r.collectors {
+		known[c.Name()] = true
+	}
+
+	check := func(names []string) (map[string]bool, error) {
+		set := make(map[string]bool, len(names))
+		for _, name := range names {
+			if !known[name] {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownCollector, name)
+			}
+			set[name] = true
+		}
+		return set, nil
+	}
+
+	enabledSet, err := check(enabled)
+	if err != nil {
+		return nil, err
+	}
+
+	disabledSet, err := check(disabled)
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range intervals {
+		if !known[name] {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCollector, name)
+		}
+	}
+
+	var selected []Collector
+	for _, c := range r.collectors {
+		if len(enabledSet) > 0 && !enabledSet[c.Name()] {
+			continue
+		}
+		if disabledSet[c.Name()] {
+			continue
+		}
+		if interval, ok := intervals[c.Name()]; ok {
+			c = &intervalCollector{Collector: c, interval: interval}
+		}
+		selected = append(selected, c)
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,196 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gomock "github.com/golang/mock/gomock"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingCollector struct {
+	metrics []types.Metrics
+}
+
+func (c *failingCollector) Name() string {
+	return "failing"
+}
+
+func (c *failingCollector) Interval() time.Duration {
+	return 50 * time.Millisecond
+}
+
+func (c *failingCollector) Collect(ctx context.Context) ([]types.Metrics, error) {
+	return c.metrics, errors.New("collect failed")
+}
+
+func collectorNames(collectors []Collector) []string {
+	var names []string
+	for _, c := range collectors {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+func TestFuncCollector(t *testing.T) {
+	c := NewFuncCollector("custom", time.Second,
+		func() []types.Metrics { return []types.Metrics{{ID: "a", MType: types.Gauge}} },
+		func() []types.Metrics { return []types.Metrics{{ID: "b", MType: types.Gauge}} },
+	)
+
+	assert.Equal(t, "custom", c.Name())
+	assert.Equal(t, time.Second, c.Interval())
+
+	metrics, err := c.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, metrics, 2)
+}
+
+func TestBuiltinCollectors(t *testing.T) {
+	runtimeMetrics, err := NewRuntimeCollector().Collect(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, runtimeMetrics, 29)
+
+	assert.Equal(t, "system", NewSystemCollector().Name())
+	assert.Zero(t, NewSystemCollector().Interval())
+}
+
+func TestCollectorRegistry_Register(t *testing.T) {
+	r := NewCollectorRegistry()
+
+	require.NoError(t, r.Register(NewRuntimeCollector()))
+	err := r.Register(NewRuntimeCollector())
+	assert.ErrorIs(t, err, ErrCollectorExists)
+}
+
+func TestCollectorRegistry_Select(t *testing.T) {
+	r := NewCollectorRegistry()
+	require.NoError(t, r.Register(NewRuntimeCollector()))
+	require.NoError(t, r.Register(NewSystemCollector()))
+	require.NoError(t, r.Register(NewFuncCollector("queue", 5*time.Second)))
+
+	tests := []struct {
+		name      string
+		enabled   []string
+		disabled  []string
+		intervals map[string]time.Duration
+		expected  []string
+		err       error
+	}{
+		{
+			name:     "all by default",
+			expected: []string{"runtime", "system", "queue"},
+		},
+		{
+			name:     "only enabled",
+			enabled:  []string{"queue", "runtime"},
+			expected: []string{"runtime", "queue"},
+		},
+		{
+			name:     "disabled excluded",
+			disabled: []string{"system"},
+			expected: []string{"runtime", "queue"},
+		},
+		{
+			name:    "unknown enabled",
+			enabled: []string{"missing"},
+			err:     ErrUnknownCollector,
+		},
+		{
+			name:     "unknown disabled",
+			disabled: []string{"missing"},
+			err:      ErrUnknownCollector,
+		},
+		{
+			name:      "unknown interval",
+			intervals: map[string]time.Duration{"missing": time.Second},
+			err:       ErrUnknownCollector,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := r.Select(tt.enabled, tt.disabled, tt.intervals)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, collectorNames(selected))
+		})
+	}
+}
+
+func TestCollectorRegistry_SelectIntervalOverride(t *testing.T) {
+	r := NewCollectorRegistry()
+	require.NoError(t, r.Register(NewRuntimeCollector()))
+	require.NoError(t, r.Register(NewSystemCollector()))
+
+	selected, err := r.Select(nil, nil, map[string]time.Duration{"system": 30 * time.Second})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+
+	assert.Zero(t, selected[0].Interval())
+	assert.Equal(t, "system", selected[1].Name())
+	assert.Equal(t, 30*time.Second, selected[1].Interval())
+}
+
+func TestPollMetrics_CollectorError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// частичный результат доставляется, ошибка только логируется
+	c := &failingCollector{metrics: []types.Metrics{{ID: "partial", MType: types.Gauge, Value: float64PtrToStringPtr(1)}}}
+	outCh := pollMetrics(ctx, c, c.Interval(), nil)
+
+	select {
+	case batch := <-outCh:
+		require.Len(t, batch, 1)
+		assert.Equal(t, "partial", batch[0].ID)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for partial metrics")
+	}
+}
+
+func TestStartMetricAgentWorker_OwnCollectorInterval(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	totals := make(map[string]int64)
+	updater := NewMockMetricsUpdater(ctrl)
+	updater.EXPECT().Updates(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req []types.Metrics) error {
+			for _, m := range req {
+				totals[m.ID] += *m.Delta
+			}
+			return nil
+		},
+	).AnyTimes()
+	collectors := []Collector{
+		NewFuncCollector("fast", 100*time.Millisecond, func() []types.Metrics {
+			return []types.Metrics{{ID: "fast", MType: types.Counter, Delta: int64Ptr(1)}}
+		}),
+		NewFuncCollector("slow", 0, func() []types.Metrics {
+			return []types.Metrics{{ID: "slow", MType: types.Counter, Delta: int64Ptr(1)}}
+		}),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		StartMetricAgentWorker(ctx, updater, collectors, 60, 60, 1, nil, nil)
+		close(done)
+	}()
+
+	time.Sleep(550 * time.Millisecond)
+	cancel()
+	<-done
+
+	assert.GreaterOrEqual(t, totals["fast"], int64(4))
+	assert.Zero(t, totals["slow"])
+}
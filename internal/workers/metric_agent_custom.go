@@ -0,0 +1,144 @@
+package workers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/sbilibin2017/yp-metrics/internal/validators"
+)
+
+const defaultExecTimeout = 5 * time.Second
+
+var (
+	ErrInvalidMetricLine = errors.New("invalid metric line")
+	ErrCommandTimeout    = errors.New("collector command timed out")
+)
+
+type ExecCollector struct {
+	name     string
+	command  []string
+	interval time.Duration
+	timeout  time.Duration
+}
+
+func NewExecCollector(name string, command []string, interval time.Duration, timeout time.Duration) *ExecCollector {
+	if timeout <= 0 {
+		timeout = defaultExecTimeout
+	}
+	return &ExecCollector{
+		name:     name,
+		command:  command,
+		interval: interval,
+		timeout:  timeout,
+	}
+}
+
+func (c *ExecCollector) Name() string {
+	return c.name
+}
+
+func (c *ExecCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *ExecCollector) Collect(ctx context.Context) ([]types.Metrics, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, c.command[0], c.command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrCommandTimeout, c.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	return parseMetricLines(&stdout)
+}
+
+type FileCollector struct {
+	name     string
+	path     string
+	interval time.Duration
+}
+
+func NewFileCollector(name string, path string, interval time.Duration) *FileCollector {
+	return &FileCollector{
+		name:     name,
+		path:     path,
+		interval: interval,
+	}
+}
+
+func (c *FileCollector) Name() string {
+	return c.name
+}
+
+func (c *FileCollector) Interval() time.Duration {
+	return c.interval
+}
+
+func (c *FileCollector) Collect(ctx context.Context) ([]types.Metrics, error) {
+	file, err := os.Open(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseMetricLines(file)
+}
+
+func parseMetricLines(r io.Reader) ([]types.Metrics, error) {
+	var (
+		metrics []types.Metrics
+		errs    []error
+	)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			errs = append(errs, fmt.Errorf("%w %d: expected \"type name value\"", ErrInvalidMetricLine, lineNum))
+			continue
+		}
+
+		if err := validators.ValidateMetricPath(fields[0], fields[1], fields[2]); err != nil {
+			errs = append(errs, fmt.Errorf("%w %d: %v", ErrInvalidMetricLine, lineNum, err))
+			continue
+		}
+
+		metrics = append(metrics, *types.NewMetrics(fields[0], fields[1], fields[2]))
+	}
+
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return metrics, errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package workers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetricLines(t *testing.T) {
+	input := strings.Join([]string{
+		"# queue stats",
+		"gauge QueueLength 12.5",
+		"",
+		"counter Processed 3",
+		"gauge Broken",
+		"counter Bad 1.5",
+		"histogram Latency 1",
+	}, "\n")
+
+	metrics, err := parseMetricLines(strings.NewReader(input))
+
+	require.Len(t, metrics, 2)
+	assert.Equal(t, "QueueLength", metrics[0].ID)
+	assert.Equal(t, types.Gauge, metrics[0].MType)
+	assert.Equal(t, 12.5, *metrics[0].Value)
+	assert.Equal(t, "Processed", metrics[1].ID)
+	assert.Equal(t, int64(3), *metrics[1].Delta)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidMetricLine)
+	assert.Contains(t, err.Error(), "line 5")
+	assert.Contains(t, err.Error(), "line 6")
+	assert.Contains(t, err.Error(), "line 7")
+}
+
+func TestExecCollector(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c := NewExecCollector("echo", []string{"sh", "-c", "echo 'gauge Temp 36.6'; echo 'counter Jobs 2'"}, time.Second, 0)
+
+		assert.Equal(t, "echo", c.Name())
+		assert.Equal(t, time.Second, c.Interval())
+
+		metrics, err := c.Collect(context.Background())
+		require.NoError(t, err)
+		require.Len(t, metrics, 2)
+		assert.Equal(t, "Temp", metrics[0].ID)
+		assert.Equal(t, "Jobs", metrics[1].ID)
+	})
+
+	t.Run("command fails", func(t *testing.T) {
+		c := NewExecCollector("fail", []string{"sh", "-c", "echo boom >&2; exit 3"}, time.Second, time.Second)
+
+		metrics, err := c.Collect(context.Background())
+		assert.Nil(t, metrics)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c := NewExecCollector("slow", []string{"sleep", "5"}, time.Second, 100*time.Millisecond)
+
+		start := time.Now()
+		metrics, err := c.Collect(context.Background())
+		assert.Nil(t, metrics)
+		assert.ErrorIs(t, err, ErrCommandTimeout)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("missing binary", func(t *testing.T) {
+		c := NewExecCollector("missing", []string{filepath.Join(t.TempDir(), "nope")}, time.Second, time.Second)
+
+		_, err := c.Collect(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestFileCollector(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.txt")
+	require.NoError(t, os.WriteFile(path, []byte("gauge CacheHitRate 0.93\n"), 0644))
+
+	c := NewFileCollector("cache", path, 0)
+	assert.Equal(t, "cache", c.Name())
+	assert.Zero(t, c.Interval())
+
+	metrics, err := c.Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, 0.93, *metrics[0].Value)
+
+	_, err = NewFileCollector("missing", filepath.Join(t.TempDir(), "missing.txt"), 0).Collect(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"strconv"
+
+	"github.com/sbilibin2017/yp-metrics/internal/logger"
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+type systemMemoryReader func() (*mem.VirtualMemoryStat, error)
+
+type systemCPUReader func() ([]float64, error)
+
+func readSystemMemory() (*mem.VirtualMemoryStat, error) {
+	return mem.VirtualMemory()
+}
+
+func readSystemCPU() ([]float64, error) {
+	return cpu.Percent(0, true)
+}
+
+func newSystemGaugeCollector(readMemory systemMemoryReader, readCPU systemCPUReader) func() []types.Metrics {
+	return func() []types.Metrics {
+		var metrics []types.Metrics
+
+		if vm, err := readMemory(); err != nil {
+			logger.Log.Errorw("Failed to read system memory stats", "error", err)
+		} else {
+			total := float64(vm.Total)
+			free := float64(vm.Free)
+			metrics = append(metrics,
+				types.Metrics{ID: "TotalMemory", MType: types.Gauge, Value: &total},
+				types.Metrics{ID: "FreeMemory", MType: types.Gauge, Value: &free},
+			)
+		}
+
+		if percents, err := readCPU(); err != nil {
+			logger.Log.Errorw("Failed to read CPU utilization", "error", err)
+		} else {
+			for i, p := range percents {
+				value := p
+				metrics = append(metrics, types.Metrics{
+					ID:    "CPUutilization" + strconv.Itoa(i+1),
+					MType: types.Gauge,
+					Value: &value,
+				})
+			}
+		}
+
+		return metrics
+	}
+}
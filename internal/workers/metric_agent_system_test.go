@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sbilibin2017/yp-metrics/internal/types"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemGaugeCollector(t *testing.T) {
+	readMemory := func() (*mem.VirtualMemoryStat, error) {
+		return &mem.VirtualMemoryStat{Total: 1024, Free: 256}, nil
+	}
+	readCPU := func() ([]float64, error) {
+		return []float64{12.5, 50}, nil
+	}
+
+	metrics := newSystemGaugeCollector(readMemory, readCPU)()
+
+	values := make(map[string]float64)
+	for _, m := range metrics {
+		require.Equal(t, types.Gauge, m.MType)
+		require.NotNil(t, m.Value)
+		values[m.ID] = *m.Value
+	}
+
+	assert.Equal(t, map[string]float64{
+		"TotalMemory":     1024,
+		"FreeMemory":      256,
+		"CPUutilization1": 12.5,
+		"CPUutilization2": 50,
+	}, values)
+}
+
+func TestSystemGaugeCollector_Errors(t *testing.T) {
+	memErr := func() (*mem.VirtualMemoryStat, error) { return nil, errors.New("mem error") }
+	cpuErr := func() ([]float64, error) { return nil, errors.New("cpu error") }
+	memOK := func() (*mem.VirtualMemoryStat, error) { return &mem.VirtualMemoryStat{Total: 1, Free: 1}, nil }
+	cpuOK := func() ([]float64, error) { return []float64{1}, nil }
+
+	assert.Len(t, newSystemGaugeCollector(memErr, cpuOK)(), 1)
+	assert.Len(t, newSystemGaugeCollector(memOK, cpuErr)(), 2)
+	assert.Empty(t, newSystemGaugeCollector(memErr, cpuErr)())
+}
+
+func TestReadSystemStats(t *testing.T) {
+	vm, err := readSystemMemory()
+	require.NoError(t, err)
+	assert.NotZero(t, vm.Total)
+
+	percents, err := readSystemCPU()
+	require.NoError(t, err)
+	assert.NotEmpty(t, percents)
+}
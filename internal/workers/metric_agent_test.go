@@ -3,6 +3,8 @@ package workers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -51,18 +53,16 @@ func TestCollectRuntimeCounterMetrics(t *testing.T) {
 }
 
 func TestPollMetrics(t *testing.T) {
-	collector := func() []types.Metrics {
+	collector := NewFuncCollector("test", 0, func() []types.Metrics {
 		return []types.Metrics{
 			{ID: "testMetric", MType: types.Gauge, Value: float64PtrToStringPtr(123.456)},
 		}
-	}
+	})
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	pollInterval := 1
-
-	outCh := pollMetrics(ctx, pollInterval, []func() []types.Metrics{collector})
+	outCh := pollMetrics(ctx, collector, time.Second, nil)
 
 	select {
 	case metricsBatch := <-outCh:
@@ -85,6 +85,28 @@ func TestPollMetrics(t *testing.T) {
 		t.Fatal("timeout waiting for channel to close after context cancel")
 	}
 }
+
+func TestPollMetrics_IntervalReload(t *testing.T) {
+	collector := NewFuncCollector("test", 0, func() []types.Metrics {
+		return []types.Metrics{{ID: "testMetric", MType: types.Gauge, Value: float64PtrToStringPtr(1)}}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	intervalCh := make(chan int, 1)
+	outCh := pollMetrics(ctx, collector, time.Minute, intervalCh)
+
+	intervalCh <- 1
+
+	select {
+	case metricsBatch := <-outCh:
+		require.Len(t, metricsBatch, 1)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for metric after poll interval change")
+	}
+}
+
 func float64PtrToStringPtr(f float64) *float64 {
 	return &f
 }
@@ -108,7 +130,7 @@ func TestReportMetrics(t *testing.T) {
 	// Expect one call with both metrics combined
 	mockUpdater.EXPECT().Updates(gomock.Any(), []types.Metrics{firstMetric, secondMetric}).Return(nil).Times(1)
 
-	outCh := reportMetrics(ctx, mockUpdater, reportInterval, in)
+	outCh := reportMetrics(ctx, mockUpdater, reportInterval, nil, 1, in)
 
 	in <- []types.Metrics{firstMetric}
 	in <- []types.Metrics{secondMetric}
@@ -157,7 +179,7 @@ func TestReportMetrics_TickerCase(t *testing.T) {
 	// Expect a single batch update call with all metrics
 	mockUpdater.EXPECT().Updates(gomock.Any(), metrics).Return(nil).Times(1)
 
-	outCh := reportMetrics(ctx, mockUpdater, reportInterval, in)
+	outCh := reportMetrics(ctx, mockUpdater, reportInterval, nil, 1, in)
 
 	// Send metrics as a batch
 	in <- metrics
@@ -201,14 +223,11 @@ doneReading:
 }
 
 func TestLogResults(t *testing.T) {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	results := make(chan metricsUpdateResult)
 
 	done := make(chan struct{})
 	go func() {
-		logResults(ctx, results)
+		logResults(results)
 		close(done)
 	}()
 
@@ -239,23 +258,132 @@ func TestLogResults(t *testing.T) {
 	<-done
 }
 
-func TestLogResults_ContextDone(t *testing.T) {
+func TestReportMetrics_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUpdater := NewMockMetricsUpdater(ctrl)
+
 	ctx, cancel := context.WithCancel(context.Background())
-	results := make(chan metricsUpdateResult)
+	defer cancel()
 
-	done := make(chan struct{})
-	go func() {
-		logResults(ctx, results)
-		close(done)
-	}()
+	const rateLimit = 2
+
+	var inFlight, maxInFlight atomic.Int32
+	release := make(chan struct{})
+
+	mockUpdater.EXPECT().Updates(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req []types.Metrics) error {
+			n := inFlight.Add(1)
+			for {
+				cur := maxInFlight.Load()
+				if n <= cur || maxInFlight.CompareAndSwap(cur, n) {
+					break
+				}
+			}
+			<-release
+			inFlight.Add(-1)
+			return nil
+		},
+	).AnyTimes()
+
+	in := make(chan []types.Metrics)
+	outCh := reportMetrics(ctx, mockUpdater, 1, nil, rateLimit, in)
+
+	// Каждый тик отдаёт новую пачку, пока все отправители заняты
+	for i := 0; i < 4; i++ {
+		in <- []types.Metrics{{ID: fmt.Sprintf("metric%d", i), MType: types.Gauge, Value: float64PtrToStringPtr(float64(i))}}
+		time.Sleep(1100 * time.Millisecond)
+	}
+
+	assert.Equal(t, int32(rateLimit), maxInFlight.Load())
+
+	close(release)
+	close(in)
+
+	counts := 0
+	for res := range outCh {
+		require.NoError(t, res.Err)
+		counts += len(res.Request)
+	}
+
+	assert.Equal(t, 4, counts)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(rateLimit))
+}
+
+func TestReportMetrics_DrainOnContextDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUpdater := NewMockMetricsUpdater(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	metrics := []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: float64PtrToStringPtr(1)}}
+
+	mockUpdater.EXPECT().Updates(gomock.Any(), metrics).DoAndReturn(
+		func(ctx context.Context, req []types.Metrics) error {
+			return ctx.Err()
+		},
+	).Times(1)
+
+	in := make(chan []types.Metrics)
+	outCh := reportMetrics(ctx, mockUpdater, 60, nil, 3, in)
+
+	in <- metrics
+	cancel()
+
+	select {
+	case res := <-outCh:
+		require.NoError(t, res.Err)
+		assert.Equal(t, metrics, res.Request)
+	case <-time.After(time.Second):
+		t.Fatal("pending batch was not sent after context cancel")
+	}
+
+	_, ok := <-outCh
+	assert.False(t, ok, "expected result channel to be closed after drain")
+}
+
+func TestReportMetrics_DrainTimeoutWithBusyWorkers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	timeout := reportDrainTimeout
+	reportDrainTimeout = 200 * time.Millisecond
+	defer func() { reportDrainTimeout = timeout }()
 
+	mockUpdater := NewMockMetricsUpdater(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan struct{})
+	mockUpdater.EXPECT().Updates(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, req []types.Metrics) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	).Times(1)
+
+	in := make(chan []types.Metrics)
+	outCh := reportMetrics(ctx, mockUpdater, 1, nil, 1, in)
+
+	// единственный отправитель завис, а в буфере осталась следующая пачка
+	in <- []types.Metrics{{ID: "metric1", MType: types.Gauge, Value: float64PtrToStringPtr(1)}}
+	<-started
+	in <- []types.Metrics{{ID: "metric2", MType: types.Gauge, Value: float64PtrToStringPtr(2)}}
 	cancel()
 
 	select {
-	case <-done:
-	case <-time.After(100 * time.Millisecond):
-		t.Fatal("logResults did not stop after context was canceled")
+	case res := <-outCh:
+		assert.ErrorIs(t, res.Err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("drain timeout was not applied while workers were busy")
 	}
+
+	_, ok := <-outCh
+	assert.False(t, ok, "expected result channel to be closed after drain")
 }
 
 func TestStartMetricAgentWorker(t *testing.T) {
@@ -276,7 +404,9 @@ func TestStartMetricAgentWorker(t *testing.T) {
 	pollInterval := 1
 	reportInterval := 1
 
-	go StartMetricAgentWorker(ctx, mockUpdater, pollInterval, reportInterval)
+	collectors := []Collector{NewRuntimeCollector(), NewSystemCollector()}
+
+	go StartMetricAgentWorker(ctx, mockUpdater, collectors, pollInterval, reportInterval, 2, nil, nil)
 
 	time.Sleep(1500 * time.Millisecond)
 
@@ -284,3 +414,165 @@ func TestStartMetricAgentWorker(t *testing.T) {
 
 	time.Sleep(500 * time.Millisecond)
 }
+
+func TestAggregateMetrics(t *testing.T) {
+	first := int64(1)
+	second := int64(2)
+
+	input := []types.Metrics{
+		{ID: "PollCount", MType: types.Counter, Delta: &first},
+		{ID: "Alloc", MType: types.Gauge, Value: float64PtrToStringPtr(10)},
+		{ID: "PollCount", MType: types.Counter, Delta: &second},
+		{ID: "Alloc", MType: types.Gauge, Value: float64PtrToStringPtr(20)},
+		{ID: "PollCount", MType: types.Counter, Delta: int64Ptr(3)},
+	}
+
+	result := aggregateMetrics(input)
+
+	require.Len(t, result, 2)
+
+	assert.Equal(t, "PollCount", result[0].ID)
+	require.NotNil(t, result[0].Delta)
+	assert.Equal(t, int64(6), *result[0].Delta)
+
+	assert.Equal(t, "Alloc", result[1].ID)
+	require.NotNil(t, result[1].Value)
+	assert.Equal(t, 20.0, *result[1].Value)
+
+	// исходные значения не должны изменяться
+	assert.Equal(t, int64(1), first)
+	assert.Equal(t, int64(2), second)
+}
+
+func TestAggregateMetrics_SameIDDifferentTypes(t *testing.T) {
+	input := []types.Metrics{
+		{ID: "metric", MType: types.Counter, Delta: int64Ptr(1)},
+		{ID: "metric", MType: types.Gauge, Value: float64PtrToStringPtr(5)},
+		{ID: "metric", MType: types.Counter, Delta: int64Ptr(4)},
+	}
+
+	result := aggregateMetrics(input)
+
+	require.Len(t, result, 2)
+	assert.Equal(t, int64(5), *result[0].Delta)
+	assert.Equal(t, 5.0, *result[1].Value)
+}
+
+func TestAggregateMetrics_SummaryAndSet(t *testing.T) {
+	members := []string{"alice", "bob"}
+	input := []types.Metrics{
+		{ID: "latency", MType: types.Summary, Value: float64PtrToStringPtr(10)},
+		{ID: "users", MType: types.Set, Members: members},
+		{ID: "latency", MType: types.Summary, Value: float64PtrToStringPtr(30)},
+		{ID: "users", MType: types.Set, Members: []string{"bob", "carol"}},
+	}
+
+	result := aggregateMetrics(input)
+
+	require.Len(t, result, 3)
+
+	// все наблюдения summary должны сохраниться
+	assert.Equal(t, 10.0, *result[0].Value)
+	assert.Equal(t, 30.0, *result[2].Value)
+
+	// элементы множества объединяются без повторов
+	assert.Equal(t, []string{"alice", "bob", "carol"}, result[1].Members)
+	assert.Equal(t, []string{"alice", "bob"}, members)
+}
+
+func TestAggregateMetrics_Histogram(t *testing.T) {
+	first := types.HistogramValue{Bounds: []float64{1, 5}, Counts: []int64{1, 0, 2}}
+	second := types.HistogramValue{Bounds: []float64{1, 5}, Counts: []int64{0, 3, 1}}
+	changed := types.HistogramValue{Bounds: []float64{2}, Counts: []int64{4, 4}}
+
+	result := aggregateMetrics([]types.Metrics{
+		{ID: "duration", MType: types.Histogram, Histogram: &first},
+		{ID: "duration", MType: types.Histogram, Histogram: &second},
+	})
+
+	require.Len(t, result, 1)
+	require.NotNil(t, result[0].Histogram)
+	assert.Equal(t, []int64{1, 3, 3}, result[0].Histogram.Counts)
+	assert.Equal(t, []int64{1, 0, 2}, first.Counts)
+
+	// при смене границ остаётся последнее значение
+	result = aggregateMetrics([]types.Metrics{
+		{ID: "duration", MType: types.Histogram, Histogram: &first},
+		{ID: "duration", MType: types.Histogram, Histogram: &changed},
+	})
+
+	require.Len(t, result, 1)
+	assert.Equal(t, changed, *result[0].Histogram)
+}
+
+func TestReportMetrics_Aggregates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUpdater := NewMockMetricsUpdater(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expected := []types.Metrics{
+		{ID: "PollCount", MType: types.Counter, Delta: int64Ptr(3)},
+		{ID: "Alloc", MType: types.Gauge, Value: float64PtrToStringPtr(2)},
+	}
+	mockUpdater.EXPECT().Updates(gomock.Any(), expected).Return(nil).Times(1)
+
+	in := make(chan []types.Metrics)
+	outCh := reportMetrics(ctx, mockUpdater, 60, nil, 1, in)
+
+	in <- []types.Metrics{
+		{ID: "PollCount", MType: types.Counter, Delta: int64Ptr(1)},
+		{ID: "Alloc", MType: types.Gauge, Value: float64PtrToStringPtr(1)},
+	}
+	in <- []types.Metrics{
+		{ID: "PollCount", MType: types.Counter, Delta: int64Ptr(2)},
+		{ID: "Alloc", MType: types.Gauge, Value: float64PtrToStringPtr(2)},
+	}
+	close(in)
+
+	for res := range outCh {
+		require.NoError(t, res.Err)
+	}
+}
+
+func TestMergeMetrics(t *testing.T) {
+	a := make(chan []types.Metrics, 1)
+	b := make(chan []types.Metrics, 1)
+
+	a <- []types.Metrics{{ID: "a", MType: types.Gauge}}
+	b <- []types.Metrics{{ID: "b", MType: types.Gauge}}
+	close(a)
+	close(b)
+
+	var ids []string
+	for batch := range mergeMetrics(a, b) {
+		for _, m := range batch {
+			ids = append(ids, m.ID)
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"a", "b"}, ids)
+}
+
+func TestFanOutIntervals(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Equal(t, []<-chan int{nil, nil}, fanOutIntervals(ctx, nil, 2))
+
+	in := make(chan int)
+	outs := fanOutIntervals(ctx, in, 2)
+	in <- 5
+
+	for _, out := range outs {
+		select {
+		case v := <-out:
+			assert.Equal(t, 5, v)
+		case <-time.After(time.Second):
+			t.Fatal("interval was not fanned out")
+		}
+	}
+}
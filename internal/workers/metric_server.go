@@ -32,32 +32,52 @@ func StartMetricServerWorker(
 	fl MetricsFileLister,
 	storeInterval int,
 	restore bool,
+	storeIntervalCh <-chan int,
 ) {
 	if restore {
 		logger.Log.Info("Restoring metrics from file...")
 		loadMetricsFromFile(ctx, fl, ms)
 	}
 
-	if storeInterval == 0 {
-		logger.Log.Info("storeInterval = 0, saving metrics on shutdown only.")
-		<-ctx.Done()
-		saveMetricsToFile(ctx, ml, fs)
-		return
-	} else {
-		logger.Log.Infof("Starting periodic saving every %d seconds", storeInterval)
-		ticker := time.NewTicker(time.Duration(storeInterval) * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				logger.Log.Info("Context canceled, saving metrics before shutdown...")
-				saveMetricsToFile(ctx, ml, fs)
-				return
-			case <-ticker.C:
-				logger.Log.Debug("Timer tick: saving metrics to file...")
-				saveMetricsToFile(ctx, ml, fs)
-			}
+	var (
+		ticker *time.Ticker
+		tickCh <-chan time.Time
+	)
+
+	setInterval := func(interval int) {
+		if ticker != nil {
+			ticker.Stop()
+			ticker, tickCh = nil, nil
+		}
+
+		if interval == 0 {
+			logger.Log.Info("storeInterval = 0, saving metrics on shutdown only.")
+			return
+		}
+
+		logger.Log.Infof("Starting periodic saving every %d seconds", interval)
+		ticker = time.NewTicker(time.Duration(interval) * time.Second)
+		tickCh = ticker.C
+	}
+
+	setInterval(storeInterval)
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Log.Info("Context canceled, saving metrics before shutdown...")
+			saveMetricsToFile(ctx, ml, fs)
+			return
+		case interval := <-storeIntervalCh:
+			setInterval(interval)
+		case <-tickCh:
+			logger.Log.Debug("Timer tick: saving metrics to file...")
+			saveMetricsToFile(ctx, ml, fs)
 		}
 	}
 }
@@ -107,7 +107,7 @@ func TestMetricServerWorker_RestoreAndInterval(t *testing.T) {
 
 	go func() {
 
-		StartMetricServerWorker(ctx, ms, fs, ml, fl, 1, true)
+		StartMetricServerWorker(ctx, ms, fs, ml, fl, 1, true, nil)
 	}()
 
 	time.Sleep(1500 * time.Millisecond)
@@ -135,5 +135,52 @@ func TestMetricServerWorker_StoreIntervalZero(t *testing.T) {
 		cancel()
 	}()
 
-	StartMetricServerWorker(ctx, ms, fs, ml, fl, 0, false)
+	StartMetricServerWorker(ctx, ms, fs, ml, fl, 0, false, nil)
+}
+
+func TestMetricServerWorker_StoreIntervalReload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ms := NewMockMetricsMemorySaver(ctrl)
+	fs := NewMockMetricsFileSaver(ctrl)
+	ml := NewMockMetricsMemoryLister(ctrl)
+	fl := NewMockMetricsFileLister(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockMetric := types.Metrics{ID: "cpu", MType: "counter", Delta: ptrInt64(42)}
+
+	saved := make(chan struct{}, 10)
+	ml.EXPECT().List(gomock.Any()).Return([]types.Metrics{mockMetric}, nil).AnyTimes()
+	fs.EXPECT().Save(gomock.Any(), mockMetric).DoAndReturn(func(ctx context.Context, m types.Metrics) error {
+		saved <- struct{}{}
+		return nil
+	}).AnyTimes()
+
+	intervalCh := make(chan int, 1)
+	done := make(chan struct{})
+	go func() {
+		StartMetricServerWorker(ctx, ms, fs, ml, fl, 0, false, intervalCh)
+		close(done)
+	}()
+
+	// С нулевым интервалом периодического сохранения нет
+	select {
+	case <-saved:
+		t.Fatal("unexpected save before interval change")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	intervalCh <- 1
+
+	select {
+	case <-saved:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected periodic save after interval change")
+	}
+
+	cancel()
+	<-done
 }
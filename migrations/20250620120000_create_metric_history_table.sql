@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE content.metric_history (
+    id TEXT NOT NULL,
+    mtype TEXT NOT NULL,
+    ts TIMESTAMPTZ NOT NULL,
+    delta BIGINT,
+    value DOUBLE PRECISION
+);
+
+CREATE INDEX metric_history_id_mtype_ts_idx ON content.metric_history (id, mtype, ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS content.metric_history;
+-- +goose StatementEnd
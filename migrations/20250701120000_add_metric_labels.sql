@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE content.metrics ADD COLUMN labels TEXT NOT NULL DEFAULT '';
+ALTER TABLE content.metrics DROP CONSTRAINT metrics_pkey;
+ALTER TABLE content.metrics ADD PRIMARY KEY (id, mtype, labels);
+
+ALTER TABLE content.metric_history ADD COLUMN labels TEXT NOT NULL DEFAULT '';
+DROP INDEX IF EXISTS content.metric_history_id_mtype_ts_idx;
+CREATE INDEX metric_history_id_mtype_labels_ts_idx ON content.metric_history (id, mtype, labels, ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS content.metric_history_id_mtype_labels_ts_idx;
+DELETE FROM content.metric_history WHERE labels <> '';
+ALTER TABLE content.metric_history DROP COLUMN labels;
+CREATE INDEX metric_history_id_mtype_ts_idx ON content.metric_history (id, mtype, ts);
+
+DELETE FROM content.metrics WHERE labels <> '';
+ALTER TABLE content.metrics DROP CONSTRAINT metrics_pkey;
+ALTER TABLE content.metrics DROP COLUMN labels;
+ALTER TABLE content.metrics ADD PRIMARY KEY (id, mtype);
+-- +goose StatementEnd
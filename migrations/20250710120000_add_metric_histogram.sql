@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE content.metrics ADD COLUMN histogram JSONB;
+ALTER TABLE content.metric_history ADD COLUMN histogram JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM content.metric_history WHERE mtype = 'histogram';
+ALTER TABLE content.metric_history DROP COLUMN histogram;
+
+DELETE FROM content.metrics WHERE mtype = 'histogram';
+ALTER TABLE content.metrics DROP COLUMN histogram;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE content.metrics ADD COLUMN summary JSONB;
+ALTER TABLE content.metric_history ADD COLUMN summary JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM content.metric_history WHERE mtype = 'summary';
+ALTER TABLE content.metric_history DROP COLUMN summary;
+
+DELETE FROM content.metrics WHERE mtype = 'summary';
+ALTER TABLE content.metrics DROP COLUMN summary;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE content.metrics ADD COLUMN "set" JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM content.metrics WHERE mtype = 'set';
+ALTER TABLE content.metrics DROP COLUMN "set";
+-- +goose StatementEnd